@@ -13,11 +13,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/protobankbankc/auth-service/internal/cache"
 	"github.com/protobankbankc/auth-service/internal/config"
+	"github.com/protobankbankc/auth-service/internal/geoip"
 	"github.com/protobankbankc/auth-service/internal/handlers"
 	"github.com/protobankbankc/auth-service/internal/middleware"
+	"github.com/protobankbankc/auth-service/internal/models"
 	"github.com/protobankbankc/auth-service/internal/repository"
 	"github.com/protobankbankc/auth-service/internal/services"
+	"github.com/protobankbankc/auth-service/internal/startup"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
@@ -25,7 +31,7 @@ const version = "1.0.0"
 
 func main() {
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -35,42 +41,223 @@ func main() {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 
+	// Log effective configuration (secrets redacted)
+	log.Printf("Loaded configuration: %s", cfg.Redacted())
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Initialize logger
+	logger := middleware.NewLogger(cfg.Environment)
+
+	// Wait for dependencies that may still be starting (bounded by the retry policy)
+	retryPolicy := startup.RetryPolicy{
+		MaxAttempts:    cfg.StartupMaxAttempts,
+		InitialBackoff: cfg.StartupInitialBackoff,
+		MaxBackoff:     cfg.StartupMaxBackoff,
+	}
+
 	// Initialize database connection
-	dbPool, err := initDatabase(cfg)
+	var dbPool *pgxpool.Pool
+	err = startup.WaitFor(context.Background(), logger, "postgres", retryPolicy, func(ctx context.Context) error {
+		pool, err := initDatabase(cfg)
+		if err != nil {
+			return err
+		}
+		dbPool = pool
+		return nil
+	})
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer dbPool.Close()
 
+	// Initialize Redis connection
+	var redisClient *redis.Client
+	err = startup.WaitFor(context.Background(), logger, "redis", retryPolicy, func(ctx context.Context) error {
+		client, err := initRedis(cfg)
+		if err != nil {
+			return err
+		}
+		redisClient = client
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize Redis: %v", err)
+	}
+	defer redisClient.Close()
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(dbPool)
+	txRetry := repository.WithTxRetry(repository.TxRetryConfig{
+		MaxAttempts: cfg.DBTxMaxAttempts,
+		Backoff:     cfg.DBTxRetryBackoff,
+	})
+
+	// Optional service features
+	tokenBinding, err := services.ParseTokenBindingMode(cfg.TokenBindingMode)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	duplicateIdentity, err := services.ParseDuplicateIdentityMode(cfg.DuplicateIdentityMode)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	postcodeCheck, err := services.ParsePostcodeCheckMode(cfg.PostcodeCheckMode)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	passwordHasher, err := utils.NewPasswordHashRegistry(cfg.PasswordHashScheme)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// RS256 access tokens can be verified by other services with the public key alone
+	var accessRSAKeys *utils.RSAKeyPair
+	var retiredRSAKeys []*utils.RSAKeyPair
+	if cfg.JWTAlgorithm == utils.AlgorithmRS256 {
+		accessRSAKeys, err = utils.LoadRSAKeyPair(cfg.JWTPrivateKeyPath, cfg.JWTPublicKeyPath)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+		for _, path := range cfg.JWTRetiredPublicKeyPaths {
+			retired, err := utils.LoadRSAPublicKey(path)
+			if err != nil {
+				log.Fatalf("Failed to load retired JWT public key %s: %v", path, err)
+			}
+			retiredRSAKeys = append(retiredRSAKeys, retired)
+		}
+	}
+	accessTokenKey := utils.SigningKeys{Access: cfg.JWTSecret, AccessRSA: accessRSAKeys, RetiredAccessRSA: retiredRSAKeys}.KeyFor("access")
+
+	serviceOptions := []services.Option{
+		services.WithLogger(logger),
+		services.WithTokenBinding(tokenBinding),
+		services.WithMinimalClaims(cfg.JWTMinimalClaims),
+		services.WithAccessTokenJitter(cfg.JWTExpiryJitter, cfg.JWTExpiryJitterMax),
+		services.WithExpiredTokenGrace(cfg.JWTExpiryGrace),
+		services.WithRefreshTokenSecret(cfg.JWTRefreshSecret),
+		services.WithAccessTokenRSAKey(accessRSAKeys),
+		services.WithRetiredAccessTokenRSAKeys(retiredRSAKeys),
+		services.WithDuplicateIdentityCheck(duplicateIdentity),
+		services.WithPostcodeCheck(postcodeCheck),
+		services.WithPasswordHashing(passwordHasher),
+		services.WithPasswordHashConcurrency(cfg.PasswordHashConcurrency, cfg.PasswordHashQueueTimeout),
+		services.WithPasswordPolicy(models.PasswordPolicy{
+			MinLength:           cfg.PasswordPolicy.MinLength,
+			MaxBytes:            cfg.PasswordPolicy.MaxBytes,
+			RequireUppercase:    cfg.PasswordPolicy.RequireUppercase,
+			RequireLowercase:    cfg.PasswordPolicy.RequireLowercase,
+			RequireDigit:        cfg.PasswordPolicy.RequireDigit,
+			RequireSpecial:      cfg.PasswordPolicy.RequireSpecial,
+			MinUniqueChars:      cfg.PasswordPolicy.MinUniqueChars,
+			CommonPasswordCheck: true,
+		}),
+		services.WithRefreshTokens(cfg.RefreshTokensEnabled),
+		services.WithRememberMe(cfg.RememberMeExpiry),
+		services.WithRegistrationLimit(cache.NewRedisCounter(redisClient, "auth:"), cfg.RegistrationsPerIPPerDay),
+		services.WithTokenRevocation(cache.NewRedisCounter(redisClient, "auth:")),
+		services.WithClosureCoolingOff(cfg.AccountClosureCoolingOff),
+		services.WithNamePolicy(services.NamePolicy{
+			MinLength:          cfg.NameMinLength,
+			MaxLength:          cfg.NameMaxLength,
+			RestrictCharacters: cfg.NameRestrictCharacters,
+		}),
+	}
+
+	if cfg.RefreshTokenRotationEnabled {
+		serviceOptions = append(serviceOptions,
+			services.WithRefreshTokenRotation(repository.NewRefreshTokenRepository(dbPool, txRetry)),
+			services.WithRefreshReuseGrace(cfg.RefreshTokenReuseGrace),
+		)
+	}
+
+	if cfg.LoginAuditEnabled {
+		var auditRepo repository.AuditRepository = repository.NewAuditRepository(dbPool, txRetry)
+		if cfg.AuditBatchSize > 0 {
+			batchedAudit := repository.NewBatchedAuditRepository(repository.NewAuditRepository(dbPool, txRetry), repository.AuditBatchConfig{
+				BatchSize:     cfg.AuditBatchSize,
+				FlushInterval: cfg.AuditFlushInterval,
+				BufferSize:    cfg.AuditBufferSize,
+			}, logger)
+			defer closeAuditWriter(batchedAudit)
+			auditRepo = batchedAudit
+		}
+		serviceOptions = append(serviceOptions, services.WithAuditRepository(auditRepo))
+
+		geoResolver, err := geoip.Open(cfg.GeoIPCountryDBPath, cfg.GeoIPASNDBPath)
+		if err != nil {
+			log.Fatalf("Failed to open GeoIP database: %v", err)
+		}
+		if geoResolver != nil {
+			defer geoResolver.Close()
+			serviceOptions = append(serviceOptions, services.WithGeoResolver(geoResolver))
+		} else {
+			log.Println("GeoIP databases not found, login audit enrichment disabled")
+		}
+	}
+
+	if cfg.SessionTrackingEnabled {
+		serviceOptions = append(serviceOptions,
+			services.WithSessions(repository.NewSessionRepository(dbPool)),
+			services.WithSessionIdleTimeout(cfg.IdleSessionTimeout),
+		)
+	}
+	if cfg.UniqueDeviceBindingEnabled {
+		serviceOptions = append(serviceOptions, services.WithDeviceBindings(repository.NewDeviceBindingRepository(dbPool)))
+	}
+	if cfg.EmailVerificationEnabled {
+		// There is no email delivery yet, so tokens are logged (never in production, see Config.Validate)
+		serviceOptions = append(serviceOptions,
+			services.WithEmailVerification(repository.NewEmailVerificationRepository(dbPool), services.NewLogVerificationSender(logger), cfg.EmailVerificationTTL),
+			services.WithRequireEmailVerification(cfg.RequireEmailVerification),
+		)
+	}
 
 	// Initialize services
 	authService := services.NewAuthService(
 		userRepo,
 		cfg.JWTSecret,
-		cfg.AccessTokenDuration,
-		cfg.RefreshTokenDuration,
+		cfg.JWTExpiry,
+		cfg.RefreshTokenExpiry,
+		serviceOptions...,
 	)
 
+	// Finalize account closures once their cooling-off period ends
+	if cfg.AccountClosureEnabled {
+		closureCtx, stopClosures := context.WithCancel(context.Background())
+		defer stopClosures()
+		go authService.RunClosureFinalizer(closureCtx, cfg.AccountClosureSweepInterval)
+	}
+
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	healthHandler := handlers.NewHealthHandler(version)
+	authHandler := handlers.NewAuthHandler(authService,
+		handlers.WithTokenClaimsInMe(cfg.MeExposeTokenClaims),
+		handlers.WithGenericRegistrationResponse(cfg.RegisterGenericResponse),
+	)
+	// Redis backs rate limits and registration caps; without it core auth still works, degraded
+	healthHandler := handlers.NewHealthHandler(version,
+		handlers.WithReadinessCheck(dbPool, cfg.ReadinessCacheTTL),
+		handlers.WithOptionalDependency("redis", cache.NewRedisCounter(redisClient, "auth:"), cfg.ReadinessCacheTTL),
+	)
 
-	// Initialize logger
-	logger := middleware.NewLogger(cfg.Environment)
+	// Rate limit counts are kept in memory unless a shared store is configured
+	var rateLimitStore middleware.RateLimitCounter
+	if cfg.RateLimitStore == "redis" {
+		rateLimitStore = cache.NewRedisCounter(redisClient, "auth:")
+	}
 
 	// Setup router
-	router := setupRouter(cfg, authHandler, healthHandler, logger)
+	router := setupRouter(cfg, authService, authHandler, healthHandler, rateLimitStore, accessTokenKey, logger)
 
 	// Create server
 	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", cfg.ServerPort),
+		Addr:           ":" + cfg.ServicePort,
 		Handler:        router,
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
@@ -80,7 +267,7 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting Auth Service v%s on port %d (environment: %s)", version, cfg.ServerPort, cfg.Environment)
+		log.Printf("Starting Auth Service v%s on port %s (environment: %s)", version, cfg.ServicePort, cfg.Environment)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -104,24 +291,23 @@ func main() {
 	log.Println("Server stopped successfully")
 }
 
+// closeAuditWriter flushes queued audit events on shutdown
+func closeAuditWriter(writer *repository.BatchedAuditRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := writer.Close(ctx); err != nil {
+		log.Printf("Failed to flush audit events: %v", err)
+	}
+}
+
 // initDatabase initializes the database connection pool
 func initDatabase(cfg *config.Config) (*pgxpool.Pool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Build connection string
-	connString := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.DBHost,
-		cfg.DBPort,
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBName,
-		cfg.DBSSLMode,
-	)
-
 	// Parse config
-	poolConfig, err := pgxpool.ParseConfig(connString)
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
@@ -141,6 +327,7 @@ func initDatabase(cfg *config.Config) (*pgxpool.Pool, error) {
 
 	// Test connection
 	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -149,18 +336,66 @@ func initDatabase(cfg *config.Config) (*pgxpool.Pool, error) {
 }
 
 // setupRouter configures the HTTP router with all routes and middleware
-func setupRouter(cfg *config.Config, authHandler *handlers.AuthHandler, healthHandler *handlers.HealthHandler, logger interface{}) *gin.Engine {
+func setupRouter(cfg *config.Config, authService middleware.AuthService, authHandler *handlers.AuthHandler, healthHandler *handlers.HealthHandler, rateLimitStore middleware.RateLimitCounter, accessTokenKey utils.SigningKey, logger interface{}) *gin.Engine {
 	router := gin.New()
 
 	// Recovery middleware (must be first)
-	router.Use(gin.Recovery())
+	router.Use(middleware.Recovery(logger.(*logrus.Logger)))
 
 	// Structured logging middleware
 	router.Use(middleware.Logger(logger.(*logrus.Logger)))
 
+	// Proxies whose X-Forwarded-For identifies the client for auditing and rate limiting
+	trustedProxies, err := middleware.NewIPAllowlist(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
+	// Client metadata (IP, user agent, request ID) for auditing
+	router.Use(middleware.RequestInfo(trustedProxies))
+
+	// Debug request/response body logging (opt-in, redacted)
+	if cfg.DebugBodyLoggingEnabled {
+		bodyLoggerConfig := middleware.DefaultBodyLoggerConfig()
+		bodyLoggerConfig.Paths = cfg.DebugBodyLoggingPaths
+		bodyLoggerConfig.MaxBodyBytes = cfg.DebugBodyLoggingMaxBytes
+		bodyLoggerConfig.LogHeaders = cfg.DebugBodyLoggingHeaders
+		bodyLoggerConfig.Headers = middleware.NewHeaderRedactor(cfg.LogRedactHeaders...)
+		router.Use(middleware.BodyLogger(logger.(*logrus.Logger), bodyLoggerConfig))
+	}
+
+	// HMAC response signing for partner integrations (opt-in per path)
+	if len(cfg.ResponseSigningPaths) > 0 {
+		router.Use(middleware.ResponseSigning(&middleware.ResponseSigningConfig{
+			Paths:   cfg.ResponseSigningPaths,
+			Secrets: cfg.ResponseSigningSecrets,
+		}))
+	}
+
+	// Reject or redirect plaintext requests (behind a TLS-terminating proxy)
+	httpsMode, err := middleware.ParseHTTPSMode(cfg.HTTPSMode)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if httpsMode != middleware.HTTPSOff {
+		httpsProxies, err := middleware.NewIPAllowlist(cfg.HTTPSTrustedProxies)
+		if err != nil {
+			log.Fatalf("Invalid HTTPS trusted proxies: %v", err)
+		}
+		httpsConfig := middleware.DefaultHTTPSConfig()
+		httpsConfig.Mode = httpsMode
+		httpsConfig.TrustedProxies = httpsProxies
+		router.Use(middleware.RequireHTTPS(httpsConfig))
+	}
+
 	// Prometheus metrics middleware
 	router.Use(middleware.Metrics())
 
+	// Per-request feature flag overrides for QA, never installed in production
+	if cfg.FeatureOverridesSecret != "" && cfg.Environment != "production" {
+		router.Use(middleware.FeatureOverrides(cfg.Environment, cfg.FeatureOverridesSecret))
+	}
+
 	// CORS middleware
 	var corsConfig *middleware.CORSConfig
 	if cfg.Environment == "production" {
@@ -173,17 +408,77 @@ func setupRouter(cfg *config.Config, authHandler *handlers.AuthHandler, healthHa
 		// Development: allow all origins
 		corsConfig = middleware.DefaultCORSConfig()
 	}
-	router.Use(middleware.CORS(corsConfig))
 
-	// Rate limiting middleware (10 requests per minute per IP)
-	rateLimiter := middleware.NewRateLimiter(10, time.Minute)
-	router.Use(rateLimiter.Limit())
+	// Route groups advertise only the methods they serve
+	cors := middleware.NewGroupCORS(corsConfig)
+	cors.Group("/api/v1/auth", middleware.MergeCORSConfig(corsConfig, &middleware.CORSConfig{
+		AllowMethods: []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+	}))
+	router.Use(cors.Handler())
+
+	// Rate limiting: each route group gets its own limiter (per IP, unless
+	// keyed on other dimensions), so e.g. exhausting login doesn't block refresh.
+	// Load shedding is shared, capping requests in flight across all of them.
+	rateLimiterOptions := []middleware.RateLimiterOption{
+		middleware.WithTrustedProxies(trustedProxies),
+		middleware.WithLoadShedding(cfg.RateLimitMaxInFlight, cfg.RateLimitShedRetryAfter),
+	}
+	keyDimensions, err := middleware.ParseRateLimitKeyDimensions(cfg.RateLimitKey)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if len(keyDimensions) > 0 {
+		rateLimiterOptions = append(rateLimiterOptions, middleware.WithKeyFunc(middleware.CompositeKey(keyDimensions...)))
+	}
+	rateLimitAllowlist, err := middleware.NewIPAllowlist(cfg.RateLimitAllowlist)
+	if err != nil {
+		log.Fatalf("Invalid rate limit allowlist: %v", err)
+	}
+	var failMode middleware.RateLimitFailMode
+	if rateLimitStore != nil {
+		failMode, err = middleware.ParseRateLimitFailMode(cfg.RateLimitStoreFailMode)
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+	}
+	rateLimiters := middleware.NewRateLimiters(func(rl *middleware.RateLimiter) {
+		rl.SetAllowlist(rateLimitAllowlist)
+		if rateLimitStore != nil {
+			rl.SetStore(rateLimitStore, failMode, cfg.RateLimitStoreRetryAfter, logger.(*logrus.Logger))
+		}
+	}, rateLimiterOptions...)
+	loginLimit := rateLimiters.Limiter("login", cfg.RateLimitLoginPerMinute, time.Minute).Limit()
+	registerLimit := rateLimiters.Limiter("register", cfg.RateLimitLoginPerMinute, time.Minute).Limit()
+
+	// Authenticated callers of the other routes are limited per user instead
+	readLimiter := rateLimiters.Limiter("read", cfg.RateLimitReadPerMinute, time.Minute)
+	readLimiter.SetUserLimitWithKey(cfg.UserRateLimitPerMinute, accessTokenKey)
+	readLimit := readLimiter.Limit()
+	apiLimiter := rateLimiters.Limiter("api", cfg.RateLimitRequestsPerMinute, time.Minute)
+	apiLimiter.SetUserLimitWithKey(cfg.UserRateLimitPerMinute, accessTokenKey)
+	apiLimit := apiLimiter.Limit()
+
+	// Personal data exports are expensive and sensitive, so they get a strict per-user daily limit
+	exportLimiter := rateLimiters.Limiter("data_export", cfg.DataExportsPerDay, 24*time.Hour)
+	exportLimiter.SetUserLimitWithKey(cfg.DataExportsPerDay, accessTokenKey)
+
+	// Login activity reads scan the audit trail, so they get their own limit,
+	// sharing the allowlist and store of the other named limiters
+	activityLimiter := rateLimiters.Limiter("login_activity", cfg.LoginActivityPerMinute, time.Minute)
+	activityLimiter.SetUserLimitWithKey(cfg.LoginActivityPerMinute, accessTokenKey)
+
+	// Each resend issues a token, so resends get a strict per-IP hourly limit
+	resendLimit := rateLimiters.Limiter("resend_verification", cfg.ResendVerificationPerHour, time.Hour).Limit()
 
 	// Health check routes (no auth required, no rate limiting)
 	router.GET("/health", healthHandler.Health)
 	router.GET("/ready", healthHandler.Ready)
 	router.GET("/live", healthHandler.Live)
 
+	// Public keys for verifying RS256 access tokens (empty with HS256)
+	jwksHandler := handlers.NewJWKSHandler(accessTokenKey.VerificationKeys())
+	router.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -192,14 +487,77 @@ func setupRouter(cfg *config.Config, authHandler *handlers.AuthHandler, healthHa
 	{
 		// Auth routes (public)
 		auth := v1.Group("/auth")
+		if cfg.AuthNoStoreEnabled {
+			auth.Use(middleware.NoStore())
+		}
+		if cfg.DegradedHeaderEnabled {
+			auth.Use(middleware.Degraded(healthHandler))
+		}
+		{
+			auth.POST("/register", registerLimit, authHandler.Register)
+			auth.POST("/login", loginLimit, authHandler.Login)
+			auth.POST("/refresh", apiLimit, authHandler.RefreshToken)
+			auth.GET("/password-policy", readLimit, authHandler.GetPasswordPolicy)
+			if cfg.EmailVerificationEnabled {
+				auth.POST("/verify-email", apiLimit, authHandler.VerifyEmail)
+				auth.POST("/resend-verification", resendLimit, authHandler.ResendVerification)
+			}
+		}
+
+		// Auth routes for the caller of a valid access token; handlers read
+		// the user from the context (see middleware.CurrentUser). Rate limits
+		// run before authentication, so requests with bad tokens are limited
+		// per IP before they cost a token validation and user lookup.
+		requireAuth := middleware.RequireAuth(authService)
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.POST("/logout", authHandler.Logout)
-			auth.GET("/me", authHandler.GetMe) // Requires auth header
+			auth.POST("/logout", apiLimit, requireAuth, authHandler.Logout)
+			auth.GET("/me", readLimit, requireAuth, authHandler.GetMe)
+			auth.PATCH("/me", apiLimit, requireAuth, authHandler.UpdateMe)
+			auth.GET("/me/export", apiLimit, exportLimiter.Limit(), requireAuth, authHandler.ExportMe)
+			auth.GET("/me/security", readLimit, requireAuth, authHandler.GetMySecurity)
+			auth.GET("/activity", readLimit, activityLimiter.Limit(), requireAuth, authHandler.GetLoginActivity)
+			if cfg.AccountClosureEnabled {
+				auth.POST("/me/close", apiLimit, requireAuth, authHandler.CloseMe)
+				auth.POST("/me/close/cancel", apiLimit, requireAuth, authHandler.CancelCloseMe)
+			}
+			if cfg.SessionTrackingEnabled {
+				auth.GET("/sessions", readLimit, requireAuth, authHandler.ListSessions)
+				auth.DELETE("/sessions", apiLimit, requireAuth, authHandler.RevokeOtherSessions)
+				auth.DELETE("/sessions/:id", apiLimit, requireAuth, authHandler.RevokeSession)
+			}
+		}
+
+		// Developer-only routes, never registered in production
+		if cfg.DebugEndpointsEnabled && cfg.Environment != "production" {
+			debugHandler := handlers.NewDebugHandler(cfg.Environment)
+			debug := v1.Group("/debug", apiLimit)
+			{
+				debug.POST("/decode-token", debugHandler.DecodeToken)
+			}
 		}
 	}
 
 	return router
 }
+
+// initRedis initializes the Redis client
+func initRedis(cfg *config.Config) (*redis.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	options, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(options)
+
+	// Test connection
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping Redis: %w", err)
+	}
+
+	log.Println("Redis connection established successfully")
+	return client, nil
+}
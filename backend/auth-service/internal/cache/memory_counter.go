@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCounter is an in-process counter with per-key expiry. It suits tests
+// and single-instance deployments; use RedisCounter when running replicas.
+type MemoryCounter struct {
+	mu      sync.Mutex
+	entries map[string]*counterEntry
+	now     func() time.Time
+}
+
+// counterEntry holds a count and when it expires
+type counterEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// NewMemoryCounter creates a new in-memory counter
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{
+		entries: make(map[string]*counterEntry),
+		now:     time.Now,
+	}
+}
+
+// Increment increments the counter for key and returns the new count.
+// The expiry is set when the key is first created and is not extended.
+func (m *MemoryCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	// Drop expired entries opportunistically
+	for k, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+
+	entry, exists := m.entries[key]
+	if !exists {
+		entry = &counterEntry{expiresAt: now.Add(ttl)}
+		m.entries[key] = entry
+	}
+
+	entry.count++
+	return entry.count, nil
+}
+
+// Exists reports whether key has been incremented and not yet expired
+func (m *MemoryCounter) Exists(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.entries[key]
+	return exists && m.now().Before(entry.expiresAt), nil
+}
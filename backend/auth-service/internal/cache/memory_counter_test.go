@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryCounter tests counting and expiry of the in-memory counter
+func TestMemoryCounter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	for want := int64(1); want <= 3; want++ {
+		count, err := counter.Increment(ctx, "a", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, want, count)
+	}
+
+	// Keys are independent
+	count, err := counter.Increment(ctx, "b", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Incrementing doesn't extend the expiry
+	now = now.Add(59 * time.Minute)
+	count, err = counter.Increment(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	// Expired keys start over
+	now = now.Add(time.Minute)
+	count, err = counter.Increment(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestMemoryCounterExists tests that Exists reports live keys only
+func TestMemoryCounterExists(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	counter := NewMemoryCounter()
+	counter.now = func() time.Time { return now }
+
+	exists, err := counter.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = counter.Increment(ctx, "a", time.Hour)
+	require.NoError(t, err)
+
+	exists, err = counter.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	now = now.Add(time.Hour)
+	exists, err = counter.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
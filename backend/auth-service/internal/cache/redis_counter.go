@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCounter is a counter backed by Redis, shared across service replicas
+type RedisCounter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCounter creates a new Redis-backed counter. Keys are namespaced under prefix.
+func NewRedisCounter(client *redis.Client, prefix string) *RedisCounter {
+	return &RedisCounter{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Increment increments the counter for key and returns the new count.
+// The expiry is set when the key is first created and is not extended.
+func (r *RedisCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	fullKey := r.prefix + key
+
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, fullKey)
+		pipe.ExpireNX(ctx, fullKey, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment counter: %w", err)
+	}
+
+	return incr.Val(), nil
+}
+
+// Exists reports whether key has been incremented and not yet expired
+func (r *RedisCounter) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.prefix+key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check counter: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Ping checks connectivity to Redis
+func (r *RedisCounter) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
@@ -2,6 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -12,31 +16,210 @@ type Config struct {
 	ServiceName string
 	ServicePort string
 	LogLevel    string
+	Environment string // "development", "staging" or "production"
+
+	// Developer-only endpoints such as token decoding (never served in production)
+	DebugEndpointsEnabled bool
+
+	// Signs per-request feature flag overrides for QA (empty = disabled; never in production)
+	FeatureOverridesSecret string
 
 	// Database
 	DatabaseURL string
 
+	// Retries of transactions failing with a serialization failure or
+	// deadlock (0 attempts = the default of 3)
+	DBTxMaxAttempts  int
+	DBTxRetryBackoff time.Duration
+
 	// Redis
 	RedisURL string
 
 	// JWT
-	JWTSecret           string
-	JWTExpiry           time.Duration
-	RefreshTokenExpiry  time.Duration
+	JWTSecret            string
+	JWTRefreshSecret     string // Signs refresh tokens; falls back to JWTSecret when empty
+	JWTRequireSeparate   bool   // Require JWTRefreshSecret to be set
+	RejectWeakSecrets    bool   // Reject placeholder/low-entropy secrets outside production too
+	JWTExpiry            time.Duration
+	JWTExpiryJitter      float64       // Random ± fraction applied to JWTExpiry per token (0 = disabled)
+	JWTExpiryJitterMax   time.Duration // Upper bound on the jitter (0 = fraction only)
+	JWTExpiryGrace       time.Duration // Accept access tokens this long past expiry for GET requests (0 = disabled)
+	RefreshTokenExpiry   time.Duration
+	RefreshTokensEnabled bool
+	RememberMeExpiry     time.Duration // Refresh token expiry for "remember me" logins (0 = disabled)
+
+	// Store refresh tokens and replace them on every refresh, revoking the family on reuse
+	RefreshTokenRotationEnabled bool
+	RefreshTokenReuseGrace      time.Duration // Reuse this soon after rotation is a concurrent refresh, not theft
+
+	// Access token signing: HS256 with JWTSecret, or RS256 with the RSA key pair
+	JWTAlgorithm      string
+	JWTPrivateKeyPath string // PEM RSA private key (RS256 only)
+	JWTPublicKeyPath  string // PEM RSA public key; derived from the private key when empty
+
+	// PEM RSA public keys of previous signing keys, still accepted and
+	// published in the JWKS while their tokens expire after a key rotation
+	JWTRetiredPublicKeyPaths []string
+
+	// Longest accepted access and refresh token expiries, checked at startup (0 = no maximum)
+	JWTMaxExpiry          time.Duration
+	RefreshTokenMaxExpiry time.Duration
 
 	// Security
 	BcryptCost int
 
 	// Rate Limiting
 	RateLimitEnabled           bool
-	RateLimitRequestsPerMinute int
+	RateLimitRequestsPerMinute int      // Default per-route limit for API routes
+	RateLimitLoginPerMinute    int      // Limit for login and register, each counted separately
+	RateLimitReadPerMinute     int      // Limit for read-only API routes
+	RateLimitAllowlist         []string // IPs/CIDRs exempt from rate limiting
+	UserRateLimitPerMinute     int      // Per-user limit for authenticated requests (0 = per-IP only)
+	RegistrationsPerIPPerDay   int      // Daily registration cap per client IP (0 = unlimited)
+	DataExportsPerDay          int      // Daily personal data exports per user
+	LoginActivityPerMinute     int      // Per-user requests per minute to the login activity list
+
+	// Request attributes the rate limit key is built from ("ip", "path", "user",
+	// "method"); empty limits per IP, or per user with a valid access token
+	RateLimitKey []string
+
+	// Where rate limit counts are kept: "memory" (per replica) or "redis" (shared)
+	RateLimitStore string
+	// When the Redis store is down: "open" (allow and log) or "closed" (503 with Retry-After)
+	RateLimitStoreFailMode   string
+	RateLimitStoreRetryAfter time.Duration
+
+	// Requests in flight above which the global limiter sheds load with 503 (0 = disabled)
+	RateLimitMaxInFlight    int
+	RateLimitShedRetryAfter time.Duration
 
 	// CORS
 	CORSOrigins     []string
 	CORSCredentials bool
 
+	// HTTPS enforcement: "off", "reject" (400) or "redirect" (308 for GET/HEAD)
+	HTTPSMode string
+	// TLS-terminating proxies whose X-Forwarded-Proto is trusted (IPs/CIDRs)
+	HTTPSTrustedProxies []string
+
+	// Proxies whose X-Forwarded-For is trusted to find the client IP (IPs/CIDRs)
+	TrustedProxies []string
+
 	// Session
 	SessionTimeout time.Duration
+
+	// Name validation
+	NameMinLength          int
+	NameMaxLength          int
+	NameRestrictCharacters bool
+
+	// Login audit
+	LoginAuditEnabled  bool
+	GeoIPCountryDBPath string
+	GeoIPASNDBPath     string
+
+	// Asynchronous batching of audit writes (0 batch size = write each event inline)
+	AuditBatchSize     int
+	AuditFlushInterval time.Duration
+	AuditBufferSize    int
+
+	// Self-service account closure: requests wait out the cooling-off period,
+	// then a background sweep anonymizes and closes the account
+	AccountClosureEnabled       bool
+	AccountClosureCoolingOff    time.Duration
+	AccountClosureSweepInterval time.Duration
+
+	// Track a session per refresh token so users can list and revoke them
+	SessionTrackingEnabled bool
+	IdleSessionTimeout     time.Duration // Reject a session unused for this long (0 = no idle timeout)
+
+	// Bind each device ID to the first user to register or sign in with it
+	UniqueDeviceBindingEnabled bool
+
+	// Email verification: new users get a token that verifies their email,
+	// which can be resent; login can be made to require a verified email
+	EmailVerificationEnabled  bool
+	EmailVerificationTTL      time.Duration
+	ResendVerificationPerHour int
+	RequireEmailVerification  bool
+
+	// Registrations matching an existing identity: "off", "warn", "flag" or "block"
+	DuplicateIdentityMode string
+
+	// Registrations whose postcode doesn't match the country's format: "off", "warn" or "block"
+	PostcodeCheckMode string
+
+	// Hash scheme for new passwords: "bcrypt" or "argon2id"
+	PasswordHashScheme string
+
+	// Rules new passwords must meet
+	PasswordPolicy PasswordPolicy
+
+	// Maximum concurrent password hash operations (0 = GOMAXPROCS)
+	PasswordHashConcurrency int
+
+	// How long a hash operation waits for a free slot before failing with 503 (0 = fail immediately)
+	PasswordHashQueueTimeout time.Duration
+
+	// What logins that opt in to token binding are bound to: "none", "ip" or "user_agent"
+	TokenBindingMode string
+
+	// Issue access tokens with only sub, exp, iat, jti and token_type
+	JWTMinimalClaims bool
+
+	// Include registered token claims (sub, iat, exp) in /me responses
+	MeExposeTokenClaims bool
+
+	// Answer every valid registration with the same 202 response, so the
+	// endpoint does not reveal which emails are already registered
+	RegisterGenericResponse bool
+
+	// HMAC response signing for partner integrations
+	ResponseSigningPaths   []string
+	ResponseSigningSecrets map[string]string // Partner ID -> secret
+
+	// How long a /ready dependency check result is reused
+	ReadinessCacheTTL time.Duration
+
+	// Set X-Service-Degraded on auth responses while optional subsystems (Redis) are down
+	DegradedHeaderEnabled bool
+
+	// Send Cache-Control: no-store and Pragma: no-cache on all /auth responses
+	AuthNoStoreEnabled bool
+
+	// Startup retries while waiting for Postgres and Redis
+	StartupMaxAttempts    int
+	StartupInitialBackoff time.Duration
+	StartupMaxBackoff     time.Duration
+
+	// Debug body logging (never enable in production)
+	DebugBodyLoggingEnabled  bool
+	DebugBodyLoggingPaths    []string
+	DebugBodyLoggingMaxBytes int
+	DebugBodyLoggingHeaders  bool
+
+	// Header names masked wherever headers are logged, on top of
+	// Authorization, Proxy-Authorization, Cookie, Set-Cookie and X-Api-Key
+	LogRedactHeaders []string
+}
+
+// PasswordPolicy holds the rules new passwords must meet. The defaults require
+// upper and lower case letters, a digit and a special character; turning the
+// character class rules off gives a length-only (NIST-style) policy.
+type PasswordPolicy struct {
+	// Minimum length in characters
+	MinLength int
+
+	// Maximum length in bytes of UTF-8; bcrypt ignores anything past 72
+	MaxBytes int
+
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSpecial   bool
+
+	// Minimum number of distinct characters (0 = no minimum)
+	MinUniqueChars int
 }
 
 // Load loads configuration from environment variables
@@ -46,12 +229,82 @@ func Load() (*Config, error) {
 	viper.SetDefault("SERVICE_NAME", "auth-service")
 	viper.SetDefault("SERVICE_PORT", "3001")
 	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("ENVIRONMENT", "development")
+	viper.SetDefault("DEBUG_ENDPOINTS_ENABLED", false)
 	viper.SetDefault("BCRYPT_COST", 12)
 	viper.SetDefault("JWT_EXPIRY", "15m")
+	viper.SetDefault("JWT_EXPIRY_JITTER", 0)
+	viper.SetDefault("JWT_EXPIRY_JITTER_MAX", "2m")
+	viper.SetDefault("JWT_EXPIRY_GRACE", "0s")
+	viper.SetDefault("JWT_REQUIRE_SEPARATE_REFRESH_SECRET", false)
+	viper.SetDefault("REJECT_WEAK_SECRETS", false)
 	viper.SetDefault("REFRESH_TOKEN_EXPIRY", "168h")
+	viper.SetDefault("JWT_ALGORITHM", "HS256")
+	viper.SetDefault("JWT_MAX_EXPIRY", "1h")
+	viper.SetDefault("REFRESH_TOKEN_MAX_EXPIRY", "2160h")
+	viper.SetDefault("REFRESH_TOKENS_ENABLED", true)
+	viper.SetDefault("REMEMBER_ME_REFRESH_EXPIRY", "720h")
+	viper.SetDefault("REFRESH_TOKEN_ROTATION_ENABLED", true)
+	viper.SetDefault("REFRESH_TOKEN_REUSE_GRACE", "10s")
 	viper.SetDefault("RATE_LIMIT_ENABLED", true)
-	viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 5)
+	viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 10)
+	viper.SetDefault("RATE_LIMIT_LOGIN_REQUESTS_PER_MINUTE", 5)
+	viper.SetDefault("RATE_LIMIT_READ_REQUESTS_PER_MINUTE", 60)
+	viper.SetDefault("USER_RATE_LIMIT_REQUESTS_PER_MINUTE", 60)
+	viper.SetDefault("REGISTRATIONS_PER_IP_PER_DAY", 10)
+	viper.SetDefault("DATA_EXPORTS_PER_DAY", 3)
+	viper.SetDefault("LOGIN_ACTIVITY_REQUESTS_PER_MINUTE", 20)
+	viper.SetDefault("RATE_LIMIT_STORE", "memory")
+	viper.SetDefault("RATE_LIMIT_STORE_FAIL_MODE", "open")
+	viper.SetDefault("RATE_LIMIT_STORE_RETRY_AFTER", "5s")
+	viper.SetDefault("RATE_LIMIT_MAX_IN_FLIGHT", 0)
+	viper.SetDefault("RATE_LIMIT_SHED_RETRY_AFTER", "1s")
+	viper.SetDefault("DB_TX_MAX_ATTEMPTS", 3)
+	viper.SetDefault("DB_TX_RETRY_BACKOFF", "20ms")
 	viper.SetDefault("SESSION_TIMEOUT", "30m")
+	viper.SetDefault("NAME_MIN_LENGTH", 1)
+	viper.SetDefault("NAME_MAX_LENGTH", 100)
+	viper.SetDefault("NAME_RESTRICT_CHARACTERS", true)
+	viper.SetDefault("LOGIN_AUDIT_ENABLED", true)
+	viper.SetDefault("AUDIT_BATCH_SIZE", 0)
+	viper.SetDefault("AUDIT_FLUSH_INTERVAL", "1s")
+	viper.SetDefault("AUDIT_BUFFER_SIZE", 10000)
+	viper.SetDefault("ACCOUNT_CLOSURE_ENABLED", false)
+	viper.SetDefault("ACCOUNT_CLOSURE_COOLING_OFF", "336h")
+	viper.SetDefault("ACCOUNT_CLOSURE_SWEEP_INTERVAL", "1h")
+	viper.SetDefault("SESSION_TRACKING_ENABLED", false)
+	viper.SetDefault("IDLE_SESSION_TIMEOUT", "0s")
+	viper.SetDefault("UNIQUE_DEVICE_BINDING_ENABLED", false)
+	viper.SetDefault("EMAIL_VERIFICATION_ENABLED", false)
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "24h")
+	viper.SetDefault("RESEND_VERIFICATION_PER_HOUR", 3)
+	viper.SetDefault("REQUIRE_EMAIL_VERIFICATION", false)
+	viper.SetDefault("DUPLICATE_IDENTITY_MODE", "off")
+	viper.SetDefault("POSTCODE_CHECK_MODE", "off")
+	viper.SetDefault("PASSWORD_HASH_SCHEME", "bcrypt")
+	viper.SetDefault("PASSWORD_MAX_BYTES", 72)
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_REQUIRE_UPPERCASE", true)
+	viper.SetDefault("PASSWORD_REQUIRE_LOWERCASE", true)
+	viper.SetDefault("PASSWORD_REQUIRE_DIGIT", true)
+	viper.SetDefault("PASSWORD_REQUIRE_SPECIAL", true)
+	viper.SetDefault("PASSWORD_MIN_UNIQUE_CHARS", 0)
+	viper.SetDefault("PASSWORD_HASH_CONCURRENCY", 0)
+	viper.SetDefault("PASSWORD_HASH_QUEUE_TIMEOUT", "1s")
+	viper.SetDefault("TOKEN_BINDING_MODE", "none")
+	viper.SetDefault("JWT_MINIMAL_CLAIMS", false)
+	viper.SetDefault("ME_EXPOSE_TOKEN_CLAIMS", false)
+	viper.SetDefault("REGISTER_GENERIC_RESPONSE", false)
+	viper.SetDefault("READINESS_CACHE_TTL", "1s")
+	viper.SetDefault("HTTPS_MODE", "off")
+	viper.SetDefault("DEGRADED_HEADER_ENABLED", false)
+	viper.SetDefault("AUTH_NO_STORE_ENABLED", true)
+	viper.SetDefault("STARTUP_MAX_ATTEMPTS", 10)
+	viper.SetDefault("STARTUP_INITIAL_BACKOFF", "500ms")
+	viper.SetDefault("STARTUP_MAX_BACKOFF", "10s")
+	viper.SetDefault("DEBUG_BODY_LOGGING_ENABLED", false)
+	viper.SetDefault("DEBUG_BODY_LOGGING_MAX_BYTES", 4096)
+	viper.SetDefault("DEBUG_BODY_LOGGING_HEADERS", false)
 
 	jwtExpiry, err := time.ParseDuration(viper.GetString("JWT_EXPIRY"))
 	if err != nil {
@@ -63,32 +316,176 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid REFRESH_TOKEN_EXPIRY: %w", err)
 	}
 
+	rememberMeExpiry, err := time.ParseDuration(viper.GetString("REMEMBER_ME_REFRESH_EXPIRY"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REMEMBER_ME_REFRESH_EXPIRY: %w", err)
+	}
+
 	sessionTimeout, err := time.ParseDuration(viper.GetString("SESSION_TIMEOUT"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid SESSION_TIMEOUT: %w", err)
 	}
 
+	readinessCacheTTL, err := time.ParseDuration(viper.GetString("READINESS_CACHE_TTL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid READINESS_CACHE_TTL: %w", err)
+	}
+
+	startupInitialBackoff, err := time.ParseDuration(viper.GetString("STARTUP_INITIAL_BACKOFF"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STARTUP_INITIAL_BACKOFF: %w", err)
+	}
+
+	startupMaxBackoff, err := time.ParseDuration(viper.GetString("STARTUP_MAX_BACKOFF"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STARTUP_MAX_BACKOFF: %w", err)
+	}
+
+	responseSigningSecrets, err := getKeyValueList("RESPONSE_SIGNING_SECRETS")
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		ServiceName: viper.GetString("SERVICE_NAME"),
 		ServicePort: viper.GetString("SERVICE_PORT"),
 		LogLevel:    viper.GetString("LOG_LEVEL"),
+		Environment: strings.ToLower(strings.TrimSpace(viper.GetString("ENVIRONMENT"))),
+
+		DebugEndpointsEnabled: viper.GetBool("DEBUG_ENDPOINTS_ENABLED"),
+
+		FeatureOverridesSecret: viper.GetString("FEATURE_OVERRIDES_SECRET"),
 
 		DatabaseURL: viper.GetString("DATABASE_URL"),
 		RedisURL:    viper.GetString("REDIS_URL"),
 
-		JWTSecret:          viper.GetString("JWT_SECRET"),
-		JWTExpiry:          jwtExpiry,
-		RefreshTokenExpiry: refreshTokenExpiry,
+		DBTxMaxAttempts:  viper.GetInt("DB_TX_MAX_ATTEMPTS"),
+		DBTxRetryBackoff: viper.GetDuration("DB_TX_RETRY_BACKOFF"),
+
+		JWTSecret:            viper.GetString("JWT_SECRET"),
+		JWTRefreshSecret:     viper.GetString("JWT_REFRESH_SECRET"),
+		JWTRequireSeparate:   viper.GetBool("JWT_REQUIRE_SEPARATE_REFRESH_SECRET"),
+		RejectWeakSecrets:    viper.GetBool("REJECT_WEAK_SECRETS"),
+		JWTExpiry:            jwtExpiry,
+		JWTExpiryJitter:      viper.GetFloat64("JWT_EXPIRY_JITTER"),
+		JWTExpiryJitterMax:   viper.GetDuration("JWT_EXPIRY_JITTER_MAX"),
+		JWTExpiryGrace:       viper.GetDuration("JWT_EXPIRY_GRACE"),
+		RefreshTokenExpiry:   refreshTokenExpiry,
+		RefreshTokensEnabled: viper.GetBool("REFRESH_TOKENS_ENABLED"),
+		RememberMeExpiry:     rememberMeExpiry,
+
+		RefreshTokenRotationEnabled: viper.GetBool("REFRESH_TOKEN_ROTATION_ENABLED"),
+		RefreshTokenReuseGrace:      viper.GetDuration("REFRESH_TOKEN_REUSE_GRACE"),
+
+		JWTAlgorithm:      strings.ToUpper(strings.TrimSpace(viper.GetString("JWT_ALGORITHM"))),
+		JWTPrivateKeyPath: viper.GetString("JWT_PRIVATE_KEY_PATH"),
+		JWTPublicKeyPath:  viper.GetString("JWT_PUBLIC_KEY_PATH"),
+
+		JWTRetiredPublicKeyPaths: getStringList("JWT_RETIRED_PUBLIC_KEY_PATHS"),
+
+		JWTMaxExpiry:          viper.GetDuration("JWT_MAX_EXPIRY"),
+		RefreshTokenMaxExpiry: viper.GetDuration("REFRESH_TOKEN_MAX_EXPIRY"),
 
 		BcryptCost: viper.GetInt("BCRYPT_COST"),
 
 		RateLimitEnabled:           viper.GetBool("RATE_LIMIT_ENABLED"),
 		RateLimitRequestsPerMinute: viper.GetInt("RATE_LIMIT_REQUESTS_PER_MINUTE"),
+		RateLimitLoginPerMinute:    viper.GetInt("RATE_LIMIT_LOGIN_REQUESTS_PER_MINUTE"),
+		RateLimitReadPerMinute:     viper.GetInt("RATE_LIMIT_READ_REQUESTS_PER_MINUTE"),
+		RateLimitAllowlist:         getStringList("RATE_LIMIT_ALLOWLIST"),
+		UserRateLimitPerMinute:     viper.GetInt("USER_RATE_LIMIT_REQUESTS_PER_MINUTE"),
+		RegistrationsPerIPPerDay:   viper.GetInt("REGISTRATIONS_PER_IP_PER_DAY"),
+		DataExportsPerDay:          viper.GetInt("DATA_EXPORTS_PER_DAY"),
+		LoginActivityPerMinute:     viper.GetInt("LOGIN_ACTIVITY_REQUESTS_PER_MINUTE"),
+
+		RateLimitKey: getStringList("RATE_LIMIT_KEY"),
+
+		RateLimitStore:           viper.GetString("RATE_LIMIT_STORE"),
+		RateLimitStoreFailMode:   viper.GetString("RATE_LIMIT_STORE_FAIL_MODE"),
+		RateLimitStoreRetryAfter: viper.GetDuration("RATE_LIMIT_STORE_RETRY_AFTER"),
+
+		RateLimitMaxInFlight:    viper.GetInt("RATE_LIMIT_MAX_IN_FLIGHT"),
+		RateLimitShedRetryAfter: viper.GetDuration("RATE_LIMIT_SHED_RETRY_AFTER"),
 
 		CORSOrigins:     viper.GetStringSlice("CORS_ORIGINS"),
 		CORSCredentials: viper.GetBool("CORS_CREDENTIALS"),
 
+		HTTPSMode:           viper.GetString("HTTPS_MODE"),
+		HTTPSTrustedProxies: getStringList("HTTPS_TRUSTED_PROXIES"),
+
+		TrustedProxies: getStringList("TRUSTED_PROXIES"),
+
 		SessionTimeout: sessionTimeout,
+
+		NameMinLength:          viper.GetInt("NAME_MIN_LENGTH"),
+		NameMaxLength:          viper.GetInt("NAME_MAX_LENGTH"),
+		NameRestrictCharacters: viper.GetBool("NAME_RESTRICT_CHARACTERS"),
+
+		LoginAuditEnabled:  viper.GetBool("LOGIN_AUDIT_ENABLED"),
+		GeoIPCountryDBPath: viper.GetString("GEOIP_COUNTRY_DB_PATH"),
+		GeoIPASNDBPath:     viper.GetString("GEOIP_ASN_DB_PATH"),
+		AuditBatchSize:     viper.GetInt("AUDIT_BATCH_SIZE"),
+		AuditFlushInterval: viper.GetDuration("AUDIT_FLUSH_INTERVAL"),
+		AuditBufferSize:    viper.GetInt("AUDIT_BUFFER_SIZE"),
+
+		AccountClosureEnabled:       viper.GetBool("ACCOUNT_CLOSURE_ENABLED"),
+		AccountClosureCoolingOff:    viper.GetDuration("ACCOUNT_CLOSURE_COOLING_OFF"),
+		AccountClosureSweepInterval: viper.GetDuration("ACCOUNT_CLOSURE_SWEEP_INTERVAL"),
+
+		SessionTrackingEnabled: viper.GetBool("SESSION_TRACKING_ENABLED"),
+		IdleSessionTimeout:     viper.GetDuration("IDLE_SESSION_TIMEOUT"),
+
+		UniqueDeviceBindingEnabled: viper.GetBool("UNIQUE_DEVICE_BINDING_ENABLED"),
+
+		EmailVerificationEnabled:  viper.GetBool("EMAIL_VERIFICATION_ENABLED"),
+		EmailVerificationTTL:      viper.GetDuration("EMAIL_VERIFICATION_TTL"),
+		ResendVerificationPerHour: viper.GetInt("RESEND_VERIFICATION_PER_HOUR"),
+		RequireEmailVerification:  viper.GetBool("REQUIRE_EMAIL_VERIFICATION"),
+
+		DuplicateIdentityMode: viper.GetString("DUPLICATE_IDENTITY_MODE"),
+		PostcodeCheckMode:     viper.GetString("POSTCODE_CHECK_MODE"),
+
+		PasswordHashScheme: viper.GetString("PASSWORD_HASH_SCHEME"),
+		PasswordPolicy: PasswordPolicy{
+			MinLength:        viper.GetInt("PASSWORD_MIN_LENGTH"),
+			MaxBytes:         viper.GetInt("PASSWORD_MAX_BYTES"),
+			RequireUppercase: viper.GetBool("PASSWORD_REQUIRE_UPPERCASE"),
+			RequireLowercase: viper.GetBool("PASSWORD_REQUIRE_LOWERCASE"),
+			RequireDigit:     viper.GetBool("PASSWORD_REQUIRE_DIGIT"),
+			RequireSpecial:   viper.GetBool("PASSWORD_REQUIRE_SPECIAL"),
+			MinUniqueChars:   viper.GetInt("PASSWORD_MIN_UNIQUE_CHARS"),
+		},
+
+		PasswordHashConcurrency:  viper.GetInt("PASSWORD_HASH_CONCURRENCY"),
+		PasswordHashQueueTimeout: viper.GetDuration("PASSWORD_HASH_QUEUE_TIMEOUT"),
+
+		TokenBindingMode: viper.GetString("TOKEN_BINDING_MODE"),
+
+		JWTMinimalClaims: viper.GetBool("JWT_MINIMAL_CLAIMS"),
+
+		MeExposeTokenClaims: viper.GetBool("ME_EXPOSE_TOKEN_CLAIMS"),
+
+		RegisterGenericResponse: viper.GetBool("REGISTER_GENERIC_RESPONSE"),
+
+		ResponseSigningPaths:   getStringList("RESPONSE_SIGNING_PATHS"),
+		ResponseSigningSecrets: responseSigningSecrets,
+
+		ReadinessCacheTTL: readinessCacheTTL,
+
+		DegradedHeaderEnabled: viper.GetBool("DEGRADED_HEADER_ENABLED"),
+
+		AuthNoStoreEnabled: viper.GetBool("AUTH_NO_STORE_ENABLED"),
+
+		StartupMaxAttempts:    viper.GetInt("STARTUP_MAX_ATTEMPTS"),
+		StartupInitialBackoff: startupInitialBackoff,
+		StartupMaxBackoff:     startupMaxBackoff,
+
+		DebugBodyLoggingEnabled:  viper.GetBool("DEBUG_BODY_LOGGING_ENABLED"),
+		DebugBodyLoggingPaths:    getStringList("DEBUG_BODY_LOGGING_PATHS"),
+		DebugBodyLoggingMaxBytes: viper.GetInt("DEBUG_BODY_LOGGING_MAX_BYTES"),
+		DebugBodyLoggingHeaders:  viper.GetBool("DEBUG_BODY_LOGGING_HEADERS"),
+
+		LogRedactHeaders: getStringList("LOG_REDACT_HEADERS"),
 	}
 
 	if err := config.Validate(); err != nil {
@@ -98,12 +495,64 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// getStringList reads a comma-separated list, trimming whitespace and
+// dropping empty entries. viper's GetStringSlice splits env values on
+// whitespace only, so "a,b" would come back as a single entry.
+func getStringList(key string) []string {
+	var values []string
+	for _, raw := range viper.GetStringSlice(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// getKeyValueList reads a comma-separated list of "key:value" pairs
+func getKeyValueList(key string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, entry := range getStringList(key) {
+		k, v, ok := strings.Cut(entry, ":")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid %s entry: expected key:value", key)
+		}
+		values[k] = v
+	}
+	return values, nil
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
+	switch c.Environment {
+	case "development", "staging", "production":
+	default:
+		return fmt.Errorf("ENVIRONMENT must be development, staging or production")
+	}
+
+	if c.DebugEndpointsEnabled && c.Environment == "production" {
+		return fmt.Errorf("DEBUG_ENDPOINTS_ENABLED must not be set in production")
+	}
+
+	if c.FeatureOverridesSecret != "" {
+		if c.Environment == "production" {
+			return fmt.Errorf("FEATURE_OVERRIDES_SECRET must not be set in production")
+		}
+		if len(c.FeatureOverridesSecret) < 32 {
+			return fmt.Errorf("FEATURE_OVERRIDES_SECRET must be at least 32 characters")
+		}
+	}
+
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
 
+	if c.DBTxMaxAttempts < 0 || c.DBTxRetryBackoff < 0 {
+		return fmt.Errorf("DB_TX_MAX_ATTEMPTS and DB_TX_RETRY_BACKOFF must not be negative")
+	}
+
 	if c.RedisURL == "" {
 		return fmt.Errorf("REDIS_URL is required")
 	}
@@ -116,9 +565,412 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters")
 	}
 
+	if c.JWTRequireSeparate && c.JWTRefreshSecret == "" {
+		return fmt.Errorf("JWT_REFRESH_SECRET is required when JWT_REQUIRE_SEPARATE_REFRESH_SECRET is set")
+	}
+
+	if c.JWTRefreshSecret != "" {
+		if len(c.JWTRefreshSecret) < 32 {
+			return fmt.Errorf("JWT_REFRESH_SECRET must be at least 32 characters")
+		}
+		if c.JWTRefreshSecret == c.JWTSecret {
+			return fmt.Errorf("JWT_REFRESH_SECRET must differ from JWT_SECRET")
+		}
+	}
+
+	switch c.JWTAlgorithm {
+	case "", "HS256":
+		if len(c.JWTRetiredPublicKeyPaths) > 0 {
+			return fmt.Errorf("JWT_RETIRED_PUBLIC_KEY_PATHS requires JWT_ALGORITHM RS256")
+		}
+	case "RS256":
+		if c.JWTPrivateKeyPath == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_PATH is required when JWT_ALGORITHM is RS256")
+		}
+	default:
+		return fmt.Errorf("JWT_ALGORITHM must be HS256 or RS256")
+	}
+
+	if err := c.validateSecretStrength(); err != nil {
+		return err
+	}
+
+	if c.JWTExpiryJitter < 0 || c.JWTExpiryJitter > 0.5 {
+		return fmt.Errorf("JWT_EXPIRY_JITTER must be between 0 and 0.5")
+	}
+
+	if err := c.validateDurations(); err != nil {
+		return err
+	}
+
+	if err := c.validateTokenExpiry(); err != nil {
+		return err
+	}
+
 	if c.BcryptCost < 10 || c.BcryptCost > 14 {
 		return fmt.Errorf("BCRYPT_COST must be between 10 and 14")
 	}
 
+	if c.RegistrationsPerIPPerDay < 0 {
+		return fmt.Errorf("REGISTRATIONS_PER_IP_PER_DAY must not be negative")
+	}
+
+	if c.DataExportsPerDay < 1 {
+		return fmt.Errorf("DATA_EXPORTS_PER_DAY must be at least 1")
+	}
+
+	if c.LoginActivityPerMinute < 1 {
+		return fmt.Errorf("LOGIN_ACTIVITY_REQUESTS_PER_MINUTE must be at least 1")
+	}
+
+	if c.RememberMeExpiry < 0 {
+		return fmt.Errorf("REMEMBER_ME_REFRESH_EXPIRY must not be negative")
+	}
+
+	if c.RateLimitRequestsPerMinute <= 0 || c.RateLimitLoginPerMinute <= 0 || c.RateLimitReadPerMinute <= 0 {
+		return fmt.Errorf("RATE_LIMIT_REQUESTS_PER_MINUTE, RATE_LIMIT_LOGIN_REQUESTS_PER_MINUTE and RATE_LIMIT_READ_REQUESTS_PER_MINUTE must be positive")
+	}
+
+	if c.UserRateLimitPerMinute < 0 {
+		return fmt.Errorf("USER_RATE_LIMIT_REQUESTS_PER_MINUTE must not be negative")
+	}
+
+	if c.RateLimitStore != "memory" && c.RateLimitStore != "redis" {
+		return fmt.Errorf("RATE_LIMIT_STORE must be memory or redis")
+	}
+
+	if c.HTTPSMode != "off" && c.HTTPSMode != "reject" && c.HTTPSMode != "redirect" {
+		return fmt.Errorf("HTTPS_MODE must be off, reject or redirect")
+	}
+
+	if c.RateLimitMaxInFlight < 0 {
+		return fmt.Errorf("RATE_LIMIT_MAX_IN_FLIGHT must not be negative")
+	}
+
+	if c.StartupMaxAttempts < 1 {
+		return fmt.Errorf("STARTUP_MAX_ATTEMPTS must be at least 1")
+	}
+
+	if c.NameMinLength < 1 {
+		return fmt.Errorf("NAME_MIN_LENGTH must be at least 1")
+	}
+
+	if c.NameMaxLength < c.NameMinLength || c.NameMaxLength > 100 {
+		return fmt.Errorf("NAME_MAX_LENGTH must be between NAME_MIN_LENGTH and 100")
+	}
+
+	if c.PasswordPolicy.MaxBytes < 8 || c.PasswordPolicy.MaxBytes > 72 {
+		return fmt.Errorf("PASSWORD_MAX_BYTES must be between 8 and 72")
+	}
+
+	if c.PasswordPolicy.MinLength < 8 || c.PasswordPolicy.MinLength > c.PasswordPolicy.MaxBytes {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH must be between 8 and PASSWORD_MAX_BYTES")
+	}
+
+	if c.PasswordPolicy.MinUniqueChars < 0 || c.PasswordPolicy.MinUniqueChars > c.PasswordPolicy.MinLength {
+		return fmt.Errorf("PASSWORD_MIN_UNIQUE_CHARS must be between 0 and PASSWORD_MIN_LENGTH")
+	}
+
+	if c.PasswordHashConcurrency < 0 {
+		return fmt.Errorf("PASSWORD_HASH_CONCURRENCY must not be negative")
+	}
+
+	if c.AuditBatchSize < 0 {
+		return fmt.Errorf("AUDIT_BATCH_SIZE must not be negative")
+	}
+
+	if c.AuditBatchSize > 0 && (c.AuditFlushInterval <= 0 || c.AuditBufferSize < c.AuditBatchSize) {
+		return fmt.Errorf("AUDIT_FLUSH_INTERVAL must be positive and AUDIT_BUFFER_SIZE at least AUDIT_BATCH_SIZE")
+	}
+
+	if c.EmailVerificationEnabled {
+		// Tokens are only logged until the service can send email
+		if c.Environment == "production" {
+			return fmt.Errorf("EMAIL_VERIFICATION_ENABLED must not be set in production until email delivery exists")
+		}
+		if c.EmailVerificationTTL <= 0 || c.ResendVerificationPerHour < 1 {
+			return fmt.Errorf("EMAIL_VERIFICATION_TTL must be positive and RESEND_VERIFICATION_PER_HOUR at least 1")
+		}
+	}
+
+	if c.RequireEmailVerification && !c.EmailVerificationEnabled {
+		return fmt.Errorf("REQUIRE_EMAIL_VERIFICATION requires EMAIL_VERIFICATION_ENABLED")
+	}
+
+	// Session last use is only recorded once a minute, so a shorter idle
+	// timeout would log out sessions that are in use
+	if c.SessionTrackingEnabled && c.IdleSessionTimeout > 0 && c.IdleSessionTimeout < 5*time.Minute {
+		return fmt.Errorf("IDLE_SESSION_TIMEOUT must be 0s (disabled) or at least 5m")
+	}
+
+	if c.AccountClosureEnabled && (c.AccountClosureCoolingOff < 0 || c.AccountClosureSweepInterval <= 0) {
+		return fmt.Errorf("ACCOUNT_CLOSURE_COOLING_OFF must not be negative and ACCOUNT_CLOSURE_SWEEP_INTERVAL must be positive")
+	}
+
+	return nil
+}
+
+// durationRange is the allowed range for a duration setting, to catch
+// misconfigurations such as "15h" for "15m" at startup
+type durationRange struct {
+	name     string
+	value    time.Duration
+	min, max time.Duration
+}
+
+// durationRanges returns the ranges the duration settings must fall within.
+// Settings only in effect when a feature is enabled are checked only then.
+func (c *Config) durationRanges() []durationRange {
+	ranges := []durationRange{
+		{"JWT_EXPIRY", c.JWTExpiry, time.Minute, 24 * time.Hour},
+		{"JWT_EXPIRY_JITTER_MAX", c.JWTExpiryJitterMax, 0, time.Hour},
+		{"JWT_EXPIRY_GRACE", c.JWTExpiryGrace, 0, 5 * time.Minute},
+		{"SESSION_TIMEOUT", c.SessionTimeout, time.Minute, 24 * time.Hour},
+		{"RATE_LIMIT_STORE_RETRY_AFTER", c.RateLimitStoreRetryAfter, time.Second, 5 * time.Minute},
+		{"READINESS_CACHE_TTL", c.ReadinessCacheTTL, 0, time.Minute},
+		{"STARTUP_INITIAL_BACKOFF", c.StartupInitialBackoff, 0, time.Minute},
+		{"STARTUP_MAX_BACKOFF", c.StartupMaxBackoff, 0, 5 * time.Minute},
+		{"PASSWORD_HASH_QUEUE_TIMEOUT", c.PasswordHashQueueTimeout, 0, 30 * time.Second},
+	}
+
+	if c.RefreshTokensEnabled {
+		ranges = append(ranges, durationRange{"REFRESH_TOKEN_EXPIRY", c.RefreshTokenExpiry, time.Hour, 90 * 24 * time.Hour})
+	}
+
+	if c.RefreshTokenRotationEnabled {
+		ranges = append(ranges, durationRange{"REFRESH_TOKEN_REUSE_GRACE", c.RefreshTokenReuseGrace, 0, time.Minute})
+	}
+
+	if c.SessionTrackingEnabled {
+		ranges = append(ranges, durationRange{"IDLE_SESSION_TIMEOUT", c.IdleSessionTimeout, 0, 90 * 24 * time.Hour})
+	}
+
+	if c.RateLimitMaxInFlight > 0 {
+		ranges = append(ranges, durationRange{"RATE_LIMIT_SHED_RETRY_AFTER", c.RateLimitShedRetryAfter, time.Second, 5 * time.Minute})
+	}
+
+	return ranges
+}
+
+// validateDurations checks each duration setting is within its range
+func (c *Config) validateDurations() error {
+	for _, r := range c.durationRanges() {
+		if r.value < r.min || r.value > r.max {
+			return fmt.Errorf("%s (%s) must be between %s and %s", r.name, r.value, r.min, r.max)
+		}
+	}
+	return nil
+}
+
+// validateTokenExpiry checks token lifetimes are positive, within their
+// configured maximums, and that access tokens expire before refresh tokens.
+// An access token outliving its refresh token would make refreshing pointless.
+// With jitter the checks apply to the longest access token lifetime it allows.
+func (c *Config) validateTokenExpiry() error {
+	if c.JWTExpiry <= 0 {
+		return fmt.Errorf("JWT_EXPIRY must be positive")
+	}
+
+	longest, name := c.JWTExpiry, "JWT_EXPIRY"
+	if spread := c.jwtExpirySpread(); spread > 0 {
+		longest, name = c.JWTExpiry+spread, "JWT_EXPIRY plus JWT_EXPIRY_JITTER"
+	}
+
+	if c.JWTMaxExpiry > 0 && longest > c.JWTMaxExpiry {
+		return fmt.Errorf("%s (%s) must not exceed JWT_MAX_EXPIRY (%s)", name, longest, c.JWTMaxExpiry)
+	}
+
+	if !c.RefreshTokensEnabled {
+		return nil
+	}
+
+	if longest >= c.RefreshTokenExpiry {
+		return fmt.Errorf("%s (%s) must be shorter than REFRESH_TOKEN_EXPIRY (%s)", name, longest, c.RefreshTokenExpiry)
+	}
+
+	if c.RefreshTokenMaxExpiry > 0 && c.RefreshTokenExpiry > c.RefreshTokenMaxExpiry {
+		return fmt.Errorf("REFRESH_TOKEN_EXPIRY (%s) must not exceed REFRESH_TOKEN_MAX_EXPIRY (%s)", c.RefreshTokenExpiry, c.RefreshTokenMaxExpiry)
+	}
+
+	if c.RefreshTokenMaxExpiry > 0 && c.RememberMeExpiry > c.RefreshTokenMaxExpiry {
+		return fmt.Errorf("REMEMBER_ME_REFRESH_EXPIRY (%s) must not exceed REFRESH_TOKEN_MAX_EXPIRY (%s)", c.RememberMeExpiry, c.RefreshTokenMaxExpiry)
+	}
+
 	return nil
 }
+
+// jwtExpirySpread returns the largest offset JWT_EXPIRY_JITTER may add to
+// JWT_EXPIRY, capped by JWT_EXPIRY_JITTER_MAX when set
+func (c *Config) jwtExpirySpread() time.Duration {
+	spread := time.Duration(float64(c.JWTExpiry) * c.JWTExpiryJitter)
+	if c.JWTExpiryJitterMax > 0 && spread > c.JWTExpiryJitterMax {
+		spread = c.JWTExpiryJitterMax
+	}
+	return spread
+}
+
+// redactedValue replaces secret values in redacted output
+const redactedValue = "REDACTED"
+
+// Redacted returns a loggable summary of the configuration with secrets masked.
+// The JWT secret is never included, and credentials embedded in the database
+// and Redis URLs are replaced so operators can confirm settings safely.
+func (c *Config) Redacted() string {
+	fields := []string{
+		fmt.Sprintf("service_name=%s", c.ServiceName),
+		fmt.Sprintf("environment=%s", c.Environment),
+		fmt.Sprintf("debug_endpoints_enabled=%t", c.DebugEndpointsEnabled),
+		fmt.Sprintf("feature_overrides_secret=%s", redactSecret(c.FeatureOverridesSecret)),
+		fmt.Sprintf("service_port=%s", c.ServicePort),
+		fmt.Sprintf("log_level=%s", c.LogLevel),
+		fmt.Sprintf("database_url=%s", redactURL(c.DatabaseURL)),
+		fmt.Sprintf("db_tx_max_attempts=%d", c.DBTxMaxAttempts),
+		fmt.Sprintf("db_tx_retry_backoff=%s", c.DBTxRetryBackoff),
+		fmt.Sprintf("redis_url=%s", redactURL(c.RedisURL)),
+		fmt.Sprintf("jwt_secret=%s", redactSecret(c.JWTSecret)),
+		fmt.Sprintf("jwt_refresh_secret=%s", redactSecret(c.JWTRefreshSecret)),
+		fmt.Sprintf("jwt_require_separate_refresh_secret=%t", c.JWTRequireSeparate),
+		fmt.Sprintf("reject_weak_secrets=%t", c.RejectWeakSecrets),
+		fmt.Sprintf("jwt_expiry=%s", c.JWTExpiry),
+		fmt.Sprintf("jwt_expiry_jitter=%g", c.JWTExpiryJitter),
+		fmt.Sprintf("jwt_expiry_jitter_max=%s", c.JWTExpiryJitterMax),
+		fmt.Sprintf("jwt_expiry_grace=%s", c.JWTExpiryGrace),
+		fmt.Sprintf("refresh_token_expiry=%s", c.RefreshTokenExpiry),
+		fmt.Sprintf("refresh_tokens_enabled=%t", c.RefreshTokensEnabled),
+		fmt.Sprintf("remember_me_refresh_expiry=%s", c.RememberMeExpiry),
+		fmt.Sprintf("refresh_token_rotation_enabled=%t", c.RefreshTokenRotationEnabled),
+		fmt.Sprintf("refresh_token_reuse_grace=%s", c.RefreshTokenReuseGrace),
+		fmt.Sprintf("jwt_algorithm=%s", c.JWTAlgorithm),
+		fmt.Sprintf("jwt_private_key_path=%s", c.JWTPrivateKeyPath),
+		fmt.Sprintf("jwt_public_key_path=%s", c.JWTPublicKeyPath),
+		fmt.Sprintf("jwt_retired_public_key_paths=%s", strings.Join(c.JWTRetiredPublicKeyPaths, ",")),
+		fmt.Sprintf("jwt_max_expiry=%s", c.JWTMaxExpiry),
+		fmt.Sprintf("refresh_token_max_expiry=%s", c.RefreshTokenMaxExpiry),
+		fmt.Sprintf("bcrypt_cost=%d", c.BcryptCost),
+		fmt.Sprintf("rate_limit_enabled=%t", c.RateLimitEnabled),
+		fmt.Sprintf("rate_limit_requests_per_minute=%d", c.RateLimitRequestsPerMinute),
+		fmt.Sprintf("rate_limit_login_requests_per_minute=%d", c.RateLimitLoginPerMinute),
+		fmt.Sprintf("rate_limit_read_requests_per_minute=%d", c.RateLimitReadPerMinute),
+		fmt.Sprintf("rate_limit_allowlist=%s", strings.Join(c.RateLimitAllowlist, ",")),
+		fmt.Sprintf("user_rate_limit_requests_per_minute=%d", c.UserRateLimitPerMinute),
+		fmt.Sprintf("rate_limit_key=%s", strings.Join(c.RateLimitKey, ",")),
+		fmt.Sprintf("rate_limit_store=%s", c.RateLimitStore),
+		fmt.Sprintf("rate_limit_store_fail_mode=%s", c.RateLimitStoreFailMode),
+		fmt.Sprintf("rate_limit_store_retry_after=%s", c.RateLimitStoreRetryAfter),
+		fmt.Sprintf("rate_limit_max_in_flight=%d", c.RateLimitMaxInFlight),
+		fmt.Sprintf("rate_limit_shed_retry_after=%s", c.RateLimitShedRetryAfter),
+		fmt.Sprintf("registrations_per_ip_per_day=%d", c.RegistrationsPerIPPerDay),
+		fmt.Sprintf("data_exports_per_day=%d", c.DataExportsPerDay),
+		fmt.Sprintf("login_activity_requests_per_minute=%d", c.LoginActivityPerMinute),
+		fmt.Sprintf("cors_origins=%s", strings.Join(c.CORSOrigins, ",")),
+		fmt.Sprintf("cors_credentials=%t", c.CORSCredentials),
+		fmt.Sprintf("https_mode=%s", c.HTTPSMode),
+		fmt.Sprintf("https_trusted_proxies=%s", strings.Join(c.HTTPSTrustedProxies, ",")),
+		fmt.Sprintf("trusted_proxies=%s", strings.Join(c.TrustedProxies, ",")),
+		fmt.Sprintf("session_timeout=%s", c.SessionTimeout),
+		fmt.Sprintf("name_min_length=%d", c.NameMinLength),
+		fmt.Sprintf("name_max_length=%d", c.NameMaxLength),
+		fmt.Sprintf("name_restrict_characters=%t", c.NameRestrictCharacters),
+		fmt.Sprintf("login_audit_enabled=%t", c.LoginAuditEnabled),
+		fmt.Sprintf("geoip_country_db_path=%s", c.GeoIPCountryDBPath),
+		fmt.Sprintf("geoip_asn_db_path=%s", c.GeoIPASNDBPath),
+		fmt.Sprintf("audit_batch_size=%d", c.AuditBatchSize),
+		fmt.Sprintf("audit_flush_interval=%s", c.AuditFlushInterval),
+		fmt.Sprintf("audit_buffer_size=%d", c.AuditBufferSize),
+		fmt.Sprintf("account_closure_enabled=%t", c.AccountClosureEnabled),
+		fmt.Sprintf("account_closure_cooling_off=%s", c.AccountClosureCoolingOff),
+		fmt.Sprintf("account_closure_sweep_interval=%s", c.AccountClosureSweepInterval),
+		fmt.Sprintf("session_tracking_enabled=%t", c.SessionTrackingEnabled),
+		fmt.Sprintf("idle_session_timeout=%s", c.IdleSessionTimeout),
+		fmt.Sprintf("unique_device_binding_enabled=%t", c.UniqueDeviceBindingEnabled),
+		fmt.Sprintf("email_verification_enabled=%t", c.EmailVerificationEnabled),
+		fmt.Sprintf("email_verification_ttl=%s", c.EmailVerificationTTL),
+		fmt.Sprintf("resend_verification_per_hour=%d", c.ResendVerificationPerHour),
+		fmt.Sprintf("require_email_verification=%t", c.RequireEmailVerification),
+		fmt.Sprintf("duplicate_identity_mode=%s", c.DuplicateIdentityMode),
+		fmt.Sprintf("postcode_check_mode=%s", c.PostcodeCheckMode),
+		fmt.Sprintf("password_hash_scheme=%s", c.PasswordHashScheme),
+		fmt.Sprintf("password_max_bytes=%d", c.PasswordPolicy.MaxBytes),
+		fmt.Sprintf("password_min_length=%d", c.PasswordPolicy.MinLength),
+		fmt.Sprintf("password_require_uppercase=%t", c.PasswordPolicy.RequireUppercase),
+		fmt.Sprintf("password_require_lowercase=%t", c.PasswordPolicy.RequireLowercase),
+		fmt.Sprintf("password_require_digit=%t", c.PasswordPolicy.RequireDigit),
+		fmt.Sprintf("password_require_special=%t", c.PasswordPolicy.RequireSpecial),
+		fmt.Sprintf("password_min_unique_chars=%d", c.PasswordPolicy.MinUniqueChars),
+		fmt.Sprintf("password_hash_concurrency=%d", c.PasswordHashConcurrency),
+		fmt.Sprintf("password_hash_queue_timeout=%s", c.PasswordHashQueueTimeout),
+		fmt.Sprintf("token_binding_mode=%s", c.TokenBindingMode),
+		fmt.Sprintf("jwt_minimal_claims=%t", c.JWTMinimalClaims),
+		fmt.Sprintf("me_expose_token_claims=%t", c.MeExposeTokenClaims),
+		fmt.Sprintf("register_generic_response=%t", c.RegisterGenericResponse),
+		fmt.Sprintf("response_signing_paths=%s", strings.Join(c.ResponseSigningPaths, ",")),
+		fmt.Sprintf("response_signing_partners=%s", strings.Join(sortedKeys(c.ResponseSigningSecrets), ",")),
+		fmt.Sprintf("readiness_cache_ttl=%s", c.ReadinessCacheTTL),
+		fmt.Sprintf("degraded_header_enabled=%t", c.DegradedHeaderEnabled),
+		fmt.Sprintf("auth_no_store_enabled=%t", c.AuthNoStoreEnabled),
+		fmt.Sprintf("startup_max_attempts=%d", c.StartupMaxAttempts),
+		fmt.Sprintf("startup_initial_backoff=%s", c.StartupInitialBackoff),
+		fmt.Sprintf("startup_max_backoff=%s", c.StartupMaxBackoff),
+		fmt.Sprintf("debug_body_logging_enabled=%t", c.DebugBodyLoggingEnabled),
+		fmt.Sprintf("debug_body_logging_paths=%s", strings.Join(c.DebugBodyLoggingPaths, ",")),
+		fmt.Sprintf("debug_body_logging_headers=%t", c.DebugBodyLoggingHeaders),
+		fmt.Sprintf("log_redact_headers=%s", strings.Join(c.LogRedactHeaders, ",")),
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// redactSecret masks a secret value, keeping only whether it is set
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// keyValuePassword matches the password setting in a key/value connection
+// string, e.g. "host=db password='se cret' dbname=x", with its value bare or
+// single-quoted (with \\ and \' escapes)
+var keyValuePassword = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'?|\S*)`)
+
+// redactURL masks the password (and any unparseable value) in a connection
+// URL or, for connection strings without a scheme, a key/value DSN
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	if !strings.Contains(raw, "://") {
+		return keyValuePassword.ReplaceAllString(raw, "${1}"+redactedValue)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		// Can't tell which part is sensitive, so hide all of it
+		return redactedValue
+	}
+
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), redactedValue)
+		}
+	}
+
+	query := u.Query()
+	if query.Has("password") {
+		query.Set("password", redactedValue)
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
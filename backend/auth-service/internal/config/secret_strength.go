@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// placeholderSecrets are fragments of the example and default secrets that
+// deployments ship with by mistake, compared with case and separators removed
+var placeholderSecrets = []string{
+	"changeme",
+	"changethis",
+	"replaceme",
+	"yoursecret",
+	"yourjwtsecret",
+	"placeholder",
+	"example",
+	"default",
+	"insecure",
+}
+
+// Secrets with fewer distinct characters or less Shannon entropy per
+// character than this are padding or a repeated pattern, not random
+const (
+	minSecretDistinctChars = 8
+	minSecretEntropyBits   = 3.0
+)
+
+// weakSecretReason returns why a secret looks like a placeholder or a
+// non-random value, or "" when it passes
+func weakSecretReason(secret string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, secret)
+	for _, placeholder := range placeholderSecrets {
+		if strings.Contains(normalized, placeholder) {
+			return "looks like a placeholder (contains " + placeholder + ")"
+		}
+	}
+
+	if isRepeated(secret) {
+		return "is a repeated pattern"
+	}
+
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range secret {
+		counts[r]++
+		total++
+	}
+	if len(counts) < minSecretDistinctChars {
+		return "has too few distinct characters"
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	if entropy < minSecretEntropyBits {
+		return "is too predictable; generate one with `openssl rand -base64 48`"
+	}
+
+	return ""
+}
+
+// isRepeated reports whether s is a shorter string repeated, e.g. "abcabcabc"
+func isRepeated(s string) bool {
+	for period := 1; period <= len(s)/2; period++ {
+		if len(s)%period == 0 && strings.Repeat(s[:period], len(s)/period) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSecretStrength rejects placeholder or low-entropy signing secrets.
+// It always applies in production; elsewhere only with REJECT_WEAK_SECRETS,
+// so local setups can keep the example secret.
+func (c *Config) validateSecretStrength() error {
+	if c.Environment != "production" && !c.RejectWeakSecrets {
+		return nil
+	}
+
+	secrets := []struct {
+		name, value string
+	}{
+		{"JWT_SECRET", c.JWTSecret},
+		{"JWT_REFRESH_SECRET", c.JWTRefreshSecret},
+	}
+	for _, secret := range secrets {
+		if secret.value == "" {
+			continue
+		}
+		if reason := weakSecretReason(secret.value); reason != "" {
+			return fmt.Errorf("%s %s", secret.name, reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package features
+
+import "context"
+
+// Feature flags that may be overridden per request outside production
+const (
+	RefreshTokens = "refresh_tokens"
+	MinimalClaims = "minimal_claims"
+)
+
+// Overridable lists the flags a request may override
+var Overridable = []string{RefreshTokens, MinimalClaims}
+
+// IsOverridable reports whether name is a flag requests may override
+func IsOverridable(name string) bool {
+	for _, flag := range Overridable {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying per-request flag overrides
+func NewContext(ctx context.Context, overrides map[string]bool) context.Context {
+	return context.WithValue(ctx, contextKey{}, overrides)
+}
+
+// Enabled returns the flag's override for the request in ctx, or configured
+// when the request doesn't override it
+func Enabled(ctx context.Context, name string, configured bool) bool {
+	overrides, _ := ctx.Value(contextKey{}).(map[string]bool)
+	if enabled, ok := overrides[name]; ok {
+		return enabled
+	}
+	return configured
+}
@@ -0,0 +1,22 @@
+package features
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnabled tests that request overrides take precedence over configuration
+func TestEnabled(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, Enabled(ctx, RefreshTokens, true))
+	assert.False(t, Enabled(ctx, MinimalClaims, false))
+
+	ctx = NewContext(ctx, map[string]bool{RefreshTokens: false})
+	assert.False(t, Enabled(ctx, RefreshTokens, true))
+	assert.False(t, Enabled(ctx, MinimalClaims, false), "flags without an override keep their configured value")
+
+	assert.True(t, IsOverridable(MinimalClaims))
+	assert.False(t, IsOverridable("debug_endpoints"))
+}
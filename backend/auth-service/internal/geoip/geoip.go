@@ -0,0 +1,96 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/oschwald/geoip2-golang"
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// Resolver resolves IP addresses using local MaxMind GeoIP databases
+type Resolver struct {
+	country *geoip2.Reader
+	asn     *geoip2.Reader
+}
+
+// Open opens the configured MaxMind databases (GeoLite2/GeoIP2 Country and ASN).
+// An empty or missing path disables that part of the lookup. If neither database
+// is available it returns a nil Resolver and no error, so enrichment is skipped.
+func Open(countryDBPath, asnDBPath string) (*Resolver, error) {
+	country, err := openReader(countryDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP country database: %w", err)
+	}
+
+	asn, err := openReader(asnDBPath)
+	if err != nil {
+		if country != nil {
+			country.Close()
+		}
+		return nil, fmt.Errorf("failed to open GeoIP ASN database: %w", err)
+	}
+
+	if country == nil && asn == nil {
+		return nil, nil
+	}
+
+	return &Resolver{
+		country: country,
+		asn:     asn,
+	}, nil
+}
+
+// openReader opens a MaxMind database, returning nil if the path is unset or missing
+func openReader(path string) (*geoip2.Reader, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	return geoip2.Open(path)
+}
+
+// Lookup resolves the country and ASN for an IP address
+func (r *Resolver) Lookup(ip string) (*models.GeoLocation, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid IP address: %q", ip)
+	}
+
+	location := &models.GeoLocation{}
+
+	if r.country != nil {
+		record, err := r.country.Country(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("country lookup failed: %w", err)
+		}
+		location.Country = record.Country.IsoCode
+	}
+
+	if r.asn != nil {
+		record, err := r.asn.ASN(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("ASN lookup failed: %w", err)
+		}
+		location.ASN = record.AutonomousSystemNumber
+		location.ASOrganization = record.AutonomousSystemOrganization
+	}
+
+	return location, nil
+}
+
+// Close closes the underlying databases
+func (r *Resolver) Close() {
+	if r.country != nil {
+		r.country.Close()
+	}
+	if r.asn != nil {
+		r.asn.Close()
+	}
+}
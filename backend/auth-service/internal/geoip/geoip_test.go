@@ -0,0 +1,25 @@
+package geoip
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenMissingDatabase tests that absent databases disable enrichment without error
+func TestOpenMissingDatabase(t *testing.T) {
+	t.Run("no paths configured", func(t *testing.T) {
+		resolver, err := Open("", "")
+		require.NoError(t, err)
+		assert.Nil(t, resolver)
+	})
+
+	t.Run("configured paths do not exist", func(t *testing.T) {
+		dir := t.TempDir()
+		resolver, err := Open(filepath.Join(dir, "GeoLite2-Country.mmdb"), filepath.Join(dir, "GeoLite2-ASN.mmdb"))
+		require.NoError(t, err)
+		assert.Nil(t, resolver)
+	})
+}
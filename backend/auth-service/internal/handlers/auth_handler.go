@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/middleware"
 	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
 	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
 )
 
@@ -14,20 +17,92 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, req *models.RegisterRequest) (*models.User, error)
 	Login(ctx context.Context, email, password string) (*models.LoginResponse, error)
+	LoginWithOptions(ctx context.Context, email, password string, opts models.LoginOptions) (*models.LoginResponse, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*models.RefreshTokenResponse, error)
+	Logout(ctx context.Context, accessClaims *utils.RegisteredTokenClaims, refreshToken string) error
 	ValidateAccessToken(ctx context.Context, accessToken string) (*models.User, error)
+	ValidateAccessTokenWithClaims(ctx context.Context, accessToken string) (*models.User, *utils.RegisteredTokenClaims, error)
+	UpdateProfile(ctx context.Context, user *models.User, req *models.UpdateProfileRequest) (*models.User, error)
+	ExportUserData(ctx context.Context, user *models.User) (*models.DataExport, error)
+	SecuritySummary(ctx context.Context, user *models.User) (*models.SecuritySummary, error)
+	LoginActivity(ctx context.Context, user *models.User, limit, offset int) (*models.LoginActivityPage, error)
+	RequestAccountClosure(ctx context.Context, user *models.User) (*models.AccountClosure, error)
+	CancelAccountClosure(ctx context.Context, user *models.User) error
+	ListSessions(ctx context.Context, user *models.User, claims *utils.RegisteredTokenClaims) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, user *models.User, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, user *models.User, claims *utils.RegisteredTokenClaims) (*models.SessionRevocation, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	PasswordPolicy() models.PasswordPolicy
 }
 
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
-	authService AuthService
+	authService         AuthService
+	exposeTokenClaims   bool
+	genericRegistration bool
 }
 
+// AuthHandlerOption configures optional AuthHandler behaviour
+type AuthHandlerOption func(*AuthHandler)
+
+// WithTokenClaimsInMe includes the access token's registered claims
+// (sub, iat, exp, iss, aud) in the /me response
+func WithTokenClaimsInMe(enabled bool) AuthHandlerOption {
+	return func(h *AuthHandler) {
+		h.exposeTokenClaims = enabled
+	}
+}
+
+// WithGenericRegistrationResponse answers registrations with the same 202
+// response whether or not the email is already registered, so the response
+// doesn't reveal which emails have accounts
+func WithGenericRegistrationResponse(enabled bool) AuthHandlerOption {
+	return func(h *AuthHandler) {
+		h.genericRegistration = enabled
+	}
+}
+
+// genericRegistrationMessage is returned for every accepted registration in generic mode
+const genericRegistrationMessage = "registration received, check your email to continue"
+
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService AuthService) *AuthHandler {
-	return &AuthHandler{
+func NewAuthHandler(authService AuthService, opts ...AuthHandlerOption) *AuthHandler {
+	h := &AuthHandler{
 		authService: authService,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// meResponse is the userinfo-style /me response when token claims are exposed
+type meResponse struct {
+	*models.User
+	Claims *utils.RegisteredTokenClaims `json:"claims"`
+}
+
+// MarshalJSON adds the claims alongside the user fields. Without it the
+// user's own MarshalJSON would be promoted and the claims dropped.
+func (r meResponse) MarshalJSON() ([]byte, error) {
+	userJSON, err := json.Marshal(r.User)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(userJSON, &fields); err != nil {
+		return nil, err
+	}
+
+	claimsJSON, err := json.Marshal(r.Claims)
+	if err != nil {
+		return nil, err
+	}
+	fields["claims"] = claimsJSON
+
+	return json.Marshal(fields)
 }
 
 // Register handles user registration
@@ -39,12 +114,22 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
 		})
 		return
 	}
 
 	// Call service
 	user, err := h.authService.Register(c.Request.Context(), &req)
+
+	// Existing accounts get the same response as new ones
+	if h.genericRegistration && (err == nil || isUserExists(err)) {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": genericRegistrationMessage,
+		})
+		return
+	}
+
 	if err != nil {
 		handleError(c, err)
 		return
@@ -66,12 +151,25 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
 		})
 		return
 	}
 
 	// Call service
-	response, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	var response *models.LoginResponse
+	var err error
+	opts := models.LoginOptions{
+		RememberMe: req.RememberMe,
+		BindToken:  req.BindToken,
+		DeviceID:   req.DeviceID,
+		DeviceType: req.DeviceType,
+	}
+	if opts != (models.LoginOptions{}) {
+		response, err = h.authService.LoginWithOptions(c.Request.Context(), req.Email, req.Password, opts)
+	} else {
+		response, err = h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	}
 	if err != nil {
 		handleError(c, err)
 		return
@@ -90,6 +188,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
 		})
 		return
 	}
@@ -105,61 +204,347 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// VerifyEmail verifies the email address a verification token was sent to
+// POST /auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
+		})
+		return
+	}
+
+	if err := h.authService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email verified",
+	})
+}
+
+// ResendVerification sends a new verification token. The response is the
+// same whether or not the email has an unverified account.
+// POST /auth/resend-verification
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req models.ResendVerificationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
+		})
+		return
+	}
+
+	if err := h.authService.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "if the email has an unverified account, a new verification link has been sent",
+	})
+}
+
 // GetMe returns the currently authenticated user
 // GET /auth/me
 func (h *AuthHandler) GetMe(c *gin.Context) {
-	// Extract token from Authorization header
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "authorization header is required",
+	user, claims, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	if h.exposeTokenClaims {
+		c.JSON(http.StatusOK, meResponse{User: user, Claims: claims})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateMe updates the caller's name, phone and address
+// PATCH /auth/me
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
 		})
 		return
 	}
 
-	// Parse Bearer token
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "invalid authorization header format",
+	updated, err := h.authService.UpdateProfile(c.Request.Context(), user, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// ExportMe returns all personal data held about the caller (GDPR access request)
+// GET /auth/me/export
+func (h *AuthHandler) ExportMe(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.authService.ExportUserData(c.Request.Context(), user)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Offer the bundle as a download and keep it out of shared caches
+	c.Header("Content-Disposition", `attachment; filename="personal-data.json"`)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, export)
+}
+
+// GetMySecurity returns the caller's account security summary
+// GET /auth/me/security
+func (h *AuthHandler) GetMySecurity(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.authService.SecuritySummary(c.Request.Context(), user)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetLoginActivity returns a page of the caller's recent login attempts
+// GET /auth/activity?limit=20&offset=0
+func (h *AuthHandler) GetLoginActivity(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	var query models.LoginActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid query: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
 		})
 		return
 	}
+	if query.Limit == 0 {
+		query.Limit = models.DefaultLoginActivityLimit
+	}
+
+	page, err := h.authService.LoginActivity(c.Request.Context(), user, query.Limit, query.Offset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, page)
+}
 
-	accessToken := parts[1]
+// CloseMe schedules the caller's account for closure after the cooling-off period
+// POST /auth/me/close
+func (h *AuthHandler) CloseMe(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
 
-	// Validate token and get user
-	user, err := h.authService.ValidateAccessToken(c.Request.Context(), accessToken)
+	closure, err := h.authService.RequestAccountClosure(c.Request.Context(), user)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	// Return user
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusAccepted, closure)
+}
+
+// CancelCloseMe withdraws the caller's pending account closure
+// POST /auth/me/close/cancel
+func (h *AuthHandler) CancelCloseMe(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.CancelAccountClosure(c.Request.Context(), user); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "account closure cancelled",
+	})
+}
+
+// ListSessions returns the caller's active sessions, flagging the current one
+// GET /auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	user, claims, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), user, claims)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession revokes one of the caller's sessions
+// DELETE /auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	user, _, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid session ID",
+			"code":  appErrors.CodeInvalidInput,
+		})
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), user, sessionID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "session revoked",
+	})
+}
+
+// RevokeOtherSessions revokes all of the caller's sessions except the current one
+// DELETE /auth/sessions
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	user, claims, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	revocation, err := h.authService.RevokeOtherSessions(c.Request.Context(), user, claims)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revocation)
+}
+
+// GetPasswordPolicy returns the rules new passwords must meet, so clients can
+// show the requirements the server actually enforces
+// GET /auth/password-policy
+func (h *AuthHandler) GetPasswordPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, h.authService.PasswordPolicy())
 }
 
-// Logout handles user logout
+// Logout revokes the caller's access token and, if one is sent in the body,
+// their refresh token
 // POST /auth/logout
-// Note: For JWT, logout is typically handled client-side by removing the token
-// This endpoint is here for completeness and can be extended with token blacklisting
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// In a production system, you might want to:
-	// 1. Add the token to a blacklist in Redis
-	// 2. Track logout events for audit
-	// 3. Revoke refresh tokens
+	_, claims, ok := currentCaller(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; without it only the access token is revoked
+	var req models.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid request body: " + err.Error(),
+				"code":  appErrors.CodeInvalidInput,
+			})
+			return
+		}
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), claims, req.RefreshToken); err != nil {
+		handleError(c, err)
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "logout successful",
 	})
 }
 
+// currentCaller returns the user and access token claims authenticated by
+// middleware.RequireAuth, responding 401 if the route isn't behind it
+func currentCaller(c *gin.Context) (*models.User, *utils.RegisteredTokenClaims, bool) {
+	user, ok := middleware.CurrentUser(c)
+	claims, claimsOK := middleware.CurrentTokenClaims(c)
+	if !ok || !claimsOK {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "authentication required",
+			"code":  appErrors.CodeUnauthorized,
+		})
+		return nil, nil, false
+	}
+	return user, claims, true
+}
+
+// isUserExists reports whether err is a conflict with an existing account
+func isUserExists(err error) bool {
+	appErr := appErrors.GetAppError(err)
+	return appErr != nil && appErr.Code == appErrors.CodeUserExists
+}
+
 // handleError maps service errors to HTTP responses
 func handleError(c *gin.Context, err error) {
+	// Field-level validation errors carry a message per field
+	if validationErr := appErrors.GetValidationError(err); validationErr != nil {
+		c.JSON(validationErr.StatusCode, gin.H{
+			"error":  validationErr.Message,
+			"code":   validationErr.Code,
+			"fields": validationErr.Fields,
+		})
+		return
+	}
+
+	// Password policy errors list every rule the password failed
+	if policyErr := appErrors.GetPasswordPolicyError(err); policyErr != nil {
+		c.JSON(policyErr.StatusCode, gin.H{
+			"error":      policyErr.Message,
+			"code":       policyErr.Code,
+			"violations": policyErr.Violations,
+		})
+		return
+	}
+
 	// Check if it's an AppError
 	if appErr := appErrors.GetAppError(err); appErr != nil {
 		c.JSON(appErr.StatusCode, gin.H{
 			"error": appErr.Message,
+			"code":  appErr.Code,
 		})
 		return
 	}
@@ -167,5 +552,6 @@ func handleError(c *gin.Context, err error) {
 	// Default to internal server error
 	c.JSON(http.StatusInternalServerError, gin.H{
 		"error": "an unexpected error occurred",
+		"code":  appErrors.CodeInternal,
 	})
 }
@@ -12,7 +12,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/middleware"
 	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
 	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -40,6 +42,14 @@ func (m *MockAuthService) Login(ctx context.Context, email, password string) (*m
 	return args.Get(0).(*models.LoginResponse), args.Error(1)
 }
 
+func (m *MockAuthService) LoginWithOptions(ctx context.Context, email, password string, opts models.LoginOptions) (*models.LoginResponse, error) {
+	args := m.Called(ctx, email, password, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginResponse), args.Error(1)
+}
+
 func (m *MockAuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.RefreshTokenResponse, error) {
 	args := m.Called(ctx, refreshToken)
 	if args.Get(0) == nil {
@@ -48,6 +58,11 @@ func (m *MockAuthService) RefreshToken(ctx context.Context, refreshToken string)
 	return args.Get(0).(*models.RefreshTokenResponse), args.Error(1)
 }
 
+func (m *MockAuthService) Logout(ctx context.Context, accessClaims *utils.RegisteredTokenClaims, refreshToken string) error {
+	args := m.Called(ctx, accessClaims, refreshToken)
+	return args.Error(0)
+}
+
 func (m *MockAuthService) ValidateAccessToken(ctx context.Context, accessToken string) (*models.User, error) {
 	args := m.Called(ctx, accessToken)
 	if args.Get(0) == nil {
@@ -56,12 +71,117 @@ func (m *MockAuthService) ValidateAccessToken(ctx context.Context, accessToken s
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockAuthService) ValidateAccessTokenWithClaims(ctx context.Context, accessToken string) (*models.User, *utils.RegisteredTokenClaims, error) {
+	args := m.Called(ctx, accessToken)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.User), args.Get(1).(*utils.RegisteredTokenClaims), args.Error(2)
+}
+
+func (m *MockAuthService) ExportUserData(ctx context.Context, user *models.User) (*models.DataExport, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataExport), args.Error(1)
+}
+
+func (m *MockAuthService) UpdateProfile(ctx context.Context, caller *models.User, req *models.UpdateProfileRequest) (*models.User, error) {
+	args := m.Called(ctx, caller, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) SecuritySummary(ctx context.Context, user *models.User) (*models.SecuritySummary, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SecuritySummary), args.Error(1)
+}
+
+func (m *MockAuthService) LoginActivity(ctx context.Context, user *models.User, limit, offset int) (*models.LoginActivityPage, error) {
+	args := m.Called(ctx, user, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginActivityPage), args.Error(1)
+}
+
+func (m *MockAuthService) RequestAccountClosure(ctx context.Context, user *models.User) (*models.AccountClosure, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AccountClosure), args.Error(1)
+}
+
+func (m *MockAuthService) CancelAccountClosure(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ListSessions(ctx context.Context, user *models.User, claims *utils.RegisteredTokenClaims) ([]*models.Session, error) {
+	args := m.Called(ctx, user, claims)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Session), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, user *models.User, sessionID uuid.UUID) error {
+	args := m.Called(ctx, user, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) RevokeOtherSessions(ctx context.Context, user *models.User, claims *utils.RegisteredTokenClaims) (*models.SessionRevocation, error) {
+	args := m.Called(ctx, user, claims)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SessionRevocation), args.Error(1)
+}
+
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) ResendVerification(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) PasswordPolicy() models.PasswordPolicy {
+	args := m.Called()
+	return args.Get(0).(models.PasswordPolicy)
+}
+
 // setupTestRouter creates a test router with Gin
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
 }
 
+// expectCaller makes RequireAuth accept "valid-access-token", returning the
+// user and claims it puts in the context
+func expectCaller(m *MockAuthService) (*models.User, *utils.RegisteredTokenClaims) {
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+	claims := &utils.RegisteredTokenClaims{
+		TokenClaims: utils.TokenClaims{UserID: user.ID.String(), Email: user.Email, TokenType: utils.TokenTypeAccess},
+		ID:          uuid.NewString(),
+	}
+	m.On("ValidateAccessTokenWithClaims", mock.Anything, "valid-access-token").Return(user, claims, nil)
+	return user, claims
+}
+
 // TestRegisterHandler tests the register endpoint
 func TestRegisterHandler(t *testing.T) {
 	tests := []struct {
@@ -91,7 +211,7 @@ func TestRegisterHandler(t *testing.T) {
 					Email:     "john.doe@example.com",
 					FirstName: "John",
 					LastName:  "Doe",
-					IsActive:  true,
+					Status:    models.UserStatusActive,
 					KYCStatus: "pending",
 				}
 				m.On("Register", mock.Anything, mock.AnythingOfType("*models.RegisterRequest")).Return(user, nil)
@@ -171,6 +291,37 @@ func TestRegisterHandler(t *testing.T) {
 				assert.Contains(t, response["error"], "password")
 			},
 		},
+		{
+			name: "field validation errors",
+			requestBody: models.RegisterRequest{
+				Email:        "john.doe@example.com",
+				Phone:        "+447700900123",
+				Password:     "SecurePass123!",
+				FirstName:    "J0hn",
+				LastName:     "Doe",
+				DateOfBirth:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+				AddressLine1: "123 Main St",
+				City:         "London",
+				Postcode:     "SW1A 1AA",
+				Country:      "UK",
+			},
+			setupMock: func(m *MockAuthService) {
+				validationErr := appErrors.NewValidationError()
+				validationErr.Add("first_name", "first name contains invalid characters")
+				m.On("Register", mock.Anything, mock.AnythingOfType("*models.RegisterRequest")).
+					Return(nil, validationErr)
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response map[string]interface{}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "validation failed", response["error"])
+				fields, ok := response["fields"].(map[string]interface{})
+				require.True(t, ok)
+				assert.Equal(t, "first name contains invalid characters", fields["first_name"])
+			},
+		},
 		{
 			name:           "malformed JSON",
 			requestBody:    `{invalid json}`,
@@ -219,6 +370,55 @@ func TestRegisterHandler(t *testing.T) {
 	}
 }
 
+// TestRegisterHandlerGenericResponse tests that generic mode hides whether an email is registered
+func TestRegisterHandlerGenericResponse(t *testing.T) {
+	request := models.RegisterRequest{
+		Email:        "john.doe@example.com",
+		Phone:        "+447700900123",
+		Password:     "SecurePass123!",
+		FirstName:    "John",
+		LastName:     "Doe",
+		DateOfBirth:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		AddressLine1: "123 Main St",
+		City:         "London",
+		Postcode:     "SW1A 1AA",
+		Country:      "GB",
+	}
+
+	register := func(serviceErr error) *httptest.ResponseRecorder {
+		mockService := new(MockAuthService)
+		if serviceErr != nil {
+			mockService.On("Register", mock.Anything, mock.AnythingOfType("*models.RegisterRequest")).Return(nil, serviceErr)
+		} else {
+			mockService.On("Register", mock.Anything, mock.AnythingOfType("*models.RegisterRequest")).Return(&models.User{ID: uuid.New(), Email: request.Email}, nil)
+		}
+
+		handler := NewAuthHandler(mockService, WithGenericRegistrationResponse(true))
+		router := setupTestRouter()
+		router.POST("/auth/register", handler.Register)
+
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	newEmail := register(nil)
+	existingEmail := register(appErrors.NewConflict("user with this email already exists"))
+
+	assert.Equal(t, http.StatusAccepted, newEmail.Code)
+	assert.Equal(t, http.StatusAccepted, existingEmail.Code)
+	assert.JSONEq(t, newEmail.Body.String(), existingEmail.Body.String())
+	assert.NotContains(t, newEmail.Body.String(), request.Email)
+
+	// Other errors are still reported
+	invalid := register(appErrors.NewBadRequest("password too long"))
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+}
+
 // TestLoginHandler tests the login endpoint
 func TestLoginHandler(t *testing.T) {
 	tests := []struct {
@@ -433,6 +633,80 @@ func TestRefreshTokenHandler(t *testing.T) {
 	}
 }
 
+// TestLogoutHandler tests revoking the caller's tokens on logout
+func TestLogoutHandler(t *testing.T) {
+	t.Run("revokes the access and refresh tokens", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		_, claims := expectCaller(mockService)
+		mockService.On("Logout", mock.Anything, claims, "valid-refresh-token").Return(nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/logout", middleware.RequireAuth(mockService), handler.Logout)
+
+		body, _ := json.Marshal(models.LogoutRequest{RefreshToken: "valid-refresh-token"})
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("body is optional", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		_, claims := expectCaller(mockService)
+		mockService.On("Logout", mock.Anything, claims, "").Return(nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/logout", middleware.RequireAuth(mockService), handler.Logout)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		mockService := new(MockAuthService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/logout", middleware.RequireAuth(mockService), handler.Logout)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockService.AssertNotCalled(t, "Logout", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		mockService.On("ValidateAccessTokenWithClaims", mock.Anything, "revoked-token").
+			Return(nil, nil, appErrors.NewUnauthorized("token has been revoked"))
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/logout", middleware.RequireAuth(mockService), handler.Logout)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+		req.Header.Set("Authorization", "Bearer revoked-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockService.AssertNotCalled(t, "Logout", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 // TestGetMeHandler tests the /auth/me endpoint
 func TestGetMeHandler(t *testing.T) {
 	tests := []struct {
@@ -451,9 +725,9 @@ func TestGetMeHandler(t *testing.T) {
 					Email:     "john.doe@example.com",
 					FirstName: "John",
 					LastName:  "Doe",
-					IsActive:  true,
+					Status:    models.UserStatusActive,
 				}
-				m.On("ValidateAccessToken", mock.Anything, "valid-access-token").Return(user, nil)
+				m.On("ValidateAccessTokenWithClaims", mock.Anything, "valid-access-token").Return(user, &utils.RegisteredTokenClaims{}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -488,12 +762,43 @@ func TestGetMeHandler(t *testing.T) {
 				assert.NotEmpty(t, response["error"])
 			},
 		},
+		{
+			name:       "lowercase bearer scheme",
+			authHeader: "bearer valid-access-token",
+			setupMock: func(m *MockAuthService) {
+				user := &models.User{
+					ID:       uuid.New(),
+					Email:    "john.doe@example.com",
+					Status:   models.UserStatusActive,
+				}
+				m.On("ValidateAccessTokenWithClaims", mock.Anything, "valid-access-token").Return(user, &utils.RegisteredTokenClaims{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var user models.User
+				err := json.Unmarshal(rec.Body.Bytes(), &user)
+				require.NoError(t, err)
+				assert.NotEmpty(t, user.ID)
+			},
+		},
+		{
+			name:       "wrong scheme",
+			authHeader: "Basic dXNlcjpwYXNz",
+			setupMock:  func(m *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response map[string]interface{}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Contains(t, response["error"], "Bearer")
+			},
+		},
 		{
 			name:       "expired token",
 			authHeader: "Bearer expired-token",
 			setupMock: func(m *MockAuthService) {
-				m.On("ValidateAccessToken", mock.Anything, "expired-token").
-					Return(nil, appErrors.NewUnauthorized("token has expired"))
+				m.On("ValidateAccessTokenWithClaims", mock.Anything, "expired-token").
+					Return(nil, nil, appErrors.NewUnauthorized("token has expired"))
 			},
 			expectedStatus: http.StatusUnauthorized,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -512,7 +817,7 @@ func TestGetMeHandler(t *testing.T) {
 			tt.setupMock(mockService)
 			handler := NewAuthHandler(mockService)
 			router := setupTestRouter()
-			router.GET("/auth/me", handler.GetMe)
+			router.GET("/auth/me", middleware.RequireAuth(mockService), handler.GetMe)
 
 			// Create request
 			req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
@@ -532,42 +837,494 @@ func TestGetMeHandler(t *testing.T) {
 	}
 }
 
+// TestGetMeHandlerWithClaims tests /me exposing registered token claims
+func TestGetMeHandlerWithClaims(t *testing.T) {
+	userID := uuid.New()
+	user := &models.User{
+		ID:       userID,
+		Email:    "john.doe@example.com",
+		Status:   models.UserStatusActive,
+	}
+	claims := &utils.RegisteredTokenClaims{
+		TokenClaims: utils.TokenClaims{
+			UserID:    userID.String(),
+			Email:     user.Email,
+			TokenType: "access",
+		},
+		Subject:   userID.String(),
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(15 * time.Minute).Unix(),
+	}
+
+	mockService := new(MockAuthService)
+	mockService.On("ValidateAccessTokenWithClaims", mock.Anything, "valid-access-token").Return(user, claims, nil)
+
+	handler := NewAuthHandler(mockService, WithTokenClaimsInMe(true))
+	router := setupTestRouter()
+	router.GET("/auth/me", middleware.RequireAuth(mockService), handler.GetMe)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer valid-access-token")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, userID.String(), response["id"])
+	assert.Equal(t, user.Email, response["email"])
+
+	responseClaims, ok := response["claims"].(map[string]interface{})
+	require.True(t, ok, "claims should be present")
+	assert.Equal(t, userID.String(), responseClaims["sub"])
+	assert.NotZero(t, responseClaims["exp"])
+	assert.NotZero(t, responseClaims["iat"])
+	mockService.AssertExpectations(t)
+}
+
+// TestExportMeHandler tests the GDPR personal data export endpoint
+func TestExportMeHandler(t *testing.T) {
+	userID := uuid.New()
+	export := &models.DataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: &models.User{
+			ID:           userID,
+			Email:        "john.doe@example.com",
+			PasswordHash: "$2a$10$abcdefghijklmnopqrstuv",
+			KYCStatus:    "pending",
+		},
+		KYC: models.KYCExport{Status: "pending"},
+		AuditEvents: []*models.AuditEvent{
+			{ID: uuid.New(), UserID: &userID, EventType: models.AuditEventLoginSuccess},
+		},
+	}
+
+	t.Run("returns the export as a download", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("ExportUserData", mock.Anything, caller).Return(export, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/me/export", middleware.RequireAuth(mockService), handler.ExportMe)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/me/export", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+		assert.NotContains(t, rec.Body.String(), "password")
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		profile, ok := response["profile"].(map[string]interface{})
+		require.True(t, ok, "profile section should be present")
+		assert.Equal(t, userID.String(), profile["id"])
+
+		auditEvents, ok := response["audit_events"].([]interface{})
+		require.True(t, ok, "audit_events section should be present")
+		assert.Len(t, auditEvents, 1)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		mockService := new(MockAuthService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/me/export", middleware.RequireAuth(mockService), handler.ExportMe)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/me/export", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockService.AssertNotCalled(t, "ExportUserData", mock.Anything, mock.Anything)
+	})
+}
+
+// TestGetMySecurityHandler tests returning the caller's security summary
+func TestGetMySecurityHandler(t *testing.T) {
+	summary := &models.SecuritySummary{
+		LastLogin: &models.LoginRecord{
+			At:        time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			IPAddress: "203.0.113.7",
+		},
+		FailedLoginsSinceLastLogin: 2,
+	}
+
+	t.Run("returns the summary", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("SecuritySummary", mock.Anything, caller).Return(summary, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/me/security", middleware.RequireAuth(mockService), handler.GetMySecurity)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/me/security", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		lastLogin, ok := response["last_login"].(map[string]interface{})
+		require.True(t, ok, "last_login should be present")
+		assert.Equal(t, "203.0.113.7", lastLogin["ip_address"])
+		assert.Equal(t, float64(2), response["failed_logins_since_last_login"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		mockService := new(MockAuthService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/me/security", middleware.RequireAuth(mockService), handler.GetMySecurity)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/me/security", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockService.AssertNotCalled(t, "SecuritySummary", mock.Anything, mock.Anything)
+	})
+}
+
+// TestGetLoginActivityHandler tests paging the caller's login activity
+func TestGetLoginActivityHandler(t *testing.T) {
+	page := &models.LoginActivityPage{
+		Activity: []*models.LoginActivity{
+			{At: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Result: models.LoginResultFailure, IPAddress: "198.51.100.1"},
+			{At: time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), Result: models.LoginResultSuccess, IPAddress: "203.0.113.7"},
+		},
+		Limit: 20,
+	}
+
+	t.Run("defaults the page size", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("LoginActivity", mock.Anything, caller, models.DefaultLoginActivityLimit, 0).Return(page, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/activity", middleware.RequireAuth(mockService), handler.GetLoginActivity)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/activity", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		activity, ok := response["activity"].([]interface{})
+		require.True(t, ok, "activity should be a list")
+		require.Len(t, activity, 2)
+		assert.Equal(t, "failure", activity[0].(map[string]interface{})["result"])
+		assert.Equal(t, "success", activity[1].(map[string]interface{})["result"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("passes limit and offset", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("LoginActivity", mock.Anything, caller, 5, 10).Return(page, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/activity", middleware.RequireAuth(mockService), handler.GetLoginActivity)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/activity?limit=5&offset=10", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("rejects an out of range limit", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		expectCaller(mockService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/activity", middleware.RequireAuth(mockService), handler.GetLoginActivity)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/activity?limit=500", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertNotCalled(t, "LoginActivity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		mockService := new(MockAuthService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/activity", middleware.RequireAuth(mockService), handler.GetLoginActivity)
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/activity", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+// TestAccountClosureHandlers tests scheduling and cancelling account closure
+func TestAccountClosureHandlers(t *testing.T) {
+	requestedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	closure := &models.AccountClosure{
+		RequestedAt: requestedAt,
+		ClosesAt:    requestedAt.Add(14 * 24 * time.Hour),
+	}
+
+	t.Run("close schedules closure", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("RequestAccountClosure", mock.Anything, caller).Return(closure, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/me/close", middleware.RequireAuth(mockService), handler.CloseMe)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/me/close", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusAccepted, rec.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "2024-03-01T12:00:00Z", response["requested_at"])
+		assert.Equal(t, "2024-03-15T12:00:00Z", response["closes_at"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("cancel withdraws closure", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("CancelAccountClosure", mock.Anything, caller).Return(nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/me/close/cancel", middleware.RequireAuth(mockService), handler.CancelCloseMe)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/me/close/cancel", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("cancel without pending closure", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("CancelAccountClosure", mock.Anything, caller).
+			Return(appErrors.NewNotFound("no pending account closure"))
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/me/close/cancel", middleware.RequireAuth(mockService), handler.CancelCloseMe)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/me/close/cancel", nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		mockService := new(MockAuthService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.POST("/auth/me/close", middleware.RequireAuth(mockService), handler.CloseMe)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/me/close", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockService.AssertNotCalled(t, "RequestAccountClosure", mock.Anything, mock.Anything)
+	})
+}
+
+// TestGetPasswordPolicyHandler tests publishing the configured password policy
+func TestGetPasswordPolicyHandler(t *testing.T) {
+	policy := models.PasswordPolicy{
+		MinLength:           12,
+		MaxBytes:            64,
+		RequireUppercase:    true,
+		RequireLowercase:    true,
+		RequireDigit:        true,
+		RequireSpecial:      false,
+		SpecialCharacters:   "!@#",
+		CommonPasswordCheck: true,
+	}
+
+	mockService := new(MockAuthService)
+	mockService.On("PasswordPolicy").Return(policy)
+
+	handler := NewAuthHandler(mockService)
+	router := setupTestRouter()
+	router.GET("/auth/password-policy", handler.GetPasswordPolicy)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/password-policy", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response models.PasswordPolicy
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, policy, response)
+	assert.Contains(t, rec.Body.String(), `"breach_check":false`)
+	mockService.AssertExpectations(t)
+}
+
+// TestEmailVerificationHandlers tests the verify and resend endpoints
+func TestEmailVerificationHandlers(t *testing.T) {
+	post := func(handler gin.HandlerFunc, path string, body interface{}) *httptest.ResponseRecorder {
+		router := setupTestRouter()
+		router.POST(path, handler)
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("verify", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		mockService.On("VerifyEmail", mock.Anything, "valid-token").Return(nil)
+		handler := NewAuthHandler(mockService)
+
+		rec := post(handler.VerifyEmail, "/auth/verify-email", models.VerifyEmailRequest{Token: "valid-token"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "email verified")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("verify with invalid token", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		mockService.On("VerifyEmail", mock.Anything, "used-token").
+			Return(appErrors.NewBadRequest("invalid or expired verification token"))
+		handler := NewAuthHandler(mockService)
+
+		rec := post(handler.VerifyEmail, "/auth/verify-email", models.VerifyEmailRequest{Token: "used-token"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid or expired verification token")
+	})
+
+	t.Run("verify without token", func(t *testing.T) {
+		handler := NewAuthHandler(new(MockAuthService))
+
+		rec := post(handler.VerifyEmail, "/auth/verify-email", map[string]string{})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("resend", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		mockService.On("ResendVerification", mock.Anything, "john.doe@example.com").Return(nil)
+		handler := NewAuthHandler(mockService)
+
+		rec := post(handler.ResendVerification, "/auth/resend-verification",
+			models.ResendVerificationRequest{Email: "john.doe@example.com"})
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("resend with invalid email", func(t *testing.T) {
+		handler := NewAuthHandler(new(MockAuthService))
+
+		rec := post(handler.ResendVerification, "/auth/resend-verification",
+			models.ResendVerificationRequest{Email: "not-an-email"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
 // TestErrorHandling tests error response formatting
 func TestErrorHandling(t *testing.T) {
 	tests := []struct {
 		name         string
 		serviceError error
 		expectedCode int
+		errorCode    appErrors.ErrorCode
 	}{
 		{
 			name:         "bad request error",
 			serviceError: appErrors.NewBadRequest("invalid input"),
 			expectedCode: http.StatusBadRequest,
+			errorCode:    appErrors.CodeInvalidInput,
 		},
 		{
 			name:         "unauthorized error",
 			serviceError: appErrors.NewUnauthorized("invalid credentials"),
 			expectedCode: http.StatusUnauthorized,
+			errorCode:    appErrors.CodeUnauthorized,
+		},
+		{
+			name:         "invalid credentials error",
+			serviceError: appErrors.NewInvalidCredentials("invalid email or password"),
+			expectedCode: http.StatusUnauthorized,
+			errorCode:    appErrors.CodeInvalidCredentials,
+		},
+		{
+			name:         "too many requests error",
+			serviceError: appErrors.NewTooManyRequests("too many attempts"),
+			expectedCode: http.StatusTooManyRequests,
+			errorCode:    appErrors.CodeRateLimited,
 		},
 		{
 			name:         "forbidden error",
 			serviceError: appErrors.NewForbidden("access denied"),
 			expectedCode: http.StatusForbidden,
+			errorCode:    appErrors.CodeForbidden,
 		},
 		{
 			name:         "not found error",
 			serviceError: appErrors.NewNotFound("user not found"),
 			expectedCode: http.StatusNotFound,
+			errorCode:    appErrors.CodeNotFound,
 		},
 		{
 			name:         "conflict error",
 			serviceError: appErrors.NewConflict("user already exists"),
 			expectedCode: http.StatusConflict,
+			errorCode:    appErrors.CodeUserExists,
 		},
 		{
 			name:         "generic error",
 			serviceError: errors.New("some internal error"),
 			expectedCode: http.StatusInternalServerError,
+			errorCode:    appErrors.CodeInternal,
 		},
 	}
 
@@ -598,6 +1355,154 @@ func TestErrorHandling(t *testing.T) {
 			err := json.Unmarshal(rec.Body.Bytes(), &response)
 			require.NoError(t, err)
 			assert.NotEmpty(t, response["error"])
+			assert.Equal(t, string(tt.errorCode), response["code"])
 		})
 	}
 }
+
+// TestUpdateMeHandler tests updating the caller's profile
+func TestUpdateMeHandler(t *testing.T) {
+	t.Run("returns the updated user", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("UpdateProfile", mock.Anything, caller, mock.MatchedBy(func(req *models.UpdateProfileRequest) bool {
+			return req.City != nil && *req.City == "Manchester" && req.FirstName == nil
+		})).Return(&models.User{Email: "john.doe@example.com", City: "Manchester"}, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.PATCH("/auth/me", middleware.RequireAuth(mockService), handler.UpdateMe)
+
+		req := httptest.NewRequest(http.MethodPatch, "/auth/me", bytes.NewBufferString(`{"city": "Manchester", "email": "new@example.com"}`))
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Manchester", response["city"])
+		assert.Equal(t, "john.doe@example.com", response["email"])
+		assert.NotContains(t, response, "password_hash")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("null and omitted fields are unset, empty strings are kept", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("UpdateProfile", mock.Anything, caller, mock.MatchedBy(func(req *models.UpdateProfileRequest) bool {
+			return req.Region == nil && req.City == nil &&
+				req.AddressLine2 != nil && *req.AddressLine2 == ""
+		})).Return(&models.User{Email: "john.doe@example.com"}, nil)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.PATCH("/auth/me", middleware.RequireAuth(mockService), handler.UpdateMe)
+
+		req := httptest.NewRequest(http.MethodPatch, "/auth/me", bytes.NewBufferString(`{"region": null, "address_line2": ""}`))
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing authorization header", func(t *testing.T) {
+		mockService := new(MockAuthService)
+
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.PATCH("/auth/me", middleware.RequireAuth(mockService), handler.UpdateMe)
+
+		req := httptest.NewRequest(http.MethodPatch, "/auth/me", bytes.NewBufferString(`{"city": "Manchester"}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		mockService.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestSessionHandlers tests listing and revoking the caller's sessions
+func TestSessionHandlers(t *testing.T) {
+	sessionID := uuid.New()
+
+	newRouter := func(mockService *MockAuthService) *gin.Engine {
+		handler := NewAuthHandler(mockService)
+		router := setupTestRouter()
+		router.GET("/auth/sessions", middleware.RequireAuth(mockService), handler.ListSessions)
+		router.DELETE("/auth/sessions", middleware.RequireAuth(mockService), handler.RevokeOtherSessions)
+		router.DELETE("/auth/sessions/:id", middleware.RequireAuth(mockService), handler.RevokeSession)
+		return router
+	}
+
+	serve := func(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer valid-access-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("lists sessions with the current one flagged", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, claims := expectCaller(mockService)
+		mockService.On("ListSessions", mock.Anything, caller, claims).Return([]*models.Session{
+			{ID: sessionID, DeviceID: "phone-1", DeviceType: "ios", Current: true},
+		}, nil)
+
+		rec := serve(newRouter(mockService), http.MethodGet, "/auth/sessions")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Sessions []map[string]interface{} `json:"sessions"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Sessions, 1)
+		assert.Equal(t, sessionID.String(), response.Sessions[0]["id"])
+		assert.Equal(t, true, response.Sessions[0]["current"])
+		assert.NotContains(t, response.Sessions[0], "user_id")
+	})
+
+	t.Run("revokes a session", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("RevokeSession", mock.Anything, caller, sessionID).Return(nil)
+
+		rec := serve(newRouter(mockService), http.MethodDelete, "/auth/sessions/"+sessionID.String())
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unknown session is not found", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, _ := expectCaller(mockService)
+		mockService.On("RevokeSession", mock.Anything, caller, sessionID).Return(appErrors.NewNotFound("session not found"))
+
+		rec := serve(newRouter(mockService), http.MethodDelete, "/auth/sessions/"+sessionID.String())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid session ID", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		expectCaller(mockService)
+
+		rec := serve(newRouter(mockService), http.MethodDelete, "/auth/sessions/not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("revokes other sessions", func(t *testing.T) {
+		mockService := new(MockAuthService)
+		caller, claims := expectCaller(mockService)
+		mockService.On("RevokeOtherSessions", mock.Anything, caller, claims).Return(&models.SessionRevocation{Revoked: 2}, nil)
+
+		rec := serve(newRouter(mockService), http.MethodDelete, "/auth/sessions")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"revoked": 2}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// DebugHandler serves developer-only endpoints. Routes are only registered
+// outside production, and each handler refuses to run in production as well.
+type DebugHandler struct {
+	environment string
+}
+
+// NewDebugHandler creates a new debug handler
+func NewDebugHandler(environment string) *DebugHandler {
+	return &DebugHandler{environment: environment}
+}
+
+// DecodeTokenRequest represents a token decoding request
+type DecodeTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// DecodeTokenResponse holds a token's unverified header and claims
+type DecodeTokenResponse struct {
+	Header   map[string]interface{} `json:"header"`
+	Claims   map[string]interface{} `json:"claims"`
+	Verified bool                   `json:"verified"`
+}
+
+// DecodeToken returns the decoded header and claims of a token without
+// verifying it, for inspecting tokens during local development
+// POST /debug/decode-token
+func (h *DebugHandler) DecodeToken(c *gin.Context) {
+	if h.environment == "production" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "debug endpoints are disabled in production",
+			"code":  appErrors.CodeForbidden,
+		})
+		return
+	}
+
+	var req DecodeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body: " + err.Error(),
+			"code":  appErrors.CodeInvalidInput,
+		})
+		return
+	}
+
+	header, claims, err := utils.DecodeTokenUnverified(req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  appErrors.CodeInvalidInput,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, DecodeTokenResponse{
+		Header:   header,
+		Claims:   claims,
+		Verified: false,
+	})
+}
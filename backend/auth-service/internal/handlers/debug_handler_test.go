@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeTokenHandler tests the developer-only token decoding endpoint
+func TestDecodeTokenHandler(t *testing.T) {
+	userID := uuid.New().String()
+	token, err := utils.GenerateAccessToken(userID, "john.doe@example.com", 15*time.Minute, "test-secret-key-at-least-32-chars-long-for-security")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		environment string
+		body        string
+		wantStatus  int
+	}{
+		{
+			name:        "decodes token in development",
+			environment: "development",
+			body:        `{"token":"` + token + `"}`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "refused in production",
+			environment: "production",
+			body:        `{"token":"` + token + `"}`,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "malformed token",
+			environment: "development",
+			body:        `{"token":"not-a-token"}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "missing token",
+			environment: "development",
+			body:        `{}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewDebugHandler(tt.environment)
+			router := setupTestRouter()
+			router.POST("/debug/decode-token", handler.DecodeToken)
+
+			req := httptest.NewRequest(http.MethodPost, "/debug/decode-token", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+			if tt.wantStatus != http.StatusOK {
+				assert.NotEmpty(t, response["code"])
+				assert.NotContains(t, rec.Body.String(), userID)
+				return
+			}
+
+			header := response["header"].(map[string]interface{})
+			claims := response["claims"].(map[string]interface{})
+			assert.Equal(t, "HS256", header["alg"])
+			assert.Equal(t, userID, claims["sub"])
+			assert.Equal(t, "access", claims["token_type"])
+			assert.Equal(t, false, response["verified"])
+		})
+	}
+}
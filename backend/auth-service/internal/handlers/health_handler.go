@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -11,36 +12,90 @@ import (
 type HealthHandler struct {
 	startTime time.Time
 	version   string
+	readiness *readinessCache
+	optional  []optionalDependency
+}
+
+// optionalDependency is a subsystem the core auth flow can run without.
+// When it is down, the service is degraded rather than not ready.
+type optionalDependency struct {
+	name  string
+	check *readinessCache
+}
+
+// HealthHandlerOption configures optional HealthHandler behaviour
+type HealthHandlerOption func(*HealthHandler)
+
+// WithReadinessCheck makes /ready ping the dependency, caching the result
+// for cacheTTL so frequent probes don't add load
+func WithReadinessCheck(pinger Pinger, cacheTTL time.Duration) HealthHandlerOption {
+	return func(h *HealthHandler) {
+		h.readiness = newReadinessCache(pinger, cacheTTL)
+	}
+}
+
+// WithOptionalDependency reports the dependency under name in /health's
+// unavailable list when its ping fails. Results are cached for cacheTTL like
+// the readiness check, but a failure only marks the service degraded.
+func WithOptionalDependency(name string, pinger Pinger, cacheTTL time.Duration) HealthHandlerOption {
+	return func(h *HealthHandler) {
+		h.optional = append(h.optional, optionalDependency{
+			name:  name,
+			check: newReadinessCache(pinger, cacheTTL),
+		})
+	}
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(version string) *HealthHandler {
-	return &HealthHandler{
+func NewHealthHandler(version string, opts ...HealthHandlerOption) *HealthHandler {
+	h := &HealthHandler{
 		startTime: time.Now(),
 		version:   version,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string        `json:"status"`
-	Service   string        `json:"service"`
-	Version   string        `json:"version"`
-	Uptime    string        `json:"uptime"`
-	Timestamp time.Time     `json:"timestamp"`
+	Status    string    `json:"status"`
+	Service   string    `json:"service"`
+	Version   string    `json:"version"`
+	Uptime    string    `json:"uptime"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Degraded is true when optional subsystems are down but core auth still works
+	Degraded    bool     `json:"degraded"`
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// Unavailable returns the names of optional dependencies that are currently down
+func (h *HealthHandler) Unavailable(ctx context.Context) []string {
+	var unavailable []string
+	for _, dep := range h.optional {
+		if err := dep.check.Check(ctx); err != nil {
+			unavailable = append(unavailable, dep.name)
+		}
+	}
+	return unavailable
 }
 
 // Health returns the service health status
 // GET /health
 func (h *HealthHandler) Health(c *gin.Context) {
 	uptime := time.Since(h.startTime)
+	unavailable := h.Unavailable(c.Request.Context())
 
 	response := HealthResponse{
-		Status:    "healthy",
-		Service:   "auth-service",
-		Version:   h.version,
-		Uptime:    uptime.String(),
-		Timestamp: time.Now().UTC(),
+		Status:      "healthy",
+		Service:     "auth-service",
+		Version:     h.version,
+		Uptime:      uptime.String(),
+		Timestamp:   time.Now().UTC(),
+		Degraded:    len(unavailable) > 0,
+		Unavailable: unavailable,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -49,14 +104,26 @@ func (h *HealthHandler) Health(c *gin.Context) {
 // Ready returns readiness status (used by Kubernetes)
 // GET /ready
 func (h *HealthHandler) Ready(c *gin.Context) {
-	// In a production system, you would check:
-	// - Database connectivity
-	// - Redis connectivity
-	// - Any critical dependencies
+	if h.readiness != nil {
+		if err := h.readiness.Check(c.Request.Context()); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "not ready",
+				"error":  "database unavailable",
+			})
+			return
+		}
+	}
 
-	c.JSON(http.StatusOK, gin.H{
+	// Optional dependencies never fail readiness; they are only reported
+	response := gin.H{
 		"status": "ready",
-	})
+	}
+	if unavailable := h.Unavailable(c.Request.Context()); len(unavailable) > 0 {
+		response["degraded"] = true
+		response["unavailable"] = unavailable
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // Live returns liveness status (used by Kubernetes)
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -125,3 +129,137 @@ func TestHealthHandlerMultipleCalls(t *testing.T) {
 	assert.NotEqual(t, response1.Uptime, response2.Uptime)
 	assert.True(t, response2.Timestamp.After(response1.Timestamp))
 }
+
+// countingPinger counts pings and returns a configurable error
+type countingPinger struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+	delay time.Duration
+}
+
+func (p *countingPinger) Ping(ctx context.Context) error {
+	time.Sleep(p.delay)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return p.err
+}
+
+func (p *countingPinger) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// TestReadyHandlerCaching tests that readiness checks are cached
+func TestReadyHandlerCaching(t *testing.T) {
+	probe := func(router *gin.Engine) int {
+		req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("database is not re-pinged within cache window", func(t *testing.T) {
+		pinger := &countingPinger{}
+		handler := NewHealthHandler("1.0.0", WithReadinessCheck(pinger, time.Minute))
+		router := setupTestRouter()
+		router.GET("/ready", handler.Ready)
+
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusOK, probe(router))
+		}
+		assert.Equal(t, 1, pinger.Calls())
+	})
+
+	t.Run("database is re-pinged after cache expiry", func(t *testing.T) {
+		pinger := &countingPinger{}
+		handler := NewHealthHandler("1.0.0", WithReadinessCheck(pinger, 10*time.Millisecond))
+		router := setupTestRouter()
+		router.GET("/ready", handler.Ready)
+
+		assert.Equal(t, http.StatusOK, probe(router))
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, http.StatusOK, probe(router))
+		assert.Equal(t, 2, pinger.Calls())
+	})
+
+	t.Run("database failure is reported as not ready", func(t *testing.T) {
+		pinger := &countingPinger{err: errors.New("connection refused")}
+		handler := NewHealthHandler("1.0.0", WithReadinessCheck(pinger, time.Minute))
+		router := setupTestRouter()
+		router.GET("/ready", handler.Ready)
+
+		assert.Equal(t, http.StatusServiceUnavailable, probe(router))
+		assert.Equal(t, http.StatusServiceUnavailable, probe(router))
+		assert.Equal(t, 1, pinger.Calls())
+	})
+
+	t.Run("concurrent probes share one check", func(t *testing.T) {
+		pinger := &countingPinger{delay: 20 * time.Millisecond}
+		handler := NewHealthHandler("1.0.0", WithReadinessCheck(pinger, time.Minute))
+		router := setupTestRouter()
+		router.GET("/ready", handler.Ready)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, http.StatusOK, probe(router))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, pinger.Calls())
+	})
+}
+
+// TestHealthHandlerDegraded tests that a down optional dependency marks the
+// service degraded without failing health or readiness
+func TestHealthHandlerDegraded(t *testing.T) {
+	get := func(router *gin.Engine, path string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+
+	t.Run("redis down", func(t *testing.T) {
+		database := &countingPinger{}
+		redis := &countingPinger{err: errors.New("connection refused")}
+		handler := NewHealthHandler("1.0.0",
+			WithReadinessCheck(database, time.Minute),
+			WithOptionalDependency("redis", redis, time.Minute),
+		)
+		router := setupTestRouter()
+		router.GET("/health", handler.Health)
+		router.GET("/ready", handler.Ready)
+
+		code, body := get(router, "/health")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "healthy", body["status"])
+		assert.Equal(t, true, body["degraded"])
+		assert.Equal(t, []interface{}{"redis"}, body["unavailable"])
+
+		code, body = get(router, "/ready")
+		assert.Equal(t, http.StatusOK, code, "optional dependencies should not fail readiness")
+		assert.Equal(t, "ready", body["status"])
+		assert.Equal(t, true, body["degraded"])
+	})
+
+	t.Run("all dependencies up", func(t *testing.T) {
+		handler := NewHealthHandler("1.0.0", WithOptionalDependency("redis", &countingPinger{}, time.Minute))
+		router := setupTestRouter()
+		router.GET("/health", handler.Health)
+
+		code, body := get(router, "/health")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, body["degraded"])
+		assert.NotContains(t, body, "unavailable")
+	})
+}
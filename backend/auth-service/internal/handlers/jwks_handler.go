@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/utils"
+)
+
+// jwksMaxAge is how long clients may cache the key set. Keys only change on
+// rotation, when the old key stays published until its tokens expire.
+const jwksMaxAge = "public, max-age=300"
+
+// JWKSHandler publishes the public keys that verify RS256 access tokens
+type JWKSHandler struct {
+	keys utils.JWKSet
+}
+
+// NewJWKSHandler creates a handler publishing the given keys: the current
+// signing key and any retired keys whose tokens may not have expired yet
+func NewJWKSHandler(keys []*utils.RSAKeyPair) *JWKSHandler {
+	return &JWKSHandler{keys: utils.PublicJWKS(keys)}
+}
+
+// JWKS returns the verification keys as a JSON Web Key Set. The set is empty
+// when tokens are signed with HS256, whose secret is never published.
+// GET /.well-known/jwks.json
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", jwksMaxAge)
+	c.JSON(http.StatusOK, h.keys)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJWKSHandler tests the JWKS endpoint publishes the verification keys
+// under the kid that issued tokens carry
+func TestJWKSHandler(t *testing.T) {
+	newKeys := func() *utils.RSAKeyPair {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		return &utils.RSAKeyPair{Private: privateKey, Public: &privateKey.PublicKey}
+	}
+
+	getJWKS := func(t *testing.T, handler *JWKSHandler) (*httptest.ResponseRecorder, map[string][]map[string]string) {
+		router := setupTestRouter()
+		router.GET("/.well-known/jwks.json", handler.JWKS)
+
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var body map[string][]map[string]string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec, body
+	}
+
+	t.Run("current and retired keys are published", func(t *testing.T) {
+		current, retired := newKeys(), newKeys()
+		key := utils.SigningKey{RSA: current, RetiredRSA: []*utils.RSAKeyPair{{Public: retired.Public}}}
+
+		rec, body := getJWKS(t, NewJWKSHandler(key.VerificationKeys()))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Cache-Control"), "max-age=")
+
+		require.Len(t, body["keys"], 2)
+		for _, jwk := range body["keys"] {
+			assert.Equal(t, "RSA", jwk["kty"])
+			assert.Equal(t, "sig", jwk["use"])
+			assert.Equal(t, "RS256", jwk["alg"])
+			assert.NotEmpty(t, jwk["kid"])
+			assert.NotContains(t, jwk, "d", "private exponent must never be published")
+		}
+		assert.Equal(t, current.KeyID(), body["keys"][0]["kid"])
+		assert.Equal(t, retired.KeyID(), body["keys"][1]["kid"])
+
+		// The published modulus and exponent are the current public key's
+		n, err := base64.RawURLEncoding.DecodeString(body["keys"][0]["n"])
+		require.NoError(t, err)
+		e, err := base64.RawURLEncoding.DecodeString(body["keys"][0]["e"])
+		require.NoError(t, err)
+		assert.Equal(t, 0, current.Public.N.Cmp(new(big.Int).SetBytes(n)))
+		assert.Equal(t, int64(current.Public.E), new(big.Int).SetBytes(e).Int64())
+	})
+
+	t.Run("kid matches issued tokens", func(t *testing.T) {
+		current := newKeys()
+		key := utils.SigningKey{RSA: current}
+
+		token, err := utils.GenerateAccessTokenWithKey(uuid.NewString(), "test@example.com", utils.AccessTokenOptions{}, 15*time.Minute, key)
+		require.NoError(t, err)
+		header, _, err := utils.DecodeTokenUnverified(token)
+		require.NoError(t, err)
+
+		_, body := getJWKS(t, NewJWKSHandler(key.VerificationKeys()))
+		require.Len(t, body["keys"], 1)
+		assert.Equal(t, header["kid"], body["keys"][0]["kid"])
+	})
+
+	t.Run("HS256 publishes no keys", func(t *testing.T) {
+		rec, body := getJWKS(t, NewJWKSHandler(utils.HMACKey("test-secret").VerificationKeys()))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"keys":[]}`, rec.Body.String())
+		assert.Empty(t, body["keys"])
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// readinessCheckTimeout bounds a single dependency check
+const readinessCheckTimeout = 2 * time.Second
+
+// Pinger checks connectivity to a dependency (e.g. *pgxpool.Pool)
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// readinessCache caches the result of a readiness check for a short TTL so
+// bursts of probes reuse a recent check. Only one check runs at a time:
+// while a refresh is in flight, callers with a previous result get it
+// immediately and only the very first check blocks.
+type readinessCache struct {
+	pinger Pinger
+	ttl    time.Duration
+
+	mu         sync.Mutex
+	checked    bool
+	checkedAt  time.Time
+	err        error
+	refreshing chan struct{}
+}
+
+// newReadinessCache creates a readiness cache around the pinger
+func newReadinessCache(pinger Pinger, ttl time.Duration) *readinessCache {
+	return &readinessCache{
+		pinger: pinger,
+		ttl:    ttl,
+	}
+}
+
+// Check returns the cached readiness result, refreshing it on expiry
+func (r *readinessCache) Check(ctx context.Context) error {
+	r.mu.Lock()
+
+	// Fresh result
+	if r.checked && time.Since(r.checkedAt) < r.ttl {
+		err := r.err
+		r.mu.Unlock()
+		return err
+	}
+
+	// A refresh is already running: serve the stale result if there is one,
+	// otherwise wait for the first check to finish
+	if r.refreshing != nil {
+		done := r.refreshing
+		if r.checked {
+			err := r.err
+			r.mu.Unlock()
+			return err
+		}
+		r.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.err
+	}
+
+	// This caller runs the refresh
+	done := make(chan struct{})
+	r.refreshing = done
+	r.mu.Unlock()
+
+	checkCtx, cancel := context.WithTimeout(context.Background(), readinessCheckTimeout)
+	err := r.pinger.Ping(checkCtx)
+	cancel()
+
+	r.mu.Lock()
+	r.checked = true
+	r.checkedAt = time.Now()
+	r.err = err
+	r.refreshing = nil
+	r.mu.Unlock()
+	close(done)
+
+	return err
+}
@@ -0,0 +1,172 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// Outbound HTTP request counter
+	outboundRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbound_http_requests_total",
+			Help: "Total number of outbound HTTP requests to dependencies",
+		},
+		[]string{"host", "status"},
+	)
+
+	// Outbound HTTP request duration histogram
+	outboundRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "outbound_http_request_duration_seconds",
+			Help:    "Outbound HTTP request duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"host"},
+	)
+)
+
+// statusError labels requests that failed without a response (DNS, connect, timeout)
+const statusError = "error"
+
+// Config holds outbound HTTP client configuration
+type Config struct {
+	// Name identifies the integration in logs (e.g. "hibp", "kyc-provider")
+	Name string
+
+	// Timeout bounds each request including retries
+	Timeout time.Duration
+
+	// MaxRetries is how many times a failed idempotent request is retried
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubled for each further retry
+	RetryBackoff time.Duration
+}
+
+// DefaultConfig returns default outbound HTTP client configuration
+func DefaultConfig(name string) Config {
+	return Config{
+		Name:         name,
+		Timeout:      10 * time.Second,
+		MaxRetries:   2,
+		RetryBackoff: 200 * time.Millisecond,
+	}
+}
+
+// New creates an http.Client for calls to external dependencies. Every
+// attempt is logged with its duration, status and target host, and counted
+// in outbound_http_requests_total. Idempotent requests are retried on
+// transport errors and 5xx responses.
+func New(logger *logrus.Logger, config Config) *http.Client {
+	return &http.Client{
+		Timeout: config.Timeout,
+		Transport: &instrumentedTransport{
+			base:   http.DefaultTransport,
+			logger: logger,
+			config: config,
+		},
+	}
+}
+
+// instrumentedTransport wraps a RoundTripper with logging, metrics and retries
+type instrumentedTransport struct {
+	base   http.RoundTripper
+	logger *logrus.Logger
+	config Config
+}
+
+// RoundTrip executes a request, retrying idempotent requests that fail
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTripOnce(req, attempt)
+
+		if attempt >= t.config.MaxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		// Discard the failed response before retrying
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, bodyErr
+			}
+			req.Body = body
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// roundTripOnce executes a single attempt and records it
+func (t *instrumentedTransport) roundTripOnce(req *http.Request, attempt int) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	host := req.URL.Host
+	status := statusError
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	outboundRequestsTotal.WithLabelValues(host, status).Inc()
+	outboundRequestDuration.WithLabelValues(host).Observe(duration.Seconds())
+
+	fields := logrus.Fields{
+		"integration": t.config.Name,
+		"method":      req.Method,
+		"host":        host,
+		"path":        req.URL.Path,
+		"status":      status,
+		"duration_ms": duration.Milliseconds(),
+		"attempt":     attempt + 1,
+	}
+
+	switch {
+	case err != nil:
+		t.logger.WithFields(fields).WithError(err).Warn("Outbound request failed")
+	case resp.StatusCode >= 500:
+		t.logger.WithFields(fields).Warn("Outbound request returned server error")
+	default:
+		t.logger.WithFields(fields).Info("Outbound request")
+	}
+
+	return resp, err
+}
+
+// shouldRetry reports whether a failed attempt can safely be retried.
+// Only idempotent methods are retried, and only if the body can be replayed.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	return err != nil || resp.StatusCode >= 500
+}
@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates an instrumented client with fast retries and a captured logger
+func newTestClient(maxRetries int) (*http.Client, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	config := DefaultConfig("test-integration")
+	config.MaxRetries = maxRetries
+	config.RetryBackoff = time.Millisecond
+	return New(logger, config), hook
+}
+
+// TestInstrumentedClient tests that outbound calls are logged and counted
+func TestInstrumentedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host := mustHost(t, server.URL)
+	before := testutil.ToFloat64(outboundRequestsTotal.WithLabelValues(host, "200"))
+
+	client, hook := newTestClient(2)
+	resp, err := client.Get(server.URL + "/v1/check")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, before+1, testutil.ToFloat64(outboundRequestsTotal.WithLabelValues(host, "200")))
+
+	require.Len(t, hook.AllEntries(), 1)
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "test-integration", entry.Data["integration"])
+	assert.Equal(t, host, entry.Data["host"])
+	assert.Equal(t, "/v1/check", entry.Data["path"])
+	assert.Equal(t, "200", entry.Data["status"])
+	assert.Equal(t, 1, entry.Data["attempt"])
+	assert.Contains(t, entry.Data, "duration_ms")
+}
+
+// TestInstrumentedClientRetries tests retrying idempotent requests after server errors
+func TestInstrumentedClientRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host := mustHost(t, server.URL)
+	before503 := testutil.ToFloat64(outboundRequestsTotal.WithLabelValues(host, "503"))
+
+	client, hook := newTestClient(2)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, before503+2, testutil.ToFloat64(outboundRequestsTotal.WithLabelValues(host, "503")))
+	require.Len(t, hook.AllEntries(), 3)
+	assert.Equal(t, 3, hook.LastEntry().Data["attempt"])
+}
+
+// TestInstrumentedClientDoesNotRetryPost tests that non-idempotent requests are sent once
+func TestInstrumentedClientDoesNotRetryPost(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(2)
+	resp, err := client.Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+// TestInstrumentedClientTransportError tests that failures without a response are counted as errors
+func TestInstrumentedClientTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	server.Close()
+
+	host := mustHost(t, serverURL)
+	before := testutil.ToFloat64(outboundRequestsTotal.WithLabelValues(host, statusError))
+
+	client, hook := newTestClient(1)
+	_, err := client.Get(serverURL)
+	require.Error(t, err)
+
+	assert.Equal(t, before+2, testutil.ToFloat64(outboundRequestsTotal.WithLabelValues(host, statusError)))
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+}
+
+// mustHost returns the host:port of a URL
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return parsed.Host
+}
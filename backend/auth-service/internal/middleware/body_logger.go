@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// redactedMask replaces sensitive values in logged bodies
+const redactedMask = "[REDACTED]"
+
+// BodyLoggerConfig holds request/response body logging configuration
+type BodyLoggerConfig struct {
+	Paths        []string // Request paths whose bodies are logged
+	MaxBodyBytes int      // Logged bodies are truncated beyond this size
+	RedactFields []string // JSON fields whose values are masked (case-insensitive)
+
+	// LogHeaders also logs request headers. Credentials are always masked by
+	// Headers, which defaults to NewHeaderRedactor().
+	LogHeaders bool
+	Headers    *HeaderRedactor
+}
+
+// DefaultBodyLoggerConfig returns default body logging configuration
+func DefaultBodyLoggerConfig() *BodyLoggerConfig {
+	return &BodyLoggerConfig{
+		Paths:        []string{},
+		MaxBodyBytes: 4096,
+		RedactFields: []string{
+			"password",
+			"current_password",
+			"new_password",
+			"access_token",
+			"refresh_token",
+			"token",
+			"secret",
+		},
+		Headers: NewHeaderRedactor(),
+	}
+}
+
+// bodyCaptureWriter tees the response body into a buffer
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write writes the data to the connection and the capture buffer
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString writes the string to the connection and the capture buffer
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// BodyLogger returns a debugging middleware that logs request and response bodies
+// for the configured paths. Sensitive JSON fields are redacted and bodies are
+// size-capped. The request body is restored so handlers can still read it.
+func BodyLogger(logger *logrus.Logger, config *BodyLoggerConfig) gin.HandlerFunc {
+	redact := make(map[string]bool, len(config.RedactFields))
+	for _, field := range config.RedactFields {
+		redact[strings.ToLower(field)] = true
+	}
+
+	headers := config.Headers
+	if headers == nil {
+		headers = NewHeaderRedactor()
+	}
+
+	return func(c *gin.Context) {
+		if !contains(config.Paths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		// Read and restore the request body
+		var requestBody []byte
+		if c.Request.Body != nil {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to read request body for logging")
+			}
+			requestBody = body
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		// Capture the response body
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		fields := logrus.Fields{
+			"method":        c.Request.Method,
+			"path":          c.Request.URL.Path,
+			"status":        c.Writer.Status(),
+			"request_body":  sanitizeBody(requestBody, redact, config.MaxBodyBytes),
+			"response_body": sanitizeBody(writer.body.Bytes(), redact, config.MaxBodyBytes),
+		}
+		if config.LogHeaders {
+			fields["request_headers"] = headers.Redact(c.Request.Header)
+		}
+
+		logger.WithFields(fields).Info("Request/response body")
+	}
+}
+
+// sanitizeBody redacts sensitive JSON fields and truncates the result.
+// Non-JSON bodies are not logged since they can't be reliably redacted.
+func sanitizeBody(body []byte, redact map[string]bool, maxBytes int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "[non-JSON body omitted]"
+	}
+
+	sanitized, err := json.Marshal(redactValue(parsed, redact))
+	if err != nil {
+		return "[unloggable body omitted]"
+	}
+
+	if maxBytes > 0 && len(sanitized) > maxBytes {
+		return string(sanitized[:maxBytes]) + "...(truncated)"
+	}
+
+	return string(sanitized)
+}
+
+// redactValue recursively masks values of sensitive keys in decoded JSON
+func redactValue(value interface{}, redact map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redact[strings.ToLower(key)] {
+				v[key] = redactedMask
+			} else {
+				v[key] = redactValue(field, redact)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, redact)
+		}
+		return v
+	default:
+		return v
+	}
+}
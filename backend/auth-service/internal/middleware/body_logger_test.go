@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBodyLoggerMiddleware tests debug body logging with redaction
+func TestBodyLoggerMiddleware(t *testing.T) {
+	newRouter := func(config *BodyLoggerConfig) (*gin.Engine, *test.Hook, *string) {
+		logger, hook := test.NewNullLogger()
+		var received string
+
+		router := setupTestRouter()
+		router.Use(BodyLogger(logger, config))
+		router.POST("/api/v1/auth/login", func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			received = string(body)
+			c.JSON(http.StatusOK, gin.H{
+				"access_token": "eyJhbGciOiJIUzI1NiJ9.secret-token",
+				"token_type":   "Bearer",
+			})
+		})
+		router.POST("/api/v1/auth/register", func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+
+		return router, hook, &received
+	}
+
+	config := DefaultBodyLoggerConfig()
+	config.Paths = []string{"/api/v1/auth/login"}
+
+	t.Run("password is redacted and handler still receives body", func(t *testing.T) {
+		router, hook, received := newRouter(config)
+		requestBody := `{"email":"john@example.com","password":"SecurePass123!"}`
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, requestBody, *received)
+
+		require.Len(t, hook.Entries, 1)
+		entry := hook.LastEntry()
+
+		loggedRequest := entry.Data["request_body"].(string)
+		assert.Contains(t, loggedRequest, "john@example.com")
+		assert.Contains(t, loggedRequest, redactedMask)
+		assert.NotContains(t, loggedRequest, "SecurePass123!")
+
+		loggedResponse := entry.Data["response_body"].(string)
+		assert.Contains(t, loggedResponse, "Bearer")
+		assert.NotContains(t, loggedResponse, "secret-token")
+
+		// Client still gets the real response
+		assert.Contains(t, rec.Body.String(), "secret-token")
+	})
+
+	t.Run("unconfigured path is not logged", func(t *testing.T) {
+		router, hook, _ := newRouter(config)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewBufferString(`{"password":"x"}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, hook.Entries)
+	})
+
+	t.Run("nested fields are redacted", func(t *testing.T) {
+		router, hook, _ := newRouter(config)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login",
+			bytes.NewBufferString(`{"credentials":[{"Password":"hunter2"}]}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Len(t, hook.Entries, 1)
+		assert.NotContains(t, hook.LastEntry().Data["request_body"], "hunter2")
+	})
+
+	t.Run("non-JSON body is omitted", func(t *testing.T) {
+		router, hook, received := newRouter(config)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString("password=hunter2"))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, "password=hunter2", *received)
+		require.Len(t, hook.Entries, 1)
+		assert.NotContains(t, hook.LastEntry().Data["request_body"], "hunter2")
+	})
+
+	t.Run("large body is truncated", func(t *testing.T) {
+		smallConfig := DefaultBodyLoggerConfig()
+		smallConfig.Paths = []string{"/api/v1/auth/login"}
+		smallConfig.MaxBodyBytes = 32
+		router, hook, received := newRouter(smallConfig)
+
+		requestBody := `{"email":"` + strings.Repeat("a", 100) + `@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(requestBody))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, requestBody, *received)
+		require.Len(t, hook.Entries, 1)
+		logged := hook.LastEntry().Data["request_body"].(string)
+		assert.True(t, strings.HasSuffix(logged, "...(truncated)"))
+		assert.LessOrEqual(t, len(logged), 32+len("...(truncated)"))
+	})
+}
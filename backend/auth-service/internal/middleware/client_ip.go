@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WithTrustedProxies makes the limiter honor X-Forwarded-For and X-Real-IP
+// from the given proxies when finding the client IP (see ClientIP)
+func WithTrustedProxies(trustedProxies *IPAllowlist) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.trustedProxies = trustedProxies
+	}
+}
+
+// getClientIP extracts the client IP from the request (see ClientIP)
+func getClientIP(c *gin.Context, trustedProxies *IPAllowlist) string {
+	return ClientIP(c.Request, trustedProxies)
+}
+
+// ClientIP returns the IP of the client that sent r. Forwarding headers are
+// only honored when the socket peer is a trusted proxy, since anyone else can
+// set them to any value. X-Forwarded-For is read right to left, skipping
+// trusted hops: each proxy appends the address it received the request from,
+// so the first untrusted address is the furthest one that can't be forged.
+// With no trusted proxies the socket peer address is always used.
+func ClientIP(r *http.Request, trustedProxies *IPAllowlist) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !trustedProxies.Contains(peer) {
+		return peer
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				// Garbage can only come from the client; stop at the last good hop
+				break
+			}
+			client = hop
+			if !trustedProxies.Contains(hop) {
+				break
+			}
+		}
+		return client
+	}
+
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(xri) != nil {
+		return xri
+	}
+
+	return peer
+}
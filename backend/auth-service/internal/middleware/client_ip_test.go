@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientIP tests forwarding headers are only honored from trusted proxies
+// and that X-Forwarded-For is read right to left past trusted hops
+func TestClientIP(t *testing.T) {
+	trustedProxies, err := NewIPAllowlist([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		xRealIP    string
+		want       string
+	}{
+		{"no headers", "198.51.100.7:1234", nil, "", "198.51.100.7"},
+		{"spoofed header from untrusted client", "198.51.100.7:1234", []string{"1.2.3.4"}, "", "198.51.100.7"},
+		{"spoofed X-Real-IP from untrusted client", "198.51.100.7:1234", nil, "1.2.3.4", "198.51.100.7"},
+		{"header from trusted proxy", "10.0.0.2:1234", []string{"203.0.113.9"}, "", "203.0.113.9"},
+		{"client-supplied hop is ignored", "10.0.0.2:1234", []string{"1.2.3.4, 203.0.113.9"}, "", "203.0.113.9"},
+		{"trusted hops are skipped", "10.0.0.2:1234", []string{"1.2.3.4, 203.0.113.9, 10.0.0.5"}, "", "203.0.113.9"},
+		{"repeated headers are joined", "10.0.0.2:1234", []string{"1.2.3.4", "203.0.113.9, 10.0.0.5"}, "", "203.0.113.9"},
+		{"all hops trusted", "10.0.0.2:1234", []string{"10.0.0.9, 10.0.0.5"}, "", "10.0.0.9"},
+		{"garbage hop stops the walk", "10.0.0.2:1234", []string{"203.0.113.9, not-an-ip, 10.0.0.5"}, "", "10.0.0.5"},
+		{"X-Real-IP from trusted proxy", "10.0.0.2:1234", nil, "203.0.113.9", "203.0.113.9"},
+		{"invalid X-Real-IP", "10.0.0.2:1234", nil, "not-an-ip", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.xff {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tt.xRealIP)
+			}
+
+			assert.Equal(t, tt.want, ClientIP(req, trustedProxies))
+		})
+	}
+
+	t.Run("no trusted proxies", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.2:1234"
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+
+		assert.Equal(t, "10.0.0.2", ClientIP(req, nil))
+	})
+}
+
+// TestRateLimitSpoofedXForwardedFor tests that rotating forged X-Forwarded-For
+// values doesn't get a client a fresh quota
+func TestRateLimitSpoofedXForwardedFor(t *testing.T) {
+	trustedProxies, err := NewIPAllowlist([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	router := setupTestRouter()
+	limiter := NewRateLimiter(2, time.Minute, WithTrustedProxies(trustedProxies))
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(remoteAddr, xff string) int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", xff)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("198.51.100.7:1234", "1.1.1.1"))
+	assert.Equal(t, http.StatusOK, send("198.51.100.7:1234", "2.2.2.2"))
+	assert.Equal(t, http.StatusTooManyRequests, send("198.51.100.7:1234", "3.3.3.3"))
+
+	// Clients behind the trusted proxy are limited separately
+	assert.Equal(t, http.StatusOK, send("10.0.0.2:1234", "203.0.113.9"))
+	assert.Equal(t, http.StatusOK, send("10.0.0.2:1234", "203.0.113.10"))
+}
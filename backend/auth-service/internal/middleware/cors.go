@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,12 +31,18 @@ func DefaultCORSConfig() *CORSConfig {
 			"Origin",
 			"Cache-Control",
 			"X-Requested-With",
+			PartnerIDHeader,
+			FeatureOverridesHeader,
+			FeatureOverridesSignatureHeader,
 		},
 		ExposeHeaders: []string{
 			"Content-Length",
+			"Retry-After",
 			"X-RateLimit-Limit",
 			"X-RateLimit-Remaining",
 			"X-RateLimit-Reset",
+			DegradedHeader,
+			SignatureHeader,
 		},
 		AllowCredentials: true,
 		MaxAge:           43200, // 12 hours
@@ -50,59 +59,70 @@ func ProductionCORSConfig(allowedOrigins []string) *CORSConfig {
 // CORS returns a CORS middleware with the given configuration
 func CORS(config *CORSConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-
-		// Check if origin is allowed
-		if config.AllowOrigins[0] == "*" || contains(config.AllowOrigins, origin) {
-			// Set allowed origin
-			if config.AllowOrigins[0] == "*" {
-				c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-			} else {
-				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-			}
+		applyCORS(c, config)
 
-			// Set other CORS headers
-			if config.AllowCredentials {
-				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-			}
+		// Handle preflight requests
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
 
-			// Set allowed methods
-			methods := ""
-			for i, method := range config.AllowMethods {
-				if i > 0 {
-					methods += ", "
-				}
-				methods += method
-			}
-			c.Writer.Header().Set("Access-Control-Allow-Methods", methods)
+		c.Next()
+	}
+}
 
-			// Set allowed headers
-			headers := ""
-			for i, header := range config.AllowHeaders {
-				if i > 0 {
-					headers += ", "
-				}
-				headers += header
-			}
-			c.Writer.Header().Set("Access-Control-Allow-Headers", headers)
-
-			// Set exposed headers
-			if len(config.ExposeHeaders) > 0 {
-				exposeHeaders := ""
-				for i, header := range config.ExposeHeaders {
-					if i > 0 {
-						exposeHeaders += ", "
-					}
-					exposeHeaders += header
-				}
-				c.Writer.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
-			}
+// MergeCORSConfig derives a route group's configuration from the base one.
+// The group's AllowMethods replace the base methods when set, so a group can
+// advertise fewer or more methods; its allowed and exposed headers are added
+// to the base headers. Origins, credentials and max age come from the base.
+func MergeCORSConfig(base, group *CORSConfig) *CORSConfig {
+	merged := *base
 
-			// Set max age
-			if config.MaxAge > 0 {
-				c.Writer.Header().Set("Access-Control-Max-Age", string(rune(config.MaxAge)))
-			}
+	if len(group.AllowMethods) > 0 {
+		merged.AllowMethods = append([]string(nil), group.AllowMethods...)
+	}
+	merged.AllowHeaders = mergeHeaders(base.AllowHeaders, group.AllowHeaders)
+	merged.ExposeHeaders = mergeHeaders(base.ExposeHeaders, group.ExposeHeaders)
+
+	return &merged
+}
+
+// GroupCORS applies a distinct CORS configuration per route group. Group
+// middleware never sees preflights for paths without an OPTIONS route, so
+// the configuration is chosen here by the longest matching group prefix and
+// the middleware is installed on the router.
+type GroupCORS struct {
+	defaultConfig *CORSConfig
+	groups        []corsGroup
+}
+
+// corsGroup is a route group prefix and its CORS configuration
+type corsGroup struct {
+	prefix string
+	config *CORSConfig
+}
+
+// NewGroupCORS creates per-group CORS with a configuration for paths outside any group
+func NewGroupCORS(defaultConfig *CORSConfig) *GroupCORS {
+	return &GroupCORS{defaultConfig: defaultConfig}
+}
+
+// Group sets the CORS configuration for paths under the group's prefix
+func (g *GroupCORS) Group(prefix string, config *CORSConfig) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	for i := range g.groups {
+		if g.groups[i].prefix == prefix {
+			g.groups[i].config = config
+			return
 		}
+	}
+	g.groups = append(g.groups, corsGroup{prefix: prefix, config: config})
+}
+
+// Handler returns the CORS middleware
+func (g *GroupCORS) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		applyCORS(c, g.configFor(c.Request.URL.Path))
 
 		// Handle preflight requests
 		if c.Request.Method == "OPTIONS" {
@@ -114,6 +134,97 @@ func CORS(config *CORSConfig) gin.HandlerFunc {
 	}
 }
 
+// configFor returns the configuration of the most specific group containing the path
+func (g *GroupCORS) configFor(path string) *CORSConfig {
+	config := g.defaultConfig
+	longest := -1
+	for _, group := range g.groups {
+		if path != group.prefix && !strings.HasPrefix(path, group.prefix+"/") {
+			continue
+		}
+		if len(group.prefix) > longest {
+			config = group.config
+			longest = len(group.prefix)
+		}
+	}
+	return config
+}
+
+// applyCORS sets the CORS response headers for an allowed origin
+func applyCORS(c *gin.Context, config *CORSConfig) {
+	origin := c.Request.Header.Get("Origin")
+
+	// Check if origin is allowed
+	if config.AllowOrigins[0] == "*" || contains(config.AllowOrigins, origin) {
+		// Set allowed origin
+		if config.AllowOrigins[0] == "*" {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+
+		// Set other CORS headers
+		if config.AllowCredentials {
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Set allowed methods
+		methods := ""
+		for i, method := range config.AllowMethods {
+			if i > 0 {
+				methods += ", "
+			}
+			methods += method
+		}
+		c.Writer.Header().Set("Access-Control-Allow-Methods", methods)
+
+		// Set allowed headers
+		headers := ""
+		for i, header := range config.AllowHeaders {
+			if i > 0 {
+				headers += ", "
+			}
+			headers += header
+		}
+		c.Writer.Header().Set("Access-Control-Allow-Headers", headers)
+
+		// Set exposed headers
+		if len(config.ExposeHeaders) > 0 {
+			exposeHeaders := ""
+			for i, header := range config.ExposeHeaders {
+				if i > 0 {
+					exposeHeaders += ", "
+				}
+				exposeHeaders += header
+			}
+			c.Writer.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
+
+		// Set max age
+		if config.MaxAge > 0 {
+			c.Writer.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		}
+	}
+}
+
+// mergeHeaders returns base followed by any extra headers it doesn't already contain
+func mergeHeaders(base, extra []string) []string {
+	merged := append([]string(nil), base...)
+	for _, header := range extra {
+		found := false
+		for _, existing := range merged {
+			if strings.EqualFold(existing, header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, header)
+		}
+	}
+	return merged
+}
+
 // contains checks if a string slice contains a value
 func contains(slice []string, value string) bool {
 	for _, item := range slice {
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGroupCORSRouter creates a router with distinct CORS configurations for the auth and admin groups
+func newGroupCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	base := DefaultCORSConfig()
+	cors := NewGroupCORS(base)
+	cors.Group("/api/v1/auth", MergeCORSConfig(base, &CORSConfig{
+		AllowMethods: []string{"GET", "POST", "OPTIONS"},
+	}))
+	cors.Group("/api/v1/admin/", MergeCORSConfig(base, &CORSConfig{
+		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"X-Admin-Reason", "authorization"},
+	}))
+
+	router := gin.New()
+	router.Use(cors.Handler())
+	router.POST("/api/v1/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.DELETE("/api/v1/admin/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+// TestGroupCORSPreflight tests that preflights reflect the matching group's configuration
+func TestGroupCORSPreflight(t *testing.T) {
+	router := newGroupCORSRouter()
+
+	tests := []struct {
+		name        string
+		path        string
+		wantMethods string
+	}{
+		{
+			name:        "auth group",
+			path:        "/api/v1/auth/login",
+			wantMethods: "GET, POST, OPTIONS",
+		},
+		{
+			name:        "admin group",
+			path:        "/api/v1/admin/users/123",
+			wantMethods: "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		},
+		{
+			name:        "path outside any group uses the default",
+			path:        "/health",
+			wantMethods: "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		},
+		{
+			name:        "prefix only matches whole path segments",
+			path:        "/api/v1/authz",
+			wantMethods: "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, tt.wantMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
+
+// TestCORSPreflightMaxAge tests the preflight cache lifetime is sent as a decimal number of seconds
+func TestCORSPreflightMaxAge(t *testing.T) {
+	router := newGroupCORSRouter()
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/auth/login", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, strconv.Itoa(DefaultCORSConfig().MaxAge), rec.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "43200", rec.Header().Get("Access-Control-Max-Age"))
+}
+
+// TestGroupCORSActualRequest tests that non-preflight requests get their group's headers and reach the handler
+func TestGroupCORSActualRequest(t *testing.T) {
+	router := newGroupCORSRouter()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/users/123", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), "DELETE")
+}
+
+// TestMergeCORSConfig tests deriving a group configuration from the base
+func TestMergeCORSConfig(t *testing.T) {
+	base := ProductionCORSConfig([]string{"https://app.example.com"})
+
+	merged := MergeCORSConfig(base, &CORSConfig{
+		AllowMethods: []string{"GET"},
+		AllowHeaders: []string{"X-Admin-Reason", "AUTHORIZATION"},
+	})
+
+	assert.Equal(t, []string{"GET"}, merged.AllowMethods)
+	assert.Equal(t, append(append([]string(nil), base.AllowHeaders...), "X-Admin-Reason"), merged.AllowHeaders)
+	assert.Equal(t, base.ExposeHeaders, merged.ExposeHeaders)
+	assert.Equal(t, base.AllowOrigins, merged.AllowOrigins)
+	assert.Equal(t, base.AllowCredentials, merged.AllowCredentials)
+
+	// The base configuration is left untouched
+	assert.Len(t, base.AllowMethods, 6)
+	assert.NotContains(t, base.AllowHeaders, "X-Admin-Reason")
+
+	// Without group methods the base methods are kept
+	assert.Equal(t, base.AllowMethods, MergeCORSConfig(base, &CORSConfig{}).AllowMethods)
+}
+
+// TestDefaultCORSConfigHeaders tests that browsers can send and read the
+// service's own headers under the default configuration
+func TestDefaultCORSConfigHeaders(t *testing.T) {
+	config := DefaultCORSConfig()
+
+	assert.Subset(t, config.AllowHeaders, []string{"X-Partner-ID", "X-Feature-Overrides", "X-Feature-Overrides-Signature"})
+	assert.Subset(t, config.ExposeHeaders, []string{"Retry-After", "X-Service-Degraded", "X-Signature"})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DegradedHeader is set on responses while optional subsystems are down,
+// listing them comma-separated (e.g. "redis")
+const DegradedHeader = "X-Service-Degraded"
+
+// DegradationReporter reports which optional subsystems are unavailable
+// (see handlers.HealthHandler)
+type DegradationReporter interface {
+	Unavailable(ctx context.Context) []string
+}
+
+// Degraded sets the X-Service-Degraded header when any optional subsystem
+// is unavailable, so clients know features such as shared rate limits may
+// be reduced even though the request itself succeeds
+func Degraded(reporter DegradationReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if unavailable := reporter.Unavailable(c.Request.Context()); len(unavailable) > 0 {
+			c.Header(DegradedHeader, strings.Join(unavailable, ","))
+		}
+		c.Next()
+	}
+}
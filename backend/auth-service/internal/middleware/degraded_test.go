@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubReporter reports a fixed list of unavailable subsystems
+type stubReporter []string
+
+func (r stubReporter) Unavailable(ctx context.Context) []string {
+	return r
+}
+
+// TestDegradedMiddleware tests the X-Service-Degraded header
+func TestDegradedMiddleware(t *testing.T) {
+	serve := func(reporter DegradationReporter) *httptest.ResponseRecorder {
+		router := setupTestRouter()
+		router.Use(Degraded(reporter))
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+		return rec
+	}
+
+	t.Run("redis down sets the header and the request still succeeds", func(t *testing.T) {
+		rec := serve(stubReporter{"redis"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "redis", rec.Header().Get(DegradedHeader))
+	})
+
+	t.Run("several subsystems are comma-separated", func(t *testing.T) {
+		rec := serve(stubReporter{"redis", "geoip"})
+		assert.Equal(t, "redis,geoip", rec.Header().Get(DegradedHeader))
+	})
+
+	t.Run("no header when everything is up", func(t *testing.T) {
+		rec := serve(stubReporter{})
+		assert.Empty(t, rec.Header().Values(DegradedHeader))
+	})
+}
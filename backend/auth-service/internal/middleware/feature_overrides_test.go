@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/features"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeatureOverrides tests signed per-request flag overrides outside production
+func TestFeatureOverrides(t *testing.T) {
+	const secret = "feature-override-secret-at-least-32-chars"
+
+	newRouter := func(environment string) *gin.Engine {
+		router := setupTestRouter()
+		router.Use(FeatureOverrides(environment, secret))
+		router.GET("/test", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{
+				"refresh_tokens": features.Enabled(c.Request.Context(), features.RefreshTokens, true),
+			})
+		})
+		return router
+	}
+
+	request := func(value, signature string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(FeatureOverridesHeader, value)
+		req.Header.Set(FeatureOverridesSignatureHeader, signature)
+		return req
+	}
+
+	value := "refresh_tokens=false"
+	signature := "sha256=" + SignFeatureOverrides(value, secret)
+
+	t.Run("signed override applies in staging", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newRouter("staging").ServeHTTP(rec, request(value, signature))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"refresh_tokens": false}`, rec.Body.String())
+	})
+
+	t.Run("requests without overrides use configuration", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newRouter("staging").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"refresh_tokens": true}`, rec.Body.String())
+	})
+
+	t.Run("overrides are refused in production", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newRouter("production").ServeHTTP(rec, request(value, signature))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("bad signature is refused", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newRouter("staging").ServeHTTP(rec, request("refresh_tokens=true", signature))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("unknown flag is rejected", func(t *testing.T) {
+		value := "debug_endpoints=true"
+		rec := httptest.NewRecorder()
+		newRouter("staging").ServeHTTP(rec, request(value, "sha256="+SignFeatureOverrides(value, secret)))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// DefaultRedactedHeaders are always masked wherever request headers are
+// logged, since they carry credentials
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// HeaderRedactor masks sensitive header values before headers are logged
+type HeaderRedactor struct {
+	redact map[string]bool
+}
+
+// NewHeaderRedactor creates a redactor for DefaultRedactedHeaders plus any
+// extra header names. Names are case-insensitive; extras can add to the
+// defaults but not remove them.
+func NewHeaderRedactor(extra ...string) *HeaderRedactor {
+	redact := make(map[string]bool, len(DefaultRedactedHeaders)+len(extra))
+	for _, name := range append(append([]string{}, DefaultRedactedHeaders...), extra...) {
+		if name = strings.TrimSpace(name); name != "" {
+			redact[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+	return &HeaderRedactor{redact: redact}
+}
+
+// Redact returns the headers as a loggable map with sensitive values
+// replaced by a fixed mask. Repeated headers are joined with ", ".
+func (r *HeaderRedactor) Redact(header http.Header) map[string]string {
+	logged := make(map[string]string, len(header))
+	for name, values := range header {
+		name = http.CanonicalHeaderKey(name)
+		if r.redact[name] {
+			logged[name] = redactedMask
+			continue
+		}
+		logged[name] = strings.Join(values, ", ")
+	}
+	return logged
+}
+
+// Names returns the redacted header names, sorted
+func (r *HeaderRedactor) Names() []string {
+	names := make([]string, 0, len(r.redact))
+	for name := range r.redact {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
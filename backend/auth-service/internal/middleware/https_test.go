@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequireHTTPS tests HTTPS enforcement behind a TLS-terminating proxy
+func TestRequireHTTPS(t *testing.T) {
+	proxies, err := NewIPAllowlist([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	newRouter := func(mode HTTPSMode) *gin.Engine {
+		config := DefaultHTTPSConfig()
+		config.Mode = mode
+		config.TrustedProxies = proxies
+
+		router := setupTestRouter()
+		router.Use(RequireHTTPS(config))
+		router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.POST("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+
+	serve := func(router *gin.Engine, method, path, remoteAddr, proto string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "http://auth.example.com"+path, nil)
+		req.RemoteAddr = remoteAddr
+		if proto != "" {
+			req.Header.Set("X-Forwarded-Proto", proto)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("X-Forwarded-Proto http is rejected", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSReject), http.MethodGet, "/test", "10.0.0.5:443", "http")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "HTTPS_REQUIRED", body["code"])
+	})
+
+	t.Run("X-Forwarded-Proto https from a trusted proxy is allowed", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSReject), http.MethodGet, "/test", "10.0.0.5:443", "https")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("first scheme in a proxy chain wins", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSReject), http.MethodGet, "/test", "10.0.0.5:443", "http, https")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("X-Forwarded-Proto from an untrusted client is ignored", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSReject), http.MethodGet, "/test", "203.0.113.7:5555", "https")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("direct TLS is allowed", func(t *testing.T) {
+		router := newRouter(HTTPSReject)
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "203.0.113.7:5555"
+		req.TLS = &tls.ConnectionState{}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("GET is redirected in redirect mode", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSRedirect), http.MethodGet, "/test?x=1", "10.0.0.5:443", "http")
+		assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+		assert.Equal(t, "https://auth.example.com/test?x=1", rec.Header().Get("Location"))
+	})
+
+	t.Run("POST is rejected even in redirect mode", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSRedirect), http.MethodPost, "/test", "10.0.0.5:443", "http")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("exempt paths are served over plaintext", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSReject), http.MethodGet, "/health", "10.1.2.3:8080", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("off allows plaintext", func(t *testing.T) {
+		rec := serve(newRouter(HTTPSOff), http.MethodGet, "/test", "10.0.0.5:443", "http")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+// TestParseHTTPSMode tests parsing the enforcement mode from configuration
+func TestParseHTTPSMode(t *testing.T) {
+	for value, expected := range map[string]HTTPSMode{"": HTTPSOff, "off": HTTPSOff, "Reject": HTTPSReject, " redirect ": HTTPSRedirect} {
+		mode, err := ParseHTTPSMode(value)
+		require.NoError(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	_, err := ParseHTTPSMode("always")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// IPAllowlist matches client IPs against a set of addresses and CIDR ranges
+type IPAllowlist struct {
+	networks []*net.IPNet
+}
+
+// NewIPAllowlist parses IP addresses (e.g. "203.0.113.7") and CIDR ranges
+// (e.g. "10.0.0.0/8") into an allowlist. Empty entries are ignored.
+func NewIPAllowlist(entries []string) (*IPAllowlist, error) {
+	allowlist := &IPAllowlist{}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// Treat bare addresses as single-host ranges
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid allowlist IP: %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			allowlist.networks = append(allowlist.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist CIDR: %q", entry)
+		}
+		allowlist.networks = append(allowlist.networks, network)
+	}
+
+	return allowlist, nil
+}
+
+// Contains reports whether the IP is covered by the allowlist.
+// A nil allowlist contains nothing.
+func (a *IPAllowlist) Contains(ip string) bool {
+	if a == nil {
+		return false
+	}
+
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range a.networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of entries in the allowlist
+func (a *IPAllowlist) Len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.networks)
+}
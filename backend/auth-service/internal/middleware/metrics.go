@@ -9,6 +9,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// UnmatchedRoute is the path label for requests that match no route, so
+// scanners probing random paths can't create unbounded label values
+const UnmatchedRoute = "unmatched"
+
 var (
 	// HTTP request counter
 	httpRequestsTotal = promauto.NewCounterVec(
@@ -69,7 +73,7 @@ func Metrics() gin.HandlerFunc {
 		start := time.Now()
 
 		// Get request size
-		requestSize := computeApproximateRequestSize(c.Request)
+		requestSize := computeApproximateRequestSize(c)
 
 		// Process request
 		c.Next()
@@ -80,12 +84,7 @@ func Metrics() gin.HandlerFunc {
 		// Get response info
 		status := strconv.Itoa(c.Writer.Status())
 		method := c.Request.Method
-		path := c.FullPath()
-
-		// If path is empty (404), use the request path
-		if path == "" {
-			path = c.Request.URL.Path
-		}
+		path := routeLabel(c)
 
 		// Record metrics
 		httpRequestsTotal.WithLabelValues(method, path, status).Inc()
@@ -95,6 +94,15 @@ func Metrics() gin.HandlerFunc {
 	}
 }
 
+// routeLabel returns the matched route template (e.g. /api/v1/auth/me), or
+// UnmatchedRoute when no route matched
+func routeLabel(c *gin.Context) string {
+	if path := c.FullPath(); path != "" {
+		return path
+	}
+	return UnmatchedRoute
+}
+
 // computeApproximateRequestSize calculates approximate request size
 func computeApproximateRequestSize(c *gin.Context) int {
 	s := 0
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetricsUnmatchedRoute tests that unknown paths share one path label
+// instead of each creating a new series
+func TestMetricsUnmatchedRoute(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(Metrics())
+	router.GET("/api/v1/auth/me", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	unmatched := httpRequestsTotal.WithLabelValues(http.MethodGet, UnmatchedRoute, "404")
+	before := testutil.ToFloat64(unmatched)
+	seriesBefore := testutil.CollectAndCount(httpRequestsTotal)
+
+	for i := 0; i < 10; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wp-admin/"+uuid.NewString(), nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+
+	assert.Equal(t, before+10, testutil.ToFloat64(unmatched))
+	assert.Equal(t, seriesBefore, testutil.CollectAndCount(httpRequestsTotal), "unknown paths should not add series")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil))
+	assert.Equal(t, float64(1), testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, "/api/v1/auth/me", "200")))
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// NoStore marks responses as uncacheable so tokens and personal data are never
+// kept by browsers or shared caches between the client and the service.
+// Pragma covers HTTP/1.0 caches that ignore Cache-Control.
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Header("Pragma", "no-cache")
+		c.Next()
+	}
+}
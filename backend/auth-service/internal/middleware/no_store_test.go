@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNoStore tests auth responses are marked uncacheable, including errors
+func TestNoStore(t *testing.T) {
+	router := setupTestRouter()
+	auth := router.Group("/auth", NoStore())
+	auth.POST("/login", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"access_token": "token"})
+	})
+	auth.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+	})
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{}`)),
+		httptest.NewRequest(http.MethodGet, "/auth/me", nil),
+	}
+
+	for _, req := range requests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"), req.URL.Path)
+		assert.Equal(t, "no-cache", rec.Header().Get("Pragma"), req.URL.Path)
+	}
+}
@@ -2,13 +2,14 @@ package middleware
 
 import (
 	"fmt"
-	"net"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
 )
 
 // RateLimiter implements a token bucket rate limiter
@@ -17,6 +18,31 @@ type RateLimiter struct {
 	clients map[string]*client
 	limit   int
 	window  time.Duration
+
+	// allowlist holds IPs exempt from rate limiting (e.g. admin IPs during incidents)
+	allowlist *IPAllowlist
+
+	// Proxies whose X-Forwarded-For is trusted to find the client IP (nil = none)
+	trustedProxies *IPAllowlist
+
+	// Per-user limiting for requests carrying a valid access token (0 = disabled)
+	userLimit    int
+	userTokenKey utils.SigningKey
+
+	// Shared store for counting across replicas (nil = in memory)
+	store *rateLimitStore
+
+	// Builds the key requests are counted under (nil = per IP, or per user)
+	keyFunc RateLimitKeyFunc
+
+	// Labels the limiter in metrics and stats
+	name string
+
+	// Time source for windows and resets
+	clock clock.Clock
+
+	// Sheds requests with 503 under global load (nil = disabled)
+	shedder *loadShedder
 }
 
 // client represents a rate limit client
@@ -26,11 +52,17 @@ type client struct {
 }
 
 // NewRateLimiter creates a new rate limiter
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+func NewRateLimiter(limit int, window time.Duration, opts ...RateLimiterOption) *RateLimiter {
 	limiter := &RateLimiter{
 		clients: make(map[string]*client),
 		limit:   limit,
 		window:  window,
+		name:    defaultLimiterName,
+		clock:   clock.System,
+	}
+
+	for _, opt := range opts {
+		opt(limiter)
 	}
 
 	// Start cleanup goroutine
@@ -39,25 +71,82 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	return limiter
 }
 
+// SetAllowlist exempts the allowlisted IPs from rate limiting.
+// Exempt requests still pass through the rest of the chain, so they are
+// logged and audited as usual.
+func (rl *RateLimiter) SetAllowlist(allowlist *IPAllowlist) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.allowlist = allowlist
+}
+
+// SetUserLimit rate limits requests carrying a valid access token by the
+// token's user ID instead of the client IP, so users sharing an IP (e.g.
+// behind NAT) don't exhaust each other's quota. Requests without a valid
+// token are still limited per IP. The token is verified here since the
+// limiter runs before the handler authenticates the request.
+func (rl *RateLimiter) SetUserLimit(limit int, jwtSecret string) {
+	rl.SetUserLimitWithKey(limit, utils.HMACKey(jwtSecret))
+}
+
+// SetUserLimitWithKey is SetUserLimit for access tokens verified with key,
+// e.g. an RS256 public key
+func (rl *RateLimiter) SetUserLimitWithKey(limit int, key utils.SigningKey) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.userLimit = limit
+	rl.userTokenKey = key
+}
+
 // Limit returns the rate limiting middleware
 func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get client IP
-		ip := getClientIP(c)
+		ip := getClientIP(c, rl.trustedProxies)
+
+		// Allowlisted IPs bypass the limit
+		if rl.isAllowlisted(ip) {
+			c.Next()
+			return
+		}
+
+		// Shed load before spending anything on per-client accounting
+		done, admitted := rl.admit(c)
+		if !admitted {
+			return
+		}
+		defer done()
 
 		// Check rate limit
-		allowed, remaining, resetTime := rl.allow(ip)
+		key, limit := rl.keyFor(c, ip)
+
+		var allowed bool
+		var remaining int
+		var resetTime time.Time
+		if store := rl.getStore(); store != nil {
+			var err error
+			allowed, remaining, resetTime, err = rl.allowShared(c.Request.Context(), store, key, limit)
+			if err != nil {
+				if store.handleStoreError(c, key, err) {
+					c.Next()
+				}
+				return
+			}
+		} else {
+			allowed, remaining, resetTime = rl.allow(key, limit)
+		}
 
 		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rl.limit))
+		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limit))
 		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
 		c.Header("X-RateLimit-Reset", fmt.Sprintf("%d", resetTime.Unix()))
 
 		if !allowed {
-			retryAfter := time.Until(resetTime).Seconds()
+			retryAfter := resetTime.Sub(rl.clock.Now()).Seconds()
 			c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":   "rate limit exceeded",
+				"code":    appErrors.CodeRateLimited,
 				"message": fmt.Sprintf("Too many requests. Please try again in %.0f seconds.", retryAfter),
 			})
 			c.Abort()
@@ -68,26 +157,89 @@ func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	}
 }
 
-// allow checks if a request is allowed for the given IP
-func (rl *RateLimiter) allow(ip string) (bool, int, time.Time) {
+// isAllowlisted reports whether the IP is exempt from rate limiting
+func (rl *RateLimiter) isAllowlisted(ip string) bool {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.allowlist.Contains(ip)
+}
+
+// keyFor returns the rate limit key and limit for a request. With a key
+// function, the key is whatever it builds. Otherwise it is the user ID when
+// per-user limiting is enabled and the request has a valid access token,
+// and the client IP when not. Requests with a valid access token get the
+// per-user limit either way.
+func (rl *RateLimiter) keyFor(c *gin.Context, ip string) (string, int) {
+	rl.mu.RLock()
+	userLimit, key := rl.userLimit, rl.userTokenKey
+	rl.mu.RUnlock()
+
+	var userID string
+	if userLimit > 0 || (rl.keyFunc != nil && key.IsSet()) {
+		userID = userIDFromRequest(c, key)
+	}
+
+	limit := rl.limit
+	if userLimit > 0 && userID != "" {
+		limit = userLimit
+	}
+
+	if rl.keyFunc != nil {
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		return rl.keyFunc(RateLimitRequest{
+			IP:     ip,
+			Path:   path,
+			Method: c.Request.Method,
+			UserID: userID,
+		}), limit
+	}
+
+	if userID != "" && userLimit > 0 {
+		return "user:" + userID, limit
+	}
+
+	return ip, limit
+}
+
+// userIDFromRequest returns the user ID from a valid bearer access token, or ""
+func userIDFromRequest(c *gin.Context, key utils.SigningKey) string {
+	token, err := utils.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		return ""
+	}
+
+	claims, err := utils.ValidateAccessToken(token, key, 0, time.Now())
+	if err != nil {
+		return ""
+	}
+
+	return claims.UserID
+}
+
+// allow checks if a request is allowed for the given key
+func (rl *RateLimiter) allow(key string, limit int) (bool, int, time.Time) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	now := time.Now()
+	now := rl.clock.Now()
 
 	// Get or create client
-	cl, exists := rl.clients[ip]
+	cl, exists := rl.clients[key]
 	if !exists {
 		cl = &client{
-			tokens:    rl.limit,
+			tokens:    limit,
 			lastReset: now,
 		}
-		rl.clients[ip] = cl
+		rl.clients[key] = cl
+		rl.recordTrackedClients()
 	}
 
 	// Check if window has expired
 	if now.Sub(cl.lastReset) > rl.window {
-		cl.tokens = rl.limit
+		cl.tokens = limit
 		cl.lastReset = now
 	}
 
@@ -109,42 +261,15 @@ func (rl *RateLimiter) cleanup() {
 
 	for range ticker.C {
 		rl.mu.Lock()
-		now := time.Now()
+		now := rl.clock.Now()
 
 		for ip, cl := range rl.clients {
 			if now.Sub(cl.lastReset) > rl.window*2 {
 				delete(rl.clients, ip)
 			}
 		}
+		rl.recordTrackedClients()
 
 		rl.mu.Unlock()
 	}
 }
-
-// getClientIP extracts the client IP from the request
-// It checks X-Forwarded-For and X-Real-IP headers for proxy support
-func getClientIP(c *gin.Context) string {
-	// Check X-Forwarded-For header
-	xff := c.GetHeader("X-Forwarded-For")
-	if xff != "" {
-		// X-Forwarded-For can contain multiple IPs, take the first one
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// Check X-Real-IP header
-	xri := c.GetHeader("X-Real-IP")
-	if xri != "" {
-		return xri
-	}
-
-	// Fall back to RemoteAddr
-	ip, _, err := net.SplitHostPort(c.Request.RemoteAddr)
-	if err != nil {
-		return c.Request.RemoteAddr
-	}
-
-	return ip
-}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimitCompositeKey tests that an IP+path key limits each endpoint separately
+func TestRateLimitCompositeKey(t *testing.T) {
+	router := setupTestRouter()
+	limiter := NewRateLimiter(2, time.Minute, WithKeyFunc(CompositeKey(RateLimitKeyIP, RateLimitKeyPath)))
+	router.Use(limiter.Limit())
+	for _, path := range []string{"/login", "/register"} {
+		router.POST(path, func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		})
+	}
+
+	send := func(path, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Login quota is used up
+	assert.Equal(t, http.StatusOK, send("/login", "192.168.1.1:12345"))
+	assert.Equal(t, http.StatusOK, send("/login", "192.168.1.1:12345"))
+	assert.Equal(t, http.StatusTooManyRequests, send("/login", "192.168.1.1:12345"))
+
+	// Register from the same IP has its own quota
+	assert.Equal(t, http.StatusOK, send("/register", "192.168.1.1:12345"))
+	assert.Equal(t, http.StatusOK, send("/register", "192.168.1.1:12345"))
+	assert.Equal(t, http.StatusTooManyRequests, send("/register", "192.168.1.1:12345"))
+
+	// Another IP is unaffected
+	assert.Equal(t, http.StatusOK, send("/login", "192.168.1.2:12345"))
+}
+
+// TestCompositeKey tests building keys from each dimension
+func TestCompositeKey(t *testing.T) {
+	req := RateLimitRequest{IP: "10.0.0.1", Path: "/api/v1/auth/login", Method: "POST"}
+
+	assert.Equal(t, "ip=10.0.0.1|path=/api/v1/auth/login", CompositeKey(RateLimitKeyIP, RateLimitKeyPath)(req))
+	assert.Equal(t, "method=POST|user=-", CompositeKey(RateLimitKeyMethod, RateLimitKeyUser)(req))
+
+	req.UserID = "user-123"
+	assert.Equal(t, "user=user-123", CompositeKey(RateLimitKeyUser)(req))
+}
+
+// TestRateLimitCompositeKeyWithUser tests keying on the user from the access token
+func TestRateLimitCompositeKeyWithUser(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+
+	router := setupTestRouter()
+	limiter := NewRateLimiter(1, time.Minute, WithKeyFunc(CompositeKey(RateLimitKeyUser, RateLimitKeyPath)))
+	limiter.SetUserLimit(0, jwtSecret)
+	router.Use(limiter.Limit())
+	router.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	send := func(userID string) int {
+		token, err := utils.GenerateAccessToken(userID, "user@example.com", 15*time.Minute, jwtSecret)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	alice, bob := uuid.New().String(), uuid.New().String()
+	assert.Equal(t, http.StatusOK, send(alice))
+	assert.Equal(t, http.StatusTooManyRequests, send(alice))
+	assert.Equal(t, http.StatusOK, send(bob))
+}
+
+// TestParseRateLimitKeyDimensions tests parsing key dimensions from configuration
+func TestParseRateLimitKeyDimensions(t *testing.T) {
+	dimensions, err := ParseRateLimitKeyDimensions([]string{" IP", "path", ""})
+	require.NoError(t, err)
+	assert.Equal(t, []RateLimitKeyDimension{RateLimitKeyIP, RateLimitKeyPath}, dimensions)
+
+	_, err = ParseRateLimitKeyDimensions([]string{"ip", "country"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// rateLimiterShedRequests counts requests shed under load, as opposed to
+// per-client throttling
+var rateLimiterShedRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rate_limiter_shed_requests_total",
+		Help: "Number of requests rejected with 503 because too many were in flight",
+	},
+	[]string{"limiter"},
+)
+
+// loadShedder rejects requests while too many are in flight through the limiter
+type loadShedder struct {
+	maxInFlight int64
+	retryAfter  time.Duration
+	inFlight    atomic.Int64
+}
+
+// WithLoadShedding sheds requests with 503 and Retry-After while more than
+// maxInFlight are being handled, protecting the service as a whole rather
+// than throttling a client. Per-client limits still answer 429. Zero disables
+// shedding. Limiters built with the same option share the in-flight count,
+// so the cap holds across route groups (see RateLimiters).
+func WithLoadShedding(maxInFlight int, retryAfter time.Duration) RateLimiterOption {
+	var shedder *loadShedder
+	if maxInFlight > 0 {
+		shedder = &loadShedder{maxInFlight: int64(maxInFlight), retryAfter: retryAfter}
+	}
+
+	return func(rl *RateLimiter) {
+		rl.shedder = shedder
+	}
+}
+
+// admit counts the request as in flight, or sheds it when the limiter is
+// over capacity. It reports whether the request was admitted; admitted
+// requests must call done once handled.
+func (rl *RateLimiter) admit(c *gin.Context) (done func(), admitted bool) {
+	shedder := rl.shedder
+	if shedder == nil {
+		return func() {}, true
+	}
+
+	done = func() { shedder.inFlight.Add(-1) }
+	if shedder.inFlight.Add(1) <= shedder.maxInFlight {
+		return done, true
+	}
+	done()
+
+	rateLimiterShedRequests.WithLabelValues(rl.name).Inc()
+
+	retryAfter := shedder.retryAfter.Seconds()
+	c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter))
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   "service overloaded",
+		"code":    appErrors.CodeServiceUnavailable,
+		"message": fmt.Sprintf("Service is under heavy load. Please try again in %.0f seconds.", retryAfter),
+	})
+	c.Abort()
+	return nil, false
+}
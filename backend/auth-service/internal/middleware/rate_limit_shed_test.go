@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimitLoadShedding tests that requests over the in-flight threshold
+// are shed with 503 while per-client limits still answer 429
+func TestRateLimitLoadShedding(t *testing.T) {
+	t.Run("global pressure sheds with 503", func(t *testing.T) {
+		router := setupTestRouter()
+		limiter := NewRateLimiter(100, time.Minute, WithLoadShedding(1, 2*time.Second))
+		router.Use(limiter.Limit())
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		router.GET("/slow", func(c *gin.Context) {
+			close(started)
+			<-release
+			c.Status(http.StatusOK)
+		})
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+			req.RemoteAddr = "192.168.1.1:12345"
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		<-started
+
+		// A different client is shed while the slow request is in flight
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.2:12345"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+		close(release)
+		wg.Wait()
+
+		// Capacity frees up once the slow request finishes
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("per-client limit still returns 429", func(t *testing.T) {
+		router := setupTestRouter()
+		limiter := NewRateLimiter(1, time.Minute, WithLoadShedding(10, time.Second))
+		router.Use(limiter.Limit())
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		for _, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = "192.168.1.1:12345"
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, want, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"unsafe"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// rateLimiterTrackedClients counts the keys each in-memory limiter holds, to
+// spot the client map growing faster than cleanup shrinks it
+var rateLimiterTrackedClients = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "rate_limiter_tracked_clients",
+		Help: "Number of clients tracked in memory by each rate limiter",
+	},
+	[]string{"limiter"},
+)
+
+// defaultLimiterName labels limiters created without WithLimiterName
+const defaultLimiterName = "default"
+
+// clientEntryOverhead approximates the memory of one tracked client besides
+// its key: the client struct, the pointer to it and map bookkeeping
+const clientEntryOverhead = int64(unsafe.Sizeof(client{})) + 48
+
+// RateLimiterStats describes a rate limiter's current state
+type RateLimiterStats struct {
+	Name           string `json:"name"`
+	TrackedClients int    `json:"tracked_clients"`
+	// EstimatedMemoryBytes is a rough estimate of the client map's size.
+	// Zero when counts are kept in a shared store.
+	EstimatedMemoryBytes int64 `json:"estimated_memory_bytes"`
+	Shared               bool  `json:"shared"`
+}
+
+// WithLimiterName labels the limiter in metrics and stats
+func WithLimiterName(name string) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.name = name
+	}
+}
+
+// Stats returns the number of clients the limiter tracks and, for the
+// in-memory limiter, an estimate of the memory they use
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	stats := RateLimiterStats{
+		Name:           rl.name,
+		TrackedClients: len(rl.clients),
+		Shared:         rl.store != nil,
+	}
+	if !stats.Shared {
+		for key := range rl.clients {
+			stats.EstimatedMemoryBytes += int64(len(key)) + clientEntryOverhead
+		}
+	}
+	return stats
+}
+
+// recordTrackedClients updates the tracked clients gauge. Callers hold rl.mu.
+func (rl *RateLimiter) recordTrackedClients() {
+	rateLimiterTrackedClients.WithLabelValues(rl.name).Set(float64(len(rl.clients)))
+}
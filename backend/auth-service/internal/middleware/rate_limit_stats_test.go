@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimiterStats tests that stats and the gauge count distinct clients
+func TestRateLimiterStats(t *testing.T) {
+	router := setupTestRouter()
+	limiter := NewRateLimiter(5, time.Minute, WithLimiterName("stats_test"))
+	router.Use(limiter.Limit())
+
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, 0, limiter.Stats().TrackedClients)
+
+	for _, addr := range []string{"192.168.1.1:1000", "192.168.1.2:1000", "192.168.1.1:2000", "192.168.1.3:1000"} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = addr
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	stats := limiter.Stats()
+	assert.Equal(t, "stats_test", stats.Name)
+	assert.Equal(t, 3, stats.TrackedClients)
+	assert.False(t, stats.Shared)
+	assert.Greater(t, stats.EstimatedMemoryBytes, int64(3*len("192.168.1.1")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(rateLimiterTrackedClients.WithLabelValues("stats_test")))
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimitCounter counts requests per key within an expiry window, shared
+// across replicas (see internal/cache)
+type RateLimitCounter interface {
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// RateLimitFailMode selects how the limiter behaves when its shared store is unavailable
+type RateLimitFailMode string
+
+const (
+	// RateLimitFailOpen allows requests and logs the store error
+	RateLimitFailOpen RateLimitFailMode = "open"
+	// RateLimitFailClosed rejects requests with 503 and a Retry-After header
+	RateLimitFailClosed RateLimitFailMode = "closed"
+)
+
+// ParseRateLimitFailMode parses a rate limit fail mode from configuration
+func ParseRateLimitFailMode(value string) (RateLimitFailMode, error) {
+	switch mode := RateLimitFailMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", RateLimitFailOpen:
+		return RateLimitFailOpen, nil
+	case RateLimitFailClosed:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid rate limit fail mode: %q", value)
+	}
+}
+
+// rateLimitStore holds the shared store and its failure policy
+type rateLimitStore struct {
+	counter    RateLimitCounter
+	failMode   RateLimitFailMode
+	retryAfter time.Duration
+	logger     *logrus.Logger
+}
+
+// SetStore counts requests in a shared store (e.g. Redis) instead of in
+// memory, so the limit holds across replicas. When the store errors,
+// failMode decides whether requests are allowed (open) or rejected with
+// 503 and a Retry-After of retryAfter (closed).
+func (rl *RateLimiter) SetStore(counter RateLimitCounter, failMode RateLimitFailMode, retryAfter time.Duration, logger *logrus.Logger) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.store = &rateLimitStore{
+		counter:    counter,
+		failMode:   failMode,
+		retryAfter: retryAfter,
+		logger:     logger,
+	}
+}
+
+// getStore returns the shared store, or nil when counting in memory
+func (rl *RateLimiter) getStore() *rateLimitStore {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.store
+}
+
+// allowShared checks if a request is allowed for the given key using a
+// fixed window in the shared store
+func (rl *RateLimiter) allowShared(ctx context.Context, store *rateLimitStore, key string, limit int) (bool, int, time.Time, error) {
+	windowStart := rl.clock.Now().Truncate(rl.window)
+	resetTime := windowStart.Add(rl.window)
+
+	// Limiters sharing a store keep separate counts
+	storeKey := fmt.Sprintf("ratelimit:%s:%s:%d", rl.name, key, windowStart.Unix())
+	count, err := store.counter.Increment(ctx, storeKey, rl.window)
+	if err != nil {
+		return false, 0, resetTime, err
+	}
+
+	if count > int64(limit) {
+		return false, 0, resetTime, nil
+	}
+
+	return true, limit - int(count), resetTime, nil
+}
+
+// handleStoreError applies the fail mode after the shared store errors.
+// It reports whether the request may continue.
+func (store *rateLimitStore) handleStoreError(c *gin.Context, key string, err error) bool {
+	if store.logger != nil {
+		store.logger.WithError(err).WithFields(logrus.Fields{
+			"key":       key,
+			"fail_mode": store.failMode,
+		}).Warn("Rate limit store unavailable")
+	}
+
+	if store.failMode != RateLimitFailClosed {
+		return true
+	}
+
+	retryAfter := store.retryAfter.Seconds()
+	c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter))
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   "service unavailable",
+		"code":    appErrors.CodeServiceUnavailable,
+		"message": fmt.Sprintf("Service temporarily unavailable. Please try again in %.0f seconds.", retryAfter),
+	})
+	c.Abort()
+	return false
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/cache"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingCounter is a RateLimitCounter whose store is down
+type failingCounter struct{}
+
+func (failingCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return 0, errors.New("dial tcp: connection refused")
+}
+
+// newStoreTestRouter returns a router limited through the given store
+func newStoreTestRouter(counter RateLimitCounter, failMode RateLimitFailMode) *gin.Engine {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	limiter := NewRateLimiter(2, time.Minute)
+	limiter.SetStore(counter, failMode, 7*time.Second, logger)
+
+	router := setupTestRouter()
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	return router
+}
+
+// TestRateLimitStore tests that counts are kept in the shared store
+func TestRateLimitStore(t *testing.T) {
+	counter := cache.NewMemoryCounter()
+
+	// Two replicas sharing one store share the limit
+	replicaA := newStoreTestRouter(counter, RateLimitFailClosed)
+	replicaB := newStoreTestRouter(counter, RateLimitFailClosed)
+
+	send := func(router *gin.Engine) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(replicaA)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, http.StatusOK, send(replicaB).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(replicaA).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(replicaB).Code)
+}
+
+// TestRateLimitStoreFailure tests both fail modes when the store errors
+func TestRateLimitStoreFailure(t *testing.T) {
+	send := func(router *gin.Engine) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("fail open allows requests", func(t *testing.T) {
+		router := newStoreTestRouter(failingCounter{}, RateLimitFailOpen)
+
+		// Past the limit of 2, since nothing is being counted
+		for i := 0; i < 5; i++ {
+			rec := send(router)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, rec.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("fail closed returns 503 with Retry-After", func(t *testing.T) {
+		router := newStoreTestRouter(failingCounter{}, RateLimitFailClosed)
+
+		rec := send(router)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "7", rec.Header().Get("Retry-After"))
+		assert.Contains(t, rec.Body.String(), `"code":"SERVICE_UNAVAILABLE"`)
+	})
+}
+
+// TestParseRateLimitFailMode tests parsing fail modes from configuration
+func TestParseRateLimitFailMode(t *testing.T) {
+	mode, err := ParseRateLimitFailMode("")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitFailOpen, mode)
+
+	mode, err = ParseRateLimitFailMode(" Closed ")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitFailClosed, mode)
+
+	_, err = ParseRateLimitFailMode("sometimes")
+	assert.Error(t, err)
+}
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -137,8 +140,8 @@ func TestRateLimitByIP(t *testing.T) {
 // TestRateLimitReset tests that rate limit resets after window
 func TestRateLimitReset(t *testing.T) {
 	router := setupTestRouter()
-	// Very short window for testing
-	limiter := NewRateLimiter(2, 100*time.Millisecond)
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	limiter := NewRateLimiter(2, time.Minute, WithLimiterClock(fakeClock))
 	router.Use(limiter.Limit())
 
 	router.GET("/test", func(c *gin.Context) {
@@ -161,8 +164,10 @@ func TestRateLimitReset(t *testing.T) {
 	router.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "3rd request should be blocked")
 
-	// Wait for window to expire
-	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	// Move past the window
+	fakeClock.Advance(61 * time.Second)
 
 	// Request after window (should pass)
 	req = httptest.NewRequest(http.MethodGet, "/test", nil)
@@ -250,8 +255,12 @@ func TestRateLimitCleanup(t *testing.T) {
 
 // TestRateLimitWithXForwardedFor tests rate limiting with proxy headers
 func TestRateLimitWithXForwardedFor(t *testing.T) {
+	// httptest requests come from 192.0.2.1
+	trustedProxies, err := NewIPAllowlist([]string{"192.0.2.1"})
+	require.NoError(t, err)
+
 	router := setupTestRouter()
-	limiter := NewRateLimiter(2, time.Minute)
+	limiter := NewRateLimiter(2, time.Minute, WithTrustedProxies(trustedProxies))
 	router.Use(limiter.Limit())
 
 	router.GET("/test", func(c *gin.Context) {
@@ -274,3 +283,112 @@ func TestRateLimitWithXForwardedFor(t *testing.T) {
 	router.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "3rd request should be blocked")
 }
+
+// TestRateLimitAllowlist tests that allowlisted IPs bypass rate limiting
+func TestRateLimitAllowlist(t *testing.T) {
+	allowlist, err := NewIPAllowlist([]string{"203.0.113.7", "10.0.0.0/8"})
+	require.NoError(t, err)
+
+	router := setupTestRouter()
+	limiter := NewRateLimiter(2, time.Minute)
+	limiter.SetAllowlist(allowlist)
+	router.Use(limiter.Limit())
+	router.POST("/login", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	send := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("allowlisted IP bypasses limit", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusOK, send("203.0.113.7:12345"))
+		}
+	})
+
+	t.Run("allowlisted CIDR bypasses limit", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusOK, send("10.20.30.40:12345"))
+		}
+	})
+
+	t.Run("normal IP is still limited", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("192.168.1.1:12345"))
+		assert.Equal(t, http.StatusOK, send("192.168.1.1:12345"))
+		assert.Equal(t, http.StatusTooManyRequests, send("192.168.1.1:12345"))
+	})
+}
+
+// TestNewIPAllowlist tests allowlist parsing and matching
+func TestNewIPAllowlist(t *testing.T) {
+	allowlist, err := NewIPAllowlist([]string{" 198.51.100.1 ", "", "2001:db8::/32"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, allowlist.Len())
+
+	assert.True(t, allowlist.Contains("198.51.100.1"))
+	assert.False(t, allowlist.Contains("198.51.100.2"))
+	assert.True(t, allowlist.Contains("2001:db8::1"))
+	assert.False(t, allowlist.Contains("not-an-ip"))
+
+	var empty *IPAllowlist
+	assert.False(t, empty.Contains("198.51.100.1"))
+
+	_, err = NewIPAllowlist([]string{"300.1.1.1"})
+	assert.Error(t, err)
+
+	_, err = NewIPAllowlist([]string{"10.0.0.0/99"})
+	assert.Error(t, err)
+}
+
+// TestRateLimitPerUser tests per-user limiting for authenticated requests
+func TestRateLimitPerUser(t *testing.T) {
+	const jwtSecret = "test-secret-key-at-least-32-chars-long-for-security"
+
+	router := setupTestRouter()
+	limiter := NewRateLimiter(100, time.Minute)
+	limiter.SetUserLimit(2, jwtSecret)
+	router.Use(limiter.Limit())
+	router.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	tokenFor := func(userID string) string {
+		token, err := utils.GenerateAccessToken(userID, userID+"@example.com", 15*time.Minute, jwtSecret)
+		require.NoError(t, err)
+		return token
+	}
+	alice := tokenFor(uuid.New().String())
+	bob := tokenFor(uuid.New().String())
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.RemoteAddr = "192.168.1.1:12345" // Same NAT IP for everyone
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Alice uses her quota
+	assert.Equal(t, http.StatusOK, send(alice).Code)
+	assert.Equal(t, http.StatusOK, send(alice).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(alice).Code)
+
+	// Bob, behind the same IP, is unaffected
+	rec := send(bob)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+
+	// Unauthenticated and invalid-token requests fall back to the per-IP limit
+	rec = send("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "100", send("not-a-valid-token").Header().Get("X-RateLimit-Limit"))
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiters builds named rate limiters that share options and settings,
+// so each route group can have its own limit. Routes limited under the same
+// name share a bucket; routes under different names are counted separately,
+// so exhausting one doesn't affect the others.
+type RateLimiters struct {
+	mu        sync.Mutex
+	limiters  map[string]*RateLimiter
+	opts      []RateLimiterOption
+	configure func(rl *RateLimiter)
+}
+
+// NewRateLimiters creates a set of named rate limiters. Every limiter is
+// built with opts and then passed to configure (if not nil), e.g. to set
+// its allowlist or shared store.
+func NewRateLimiters(configure func(rl *RateLimiter), opts ...RateLimiterOption) *RateLimiters {
+	return &RateLimiters{
+		limiters:  make(map[string]*RateLimiter),
+		opts:      opts,
+		configure: configure,
+	}
+}
+
+// Limiter returns the limiter named name, creating it with limit requests
+// per window on first use. Later calls with the same name return the same
+// limiter, ignoring limit and window.
+func (r *RateLimiters) Limiter(name string, limit int, window time.Duration) *RateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limiter, ok := r.limiters[name]; ok {
+		return limiter
+	}
+
+	opts := append(append([]RateLimiterOption(nil), r.opts...), WithLimiterName(name))
+	limiter := NewRateLimiter(limit, window, opts...)
+	if r.configure != nil {
+		r.configure(limiter)
+	}
+
+	r.limiters[name] = limiter
+	return limiter
+}
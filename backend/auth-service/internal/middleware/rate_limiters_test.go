@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimiters tests that routes limited under different names have
+// separate buckets and limits, and that unlimited routes aren't counted
+func TestRateLimiters(t *testing.T) {
+	limiters := NewRateLimiters(nil)
+	loginLimit := limiters.Limiter("login", 2, time.Minute).Limit()
+	apiLimit := limiters.Limiter("api", 3, time.Minute).Limit()
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router := setupTestRouter()
+	router.GET("/health", ok)
+	router.POST("/login", loginLimit, ok)
+	router.POST("/refresh", apiLimit, ok)
+	router.POST("/logout", apiLimit, ok)
+
+	send := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Health checks don't use up any budget
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/health"))
+	}
+
+	// Exhausting login...
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/login"))
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/login"))
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "/login"))
+
+	// ...leaves refresh with its own, larger limit, shared with logout
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/refresh"))
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/refresh"))
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/logout"))
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "/refresh"))
+
+	// Asking for a limiter again returns the existing one
+	assert.Same(t, limiters.Limiter("login", 100, time.Minute), limiters.Limiter("login", 2, time.Minute))
+	assert.Equal(t, "login", limiters.Limiter("login", 2, time.Minute).Stats().Name)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Recovery returns a middleware that recovers from panics in downstream handlers.
+// The panic and its stack trace are logged, while the client only receives the
+// standard JSON error body.
+func Recovery(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.WithFields(logrus.Fields{
+					"panic":      fmt.Sprintf("%v", recovered),
+					"stack":      string(debug.Stack()),
+					"method":     c.Request.Method,
+					"path":       c.Request.URL.Path,
+					"ip":         c.ClientIP(),
+					"request_id": c.GetHeader("X-Request-ID"),
+				}).Error("Recovered from panic")
+
+				// Headers may already be flushed; only write a body if we still can
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error": "an unexpected error occurred",
+					"code":  appErrors.CodeInternal,
+				})
+			}
+		}()
+
+		c.Next()
+	}
+}
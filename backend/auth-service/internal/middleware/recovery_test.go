@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecoveryMiddleware tests panic recovery
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Run("panic returns JSON 500 and is logged", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		router := setupTestRouter()
+		router.Use(Recovery(logger))
+		router.GET("/panic", func(c *gin.Context) {
+			panic("something went badly wrong")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "an unexpected error occurred", response["error"])
+
+		// Stack trace must not leak to the client
+		assert.NotContains(t, rec.Body.String(), "something went badly wrong")
+		assert.NotContains(t, rec.Body.String(), "goroutine")
+
+		require.Len(t, hook.Entries, 1)
+		entry := hook.LastEntry()
+		assert.Equal(t, logrus.ErrorLevel, entry.Level)
+		assert.Equal(t, "something went badly wrong", entry.Data["panic"])
+		assert.Equal(t, "req-123", entry.Data["request_id"])
+		assert.Contains(t, entry.Data["stack"], "goroutine")
+	})
+
+	t.Run("no panic passes through", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+
+		router := setupTestRouter()
+		router.Use(Recovery(logger))
+		router.GET("/ok", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, hook.Entries)
+	})
+}
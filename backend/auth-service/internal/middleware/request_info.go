@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+)
+
+// RequestInfo returns a middleware that stores client metadata (IP, user agent,
+// request ID, method) in the request context so services can use it for auditing.
+// Forwarding headers are honored only from trustedProxies (see ClientIP).
+func RequestInfo(trustedProxies *IPAllowlist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := requestinfo.Info{
+			IP:        getClientIP(c, trustedProxies),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: c.GetHeader("X-Request-ID"),
+			Method:    c.Request.Method,
+		}
+
+		c.Request = c.Request.WithContext(requestinfo.NewContext(c.Request.Context(), info))
+		c.Next()
+	}
+}
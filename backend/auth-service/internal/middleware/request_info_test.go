@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequestInfoMiddleware tests that client metadata is stored in the request context
+func TestRequestInfoMiddleware(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(RequestInfo(nil))
+
+	var info requestinfo.Info
+	router.GET("/test", func(c *gin.Context) {
+		info = requestinfo.FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.RemoteAddr = "192.168.1.10:12345"
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	req.Header.Set("X-Request-ID", "req-abc")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "192.168.1.10", info.IP)
+	assert.Equal(t, "test-agent/1.0", info.UserAgent)
+	assert.Equal(t, "req-abc", info.RequestID)
+	assert.Equal(t, http.MethodGet, info.Method)
+}
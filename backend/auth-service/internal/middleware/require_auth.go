@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// Context keys set by RequireAuth
+const (
+	CurrentUserKey = "currentUser"
+	TokenClaimsKey = "tokenClaims"
+)
+
+// AuthService validates access tokens (see services.AuthService)
+type AuthService interface {
+	ValidateAccessTokenWithClaims(ctx context.Context, accessToken string) (*models.User, *utils.RegisteredTokenClaims, error)
+}
+
+// RequireAuth validates the bearer token in the Authorization header and
+// stores the authenticated user and the token's claims in the context (see
+// CurrentUser). Requests without a valid token are aborted with 401, or with
+// the status of the service error, such as 403 for a suspended account.
+func RequireAuth(authService AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken, err := utils.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+				"code":  appErrors.CodeUnauthorized,
+			})
+			return
+		}
+
+		user, claims, err := authService.ValidateAccessTokenWithClaims(c.Request.Context(), accessToken)
+		if err != nil {
+			abortUnauthenticated(c, err)
+			return
+		}
+
+		c.Set(CurrentUserKey, user)
+		c.Set(TokenClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// CurrentUser returns the user authenticated by RequireAuth
+func CurrentUser(c *gin.Context) (*models.User, bool) {
+	user, ok := c.Get(CurrentUserKey)
+	if !ok {
+		return nil, false
+	}
+	u, ok := user.(*models.User)
+	return u, ok && u != nil
+}
+
+// CurrentTokenClaims returns the claims of the token authenticated by RequireAuth
+func CurrentTokenClaims(c *gin.Context) (*utils.RegisteredTokenClaims, bool) {
+	claims, ok := c.Get(TokenClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	tc, ok := claims.(*utils.RegisteredTokenClaims)
+	return tc, ok && tc != nil
+}
+
+// abortUnauthenticated aborts with the service error's status and code, the
+// same body handlers render; unexpected errors are a 500
+func abortUnauthenticated(c *gin.Context, err error) {
+	appErr := appErrors.GetAppError(err)
+	if appErr == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "an unexpected error occurred",
+			"code":  appErrors.CodeInternal,
+		})
+		return
+	}
+
+	c.AbortWithStatusJSON(appErr.StatusCode, gin.H{
+		"error": appErr.Message,
+		"code":  appErr.Code,
+	})
+}
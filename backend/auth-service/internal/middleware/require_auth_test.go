@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAuthService accepts one token and fails every other with err
+type stubAuthService struct {
+	token string
+	user  *models.User
+	err   error
+}
+
+func (s stubAuthService) ValidateAccessTokenWithClaims(ctx context.Context, accessToken string) (*models.User, *utils.RegisteredTokenClaims, error) {
+	if accessToken != s.token {
+		return nil, nil, s.err
+	}
+	return s.user, &utils.RegisteredTokenClaims{Subject: s.user.ID.String()}, nil
+}
+
+// TestRequireAuth tests that only requests with a valid token reach the
+// handler, which can read the authenticated user from the context
+func TestRequireAuth(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "john.doe@example.com"}
+
+	serve := func(service AuthService, authHeader string) (*httptest.ResponseRecorder, bool) {
+		reached := false
+		router := setupTestRouter()
+		router.GET("/test", RequireAuth(service), func(c *gin.Context) {
+			reached = true
+			current, ok := CurrentUser(c)
+			assert.True(t, ok)
+			assert.Equal(t, user.ID, current.ID)
+			claims, ok := CurrentTokenClaims(c)
+			assert.True(t, ok)
+			assert.Equal(t, user.ID.String(), claims.Subject)
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec, reached
+	}
+
+	service := stubAuthService{token: "valid-token", user: user, err: appErrors.NewUnauthorized("invalid or expired access token")}
+
+	t.Run("valid token reaches the handler", func(t *testing.T) {
+		rec, reached := serve(service, "Bearer valid-token")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, reached)
+	})
+
+	t.Run("missing header is rejected", func(t *testing.T) {
+		rec, reached := serve(service, "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"UNAUTHORIZED"`)
+		assert.False(t, reached)
+	})
+
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		rec, reached := serve(service, "Bearer forged-token")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid or expired access token")
+		assert.False(t, reached)
+	})
+
+	t.Run("service status errors keep their status", func(t *testing.T) {
+		suspended := stubAuthService{token: "valid-token", user: user, err: appErrors.NewAccountStatusError(appErrors.CodeAccountSuspended, "account is suspended")}
+		rec, reached := serve(suspended, "Bearer other-token")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"ACCOUNT_SUSPENDED"`)
+		assert.False(t, reached)
+	})
+
+	t.Run("unexpected errors are a 500", func(t *testing.T) {
+		broken := stubAuthService{token: "valid-token", user: user, err: errors.New("connection refused")}
+		rec, reached := serve(broken, "Bearer other-token")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.False(t, reached)
+	})
+
+	t.Run("without the middleware there is no current user", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		_, ok := CurrentUser(c)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response signing headers
+const (
+	// PartnerIDHeader identifies the partner whose secret signs the response
+	PartnerIDHeader = "X-Partner-ID"
+	// SignatureHeader carries the response signature as "sha256=<hex hmac>"
+	SignatureHeader = "X-Signature"
+)
+
+// ResponseSigningConfig holds response signing configuration
+type ResponseSigningConfig struct {
+	Paths   []string          // Request paths whose responses are signed
+	Secrets map[string]string // Partner ID -> HMAC secret
+}
+
+// bufferedResponseWriter holds the response body until it has been signed
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write buffers the data instead of sending it
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteString buffers the string instead of sending it
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ResponseSigning returns a middleware that signs response bodies on the
+// configured paths with an HMAC-SHA256 of the calling partner's secret, so
+// partners can verify responses came from us. Requests without a known
+// X-Partner-ID are passed through unsigned.
+func ResponseSigning(config *ResponseSigningConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !contains(config.Paths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		secret, ok := config.Secrets[c.GetHeader(PartnerIDHeader)]
+		if !ok || secret == "" {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedResponseWriter{ResponseWriter: original, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		body := writer.body.Bytes()
+
+		// Headers are still unsent: the status is only recorded until the body is written
+		if !original.Written() {
+			original.Header().Set(SignatureHeader, "sha256="+SignResponse(body, secret))
+		}
+		if len(body) > 0 {
+			_, _ = original.Write(body)
+		}
+	}
+}
+
+// SignResponse computes the hex HMAC-SHA256 of a response body
+func SignResponse(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResponseSigning tests HMAC signing of responses for partners
+func TestResponseSigning(t *testing.T) {
+	const partnerSecret = "partner-a-shared-secret"
+
+	router := setupTestRouter()
+	router.Use(ResponseSigning(&ResponseSigningConfig{
+		Paths:   []string{"/api/v1/auth/me"},
+		Secrets: map[string]string{"partner-a": partnerSecret},
+	}))
+	router.GET("/api/v1/auth/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": "123", "email": "john@example.com"})
+	})
+	router.GET("/api/v1/other", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	send := func(path, partnerID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if partnerID != "" {
+			req.Header.Set(PartnerIDHeader, partnerID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("signed endpoint signature verifies against body", func(t *testing.T) {
+		rec := send("/api/v1/auth/me", "partner-a")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "john@example.com")
+
+		signature := rec.Header().Get(SignatureHeader)
+		require.True(t, strings.HasPrefix(signature, "sha256="), "signature header missing: %q", signature)
+
+		// Verify as a partner would
+		mac := hmac.New(sha256.New, []byte(partnerSecret))
+		mac.Write(rec.Body.Bytes())
+		expected := hex.EncodeToString(mac.Sum(nil))
+		assert.True(t, hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256="))))
+	})
+
+	t.Run("unknown partner is not signed", func(t *testing.T) {
+		rec := send("/api/v1/auth/me", "partner-b")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(SignatureHeader))
+	})
+
+	t.Run("unconfigured path is not signed", func(t *testing.T) {
+		rec := send("/api/v1/other", "partner-a")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(SignatureHeader))
+	})
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// AccountClosure describes a pending account closure. The account stays
+// usable until ClosesAt, when it is anonymized and closed.
+type AccountClosure struct {
+	RequestedAt time.Time `json:"requested_at"`
+	ClosesAt    time.Time `json:"closes_at"`
+}
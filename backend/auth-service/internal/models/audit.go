@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event types
+const (
+	AuditEventLoginSuccess = "login_success"
+	AuditEventLoginFailure = "login_failure"
+
+	// AuditEventPossibleDuplicate flags a registration matching an existing identity for review
+	AuditEventPossibleDuplicate = "registration_possible_duplicate"
+
+	// AuditEventTokenRevoked records an access token revoked by jti
+	AuditEventTokenRevoked = "token_revoked"
+
+	// AuditEventRefreshTokenReuse records an already-rotated refresh token being presented again
+	AuditEventRefreshTokenReuse = "refresh_token_reuse"
+
+	// AuditEventProfileUpdated records which profile fields a user changed
+	AuditEventProfileUpdated = "profile_updated"
+)
+
+// AuditEvent represents a security-relevant event recorded for later review
+type AuditEvent struct {
+	ID             uuid.UUID         `json:"id" db:"id"`
+	UserID         *uuid.UUID        `json:"user_id,omitempty" db:"user_id"`
+	EventType      string            `json:"event_type" db:"event_type"`
+	IPAddress      string            `json:"ip_address" db:"ip_address"`
+	UserAgent      string            `json:"user_agent" db:"user_agent"`
+	Country        string            `json:"country,omitempty" db:"country"`
+	ASN            uint              `json:"asn,omitempty" db:"asn"`
+	ASOrganization string            `json:"as_organization,omitempty" db:"as_organization"`
+	Metadata       map[string]string `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+}
+
+// GeoLocation holds GeoIP data resolved for an IP address
+type GeoLocation struct {
+	Country        string // ISO 3166-1 alpha-2 country code
+	ASN            uint   // Autonomous system number
+	ASOrganization string // Autonomous system organization
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DataExport is the personal data held about a user, returned for GDPR
+// subject access requests
+type DataExport struct {
+	ExportedAt  time.Time     `json:"exported_at"`
+	Profile     *User         `json:"profile"`
+	KYC         KYCExport     `json:"kyc"`
+	AuditEvents []*AuditEvent `json:"audit_events"`
+	Sessions    []*Session    `json:"sessions"`
+}
+
+// KYCExport is the user's identity verification status
+type KYCExport struct {
+	Status     string     `json:"status"`
+	VerifiedAt *time.Time `json:"verified_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceBinding ties a device ID to the one user allowed to use it
+type DeviceBinding struct {
+	DeviceID  string    `json:"device_id" db:"device_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
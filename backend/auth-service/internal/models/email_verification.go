@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerification is a user's outstanding email verification token. Only a
+// hash of the token is kept, and each user has at most one: sending a new
+// token replaces the previous one.
+type EmailVerification struct {
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Login activity results
+const (
+	LoginResultSuccess = "success"
+	LoginResultFailure = "failure"
+)
+
+// DefaultLoginActivityLimit is the page size when the request doesn't set one
+const DefaultLoginActivityLimit = 20
+
+// LoginActivityQuery is the paging for a login activity request
+type LoginActivityQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+// LoginActivity is one login attempt on the caller's account, for a recent
+// activity page
+type LoginActivity struct {
+	At        time.Time `json:"at"`
+	Result    string    `json:"result"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Country   string    `json:"country,omitempty"`
+}
+
+// LoginActivityPage is a page of login attempts, newest first
+type LoginActivityPage struct {
+	Activity []*LoginActivity `json:"activity"`
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+	Total    int              `json:"total"`
+	HasMore  bool             `json:"has_more"`
+}
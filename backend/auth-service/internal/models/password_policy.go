@@ -0,0 +1,16 @@
+package models
+
+// PasswordPolicy describes the rules new passwords must meet, published so
+// clients can show the same requirements the server enforces
+type PasswordPolicy struct {
+	MinLength           int    `json:"min_length"` // Minimum length in characters
+	MaxBytes            int    `json:"max_bytes"`  // Maximum length in bytes of UTF-8
+	RequireUppercase    bool   `json:"require_uppercase"`
+	RequireLowercase    bool   `json:"require_lowercase"`
+	RequireDigit        bool   `json:"require_digit"`
+	RequireSpecial      bool   `json:"require_special"`
+	SpecialCharacters   string `json:"special_characters"` // Characters that count as special
+	MinUniqueChars      int    `json:"min_unique_chars"`   // Minimum distinct characters (0 = no minimum)
+	CommonPasswordCheck bool   `json:"common_password_check"`
+	BreachCheck         bool   `json:"breach_check"` // Checked against known breached passwords
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRecord is an issued refresh token as stored for rotation. Only
+// a hash of the token is kept. Every token rotated from the same login shares
+// a FamilyID, so presenting an already-rotated token can revoke the family.
+type RefreshTokenRecord struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// SecuritySummary describes the security posture of a user's account, for a
+// security settings page
+type SecuritySummary struct {
+	LastLogin                  *LoginRecord `json:"last_login"`
+	FailedLoginsSinceLastLogin int          `json:"failed_logins_since_last_login"`
+	ActiveSessions             *int         `json:"active_sessions"`
+	ClosureRequestedAt         *time.Time   `json:"closure_requested_at"`
+}
+
+// LoginSummary is a user's most recent successful login and the failed
+// attempts after it, aggregated from the audit trail
+type LoginSummary struct {
+	LastLogin            *LoginRecord
+	FailedSinceLastLogin int
+}
+
+// LoginRecord is when and where a login happened
+type LoginRecord struct {
+	At        time.Time `json:"at"`
+	IPAddress string    `json:"ip_address"`
+	Country   string    `json:"country,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a signed-in device, tracked per refresh token. Revoking it
+// invalidates the refresh token and the access tokens issued with it.
+type Session struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"-" db:"user_id"`
+	DeviceID   string     `json:"device_id,omitempty" db:"device_id"`
+	DeviceType string     `json:"device_type,omitempty" db:"device_type"`
+	IPAddress  string     `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent  string     `json:"user_agent,omitempty" db:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+
+	// Current marks the session the listing request was made from
+	Current bool `json:"current"`
+}
+
+// IsActive reports whether the session can still refresh tokens at now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// SessionRevocation reports how many sessions were revoked
+type SessionRevocation struct {
+	Revoked int `json:"revoked"`
+}
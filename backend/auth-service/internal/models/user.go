@@ -1,31 +1,66 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// UserStatus is the lifecycle state of a user account
+type UserStatus string
+
+const (
+	// UserStatusActive accounts can log in
+	UserStatusActive UserStatus = "active"
+	// UserStatusPending accounts are awaiting verification
+	UserStatusPending UserStatus = "pending"
+	// UserStatusSuspended accounts are temporarily blocked (e.g. fraud review)
+	UserStatusSuspended UserStatus = "suspended"
+	// UserStatusClosed accounts have been closed for good
+	UserStatusClosed UserStatus = "closed"
+)
+
 // User represents a user in the system
 type User struct {
-	ID              uuid.UUID  `json:"id" db:"id"`
-	Email           string     `json:"email" db:"email"`
-	Phone           string     `json:"phone" db:"phone"`
-	PasswordHash    string     `json:"-" db:"password_hash"` // Never expose in JSON
-	FirstName       string     `json:"first_name" db:"first_name"`
-	LastName        string     `json:"last_name" db:"last_name"`
-	DateOfBirth     time.Time  `json:"date_of_birth" db:"date_of_birth"`
-	AddressLine1    string     `json:"address_line1" db:"address_line1"`
-	AddressLine2    string     `json:"address_line2" db:"address_line2"`
-	City            string     `json:"city" db:"city"`
-	Region          string     `json:"region" db:"region"`
-	Postcode        string     `json:"postcode" db:"postcode"`
-	Country         string     `json:"country" db:"country"`
-	KYCStatus       string     `json:"kyc_status" db:"kyc_status"`
-	KYCVerifiedAt   *time.Time `json:"kyc_verified_at" db:"kyc_verified_at"`
-	IsActive        bool       `json:"is_active" db:"is_active"`
-	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	Email              string     `json:"email" db:"email"`
+	Phone              string     `json:"phone" db:"phone"`
+	PasswordHash       string     `json:"-" db:"password_hash"` // Never expose in JSON
+	FirstName          string     `json:"first_name" db:"first_name"`
+	LastName           string     `json:"last_name" db:"last_name"`
+	DateOfBirth        time.Time  `json:"date_of_birth" db:"date_of_birth"`
+	AddressLine1       string     `json:"address_line1" db:"address_line1"`
+	AddressLine2       string     `json:"address_line2" db:"address_line2"`
+	City               string     `json:"city" db:"city"`
+	Region             string     `json:"region" db:"region"`
+	Postcode           string     `json:"postcode" db:"postcode"`
+	Country            string     `json:"country" db:"country"`
+	KYCStatus          string     `json:"kyc_status" db:"kyc_status"`
+	KYCVerifiedAt      *time.Time `json:"kyc_verified_at" db:"kyc_verified_at"`
+	Status             UserStatus `json:"status" db:"status"`
+	EmailVerified      bool       `json:"email_verified" db:"email_verified"`
+	ClosureRequestedAt *time.Time `json:"closure_requested_at" db:"closure_requested_at"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsActive reports whether the account is active. It is kept for
+// compatibility with the is_active flag that Status replaced.
+func (u *User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// MarshalJSON includes the derived is_active flag for existing clients
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return json.Marshal(struct {
+		user
+		IsActive bool `json:"is_active"`
+	}{
+		user:     user(u),
+		IsActive: u.IsActive(),
+	})
 }
 
 // RegisterRequest represents user registration request
@@ -42,6 +77,34 @@ type RegisterRequest struct {
 	Region          string    `json:"region"`
 	Postcode        string    `json:"postcode" binding:"required"`
 	Country         string    `json:"country" binding:"required"`
+	DeviceID        string    `json:"device_id"`
+}
+
+// UpdateProfileRequest represents a partial profile update. Fields that are
+// omitted or null are left unchanged; an empty string clears an optional
+// field (address_line2, region) and is rejected for the others. Email and
+// date of birth can't be changed.
+type UpdateProfileRequest struct {
+	FirstName    *string `json:"first_name"`
+	LastName     *string `json:"last_name"`
+	Phone        *string `json:"phone"`
+	AddressLine1 *string `json:"address_line1"`
+	AddressLine2 *string `json:"address_line2"`
+	City         *string `json:"city"`
+	Region       *string `json:"region"`
+	Postcode     *string `json:"postcode"`
+	Country      *string `json:"country"`
+}
+
+// VerifyEmailRequest represents an email verification request, carrying the
+// token from the verification link
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest represents a request to send a new verification token
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
 }
 
 // LoginRequest represents login request
@@ -50,15 +113,35 @@ type LoginRequest struct {
 	Password   string `json:"password" binding:"required"`
 	DeviceID   string `json:"device_id"`
 	DeviceType string `json:"device_type"`
+	RememberMe bool   `json:"remember_me"`
+	BindToken  bool   `json:"bind_token"`
 }
 
 // LoginResponse represents login response
 type LoginResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // Omitted when refresh tokens are disabled
 	ExpiresIn    int    `json:"expires_in"`
 	TokenType    string `json:"token_type"`
 	User         *User  `json:"user"`
+
+	// Access token issue and expiry times (UTC), matching its iat and exp claims
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Refresh token lifetime; longer for persistent (remember me) sessions
+	RefreshExpiresIn int  `json:"refresh_expires_in,omitempty"`
+	Persistent       bool `json:"persistent,omitempty"`
+}
+
+// LoginOptions holds per-login options
+type LoginOptions struct {
+	RememberMe bool // Issue a longer-lived refresh token for a persistent session
+	BindToken  bool // Bind the login's tokens to the client (see WithTokenBinding)
+
+	// Device the login is from, shown in the session listing
+	DeviceID   string
+	DeviceType string
 }
 
 // RefreshTokenRequest represents refresh token request
@@ -66,11 +149,19 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// LogoutRequest represents the optional logout request body
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 // RefreshTokenResponse represents refresh token response
 type RefreshTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	ExpiresIn   int    `json:"expires_in"`
-	TokenType   string `json:"token_type"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"` // Replaces the presented token when rotation is enabled
+	ExpiresIn    int       `json:"expires_in"`
+	TokenType    string    `json:"token_type"`
+	IssuedAt     time.Time `json:"issued_at"`  // Matches the access token's iat claim
+	ExpiresAt    time.Time `json:"expires_at"` // Matches the access token's exp claim
 }
 
 // TokenClaims represents JWT token claims
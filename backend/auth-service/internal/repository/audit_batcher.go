@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrAuditBufferFull is returned when an event cannot be queued because the
+// batch writer has fallen too far behind
+var ErrAuditBufferFull = errors.New("audit event buffer is full")
+
+// ErrAuditWriterClosed is returned for events queued after Close
+var ErrAuditWriterClosed = errors.New("audit batch writer is closed")
+
+// AuditBatchWriter stores several audit events in one round trip
+type AuditBatchWriter interface {
+	AuditRepository
+
+	// CreateBatch records all events, or none of them
+	CreateBatch(ctx context.Context, events []*models.AuditEvent) error
+}
+
+// AuditBatchConfig controls when queued audit events are written
+type AuditBatchConfig struct {
+	BatchSize     int           // Flush once this many events are queued
+	FlushInterval time.Duration // Flush at least this often while events are queued
+	BufferSize    int           // Events queued before Create starts rejecting them
+	MaxAttempts   int           // Attempts per batch before it is dropped
+	RetryBackoff  time.Duration // Delay before the first retry, doubled per attempt
+}
+
+// DefaultAuditBatchConfig returns the batching defaults
+func DefaultAuditBatchConfig() AuditBatchConfig {
+	return AuditBatchConfig{
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		BufferSize:    10000,
+		MaxAttempts:   3,
+		RetryBackoff:  100 * time.Millisecond,
+	}
+}
+
+// BatchedAuditRepository queues audit events in memory and writes them in
+// batches from a background goroutine, so Create never waits on the
+// database. Close must be called on shutdown to flush queued events.
+type BatchedAuditRepository struct {
+	store  AuditBatchWriter
+	config AuditBatchConfig
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	events chan *models.AuditEvent
+	flush  chan chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewBatchedAuditRepository starts a batch writer in front of store. Zero
+// values in config fall back to DefaultAuditBatchConfig.
+func NewBatchedAuditRepository(store AuditBatchWriter, config AuditBatchConfig, logger *logrus.Logger, opts ...Option) *BatchedAuditRepository {
+	defaults := DefaultAuditBatchConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaults.BufferSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryBackoff < 0 {
+		config.RetryBackoff = 0
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	r := &BatchedAuditRepository{
+		store:  store,
+		config: config,
+		logger: logger,
+		clock:  newOptions(opts).clock,
+		events: make(chan *models.AuditEvent, config.BufferSize),
+		flush:  make(chan chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+
+	return r
+}
+
+// Create queues an audit event for the next batch. It returns
+// ErrAuditBufferFull instead of blocking when the queue is full.
+func (r *BatchedAuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	// Defaults are set now so the stored time is when the event happened,
+	// not when its batch was written
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = r.clock.Now().UTC()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return ErrAuditWriterClosed
+	}
+
+	select {
+	case r.events <- event:
+		return nil
+	default:
+		return ErrAuditBufferFull
+	}
+}
+
+// ListByUser returns all stored audit events for a user, newest first.
+// Events still queued are not included.
+func (r *BatchedAuditRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.AuditEvent, error) {
+	return r.store.ListByUser(ctx, userID)
+}
+
+// ListByUserAndTypes returns a page of a user's stored audit events of the
+// given types, newest first. Events still queued are not included.
+func (r *BatchedAuditRepository) ListByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string, limit, offset int) ([]*models.AuditEvent, error) {
+	return r.store.ListByUserAndTypes(ctx, userID, eventTypes, limit, offset)
+}
+
+// CountByUserAndTypes returns how many of a user's stored audit events have
+// one of the given types. Events still queued are not counted.
+func (r *BatchedAuditRepository) CountByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string) (int, error) {
+	return r.store.CountByUserAndTypes(ctx, userID, eventTypes)
+}
+
+// SummarizeLogins returns a user's latest stored successful login and how
+// many stored failed logins followed it. Events still queued are not included.
+func (r *BatchedAuditRepository) SummarizeLogins(ctx context.Context, userID uuid.UUID) (*models.LoginSummary, error) {
+	return r.store.SummarizeLogins(ctx, userID)
+}
+
+// Flush writes all queued events and waits until they are stored or dropped
+func (r *BatchedAuditRepository) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	select {
+	case r.flush <- flushed:
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events, flushes everything queued and stops the
+// writer. It returns early with ctx's error if the flush does not finish in time.
+func (r *BatchedAuditRepository) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run collects queued events and writes them when the batch is full, the
+// flush interval passes, a flush is requested or the writer is closed
+func (r *BatchedAuditRepository) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.AuditEvent, 0, r.config.BatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		r.writeBatch(batch)
+		batch = make([]*models.AuditEvent, 0, r.config.BatchSize)
+	}
+
+	for {
+		select {
+		case event, ok := <-r.events:
+			if !ok {
+				write()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= r.config.BatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case flushed := <-r.flush:
+			for drained := false; !drained; {
+				select {
+				case event, ok := <-r.events:
+					if !ok {
+						drained = true
+						break
+					}
+					batch = append(batch, event)
+					if len(batch) >= r.config.BatchSize {
+						write()
+					}
+				default:
+					drained = true
+				}
+			}
+			write()
+			close(flushed)
+		}
+	}
+}
+
+// writeBatch stores a batch, retrying with backoff. A batch that still fails
+// after MaxAttempts is dropped and logged.
+func (r *BatchedAuditRepository) writeBatch(batch []*models.AuditEvent) {
+	backoff := r.config.RetryBackoff
+
+	var err error
+	for attempt := 1; attempt <= r.config.MaxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = r.store.CreateBatch(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"events":  len(batch),
+			"attempt": attempt,
+		}).Warn("Failed to write audit batch")
+
+		if attempt < r.config.MaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	r.logger.WithError(err).WithField("events", len(batch)).Error("Dropping audit batch after retries")
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuditStore records batches, failing the first failures calls
+type fakeAuditStore struct {
+	mu       sync.Mutex
+	batches  [][]*models.AuditEvent
+	failures int
+	calls    int
+}
+
+func (s *fakeAuditStore) Create(ctx context.Context, event *models.AuditEvent) error {
+	return s.CreateBatch(ctx, []*models.AuditEvent{event})
+}
+
+func (s *fakeAuditStore) CreateBatch(ctx context.Context, events []*models.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("database unavailable")
+	}
+	s.batches = append(s.batches, append([]*models.AuditEvent(nil), events...))
+	return nil
+}
+
+func (s *fakeAuditStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.AuditEvent, error) {
+	return nil, nil
+}
+
+func (s *fakeAuditStore) ListByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string, limit, offset int) ([]*models.AuditEvent, error) {
+	return nil, nil
+}
+
+func (s *fakeAuditStore) CountByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string) (int, error) {
+	return 0, nil
+}
+
+func (s *fakeAuditStore) SummarizeLogins(ctx context.Context, userID uuid.UUID) (*models.LoginSummary, error) {
+	return &models.LoginSummary{}, nil
+}
+
+func (s *fakeAuditStore) batchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, len(s.batches))
+	for i, batch := range s.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func newTestEvent() *models.AuditEvent {
+	return &models.AuditEvent{EventType: models.AuditEventLoginSuccess}
+}
+
+func TestBatchedAuditRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flushes when the batch is full", func(t *testing.T) {
+		store := &fakeAuditStore{}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 3, FlushInterval: time.Hour}, quietLogger())
+		defer repo.Close(ctx)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, repo.Create(ctx, newTestEvent()))
+		}
+
+		assert.Eventually(t, func() bool { return len(store.batchSizes()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, []int{3}, store.batchSizes())
+	})
+
+	t.Run("flushes a partial batch on the interval", func(t *testing.T) {
+		store := &fakeAuditStore{}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 100, FlushInterval: 20 * time.Millisecond}, quietLogger())
+		defer repo.Close(ctx)
+
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+
+		assert.Eventually(t, func() bool { return len(store.batchSizes()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, []int{2}, store.batchSizes())
+	})
+
+	t.Run("flushes queued events on shutdown", func(t *testing.T) {
+		store := &fakeAuditStore{}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 100, FlushInterval: time.Hour}, quietLogger())
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, repo.Create(ctx, newTestEvent()))
+		}
+		assert.Empty(t, store.batchSizes())
+
+		require.NoError(t, repo.Close(ctx))
+		assert.Equal(t, []int{5}, store.batchSizes())
+
+		assert.ErrorIs(t, repo.Create(ctx, newTestEvent()), ErrAuditWriterClosed)
+	})
+
+	t.Run("flush writes queued events immediately", func(t *testing.T) {
+		store := &fakeAuditStore{}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 100, FlushInterval: time.Hour}, quietLogger())
+		defer repo.Close(ctx)
+
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		require.NoError(t, repo.Flush(ctx))
+		assert.Equal(t, []int{1}, store.batchSizes())
+	})
+
+	t.Run("retries a failed batch", func(t *testing.T) {
+		store := &fakeAuditStore{failures: 2}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 2, FlushInterval: time.Hour, MaxAttempts: 3, RetryBackoff: time.Millisecond}, quietLogger())
+
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		require.NoError(t, repo.Close(ctx))
+
+		assert.Equal(t, []int{2}, store.batchSizes())
+		assert.Equal(t, 3, store.calls)
+	})
+
+	t.Run("drops a batch after the last attempt", func(t *testing.T) {
+		store := &fakeAuditStore{failures: 10}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 1, FlushInterval: time.Hour, MaxAttempts: 2, RetryBackoff: time.Millisecond}, quietLogger())
+
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		require.NoError(t, repo.Close(ctx))
+
+		assert.Empty(t, store.batchSizes())
+		assert.Equal(t, 2, store.calls)
+	})
+
+	t.Run("rejects events instead of blocking when the buffer is full", func(t *testing.T) {
+		blocked := make(chan struct{})
+		store := &blockingAuditStore{release: blocked, started: make(chan struct{})}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 1, FlushInterval: time.Hour, BufferSize: 1}, quietLogger())
+
+		// The first event is taken by the writer, which then blocks on the store
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		<-store.started
+
+		require.NoError(t, repo.Create(ctx, newTestEvent()))
+		assert.ErrorIs(t, repo.Create(ctx, newTestEvent()), ErrAuditBufferFull)
+
+		close(blocked)
+		require.NoError(t, repo.Close(ctx))
+	})
+
+	t.Run("sets the event time when queued", func(t *testing.T) {
+		store := &fakeAuditStore{}
+		repo := NewBatchedAuditRepository(store, AuditBatchConfig{BatchSize: 100, FlushInterval: time.Hour}, quietLogger())
+
+		event := newTestEvent()
+		before := time.Now().UTC()
+		require.NoError(t, repo.Create(ctx, event))
+		require.NoError(t, repo.Close(ctx))
+
+		assert.NotEqual(t, uuid.Nil, event.ID)
+		assert.False(t, event.CreatedAt.Before(before))
+	})
+}
+
+// blockingAuditStore blocks every write until release is closed
+type blockingAuditStore struct {
+	fakeAuditStore
+	release chan struct{}
+	started chan struct{}
+	once    sync.Once
+}
+
+func (s *blockingAuditStore) CreateBatch(ctx context.Context, events []*models.AuditEvent) error {
+	s.once.Do(func() { close(s.started) })
+	<-s.release
+	return s.fakeAuditStore.CreateBatch(ctx, events)
+}
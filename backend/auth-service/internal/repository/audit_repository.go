@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// AuditRepository defines the interface for audit event storage
+type AuditRepository interface {
+	// Create records a new audit event
+	Create(ctx context.Context, event *models.AuditEvent) error
+
+	// ListByUser returns all audit events for a user, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.AuditEvent, error)
+
+	// ListByUserAndTypes returns a page of a user's audit events of the given types, newest first
+	ListByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string, limit, offset int) ([]*models.AuditEvent, error)
+
+	// CountByUserAndTypes returns how many of a user's audit events have one of the given types
+	CountByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string) (int, error)
+
+	// SummarizeLogins returns a user's latest successful login and how many
+	// failed logins followed it
+	SummarizeLogins(ctx context.Context, userID uuid.UUID) (*models.LoginSummary, error)
+}
+
+// auditRepository implements AuditRepository
+type auditRepository struct {
+	db      *pgxpool.Pool
+	clock   clock.Clock
+	txRetry TxRetryConfig
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *pgxpool.Pool, opts ...Option) AuditBatchWriter {
+	o := newOptions(opts)
+	return &auditRepository{
+		db:      db,
+		clock:   o.clock,
+		txRetry: o.txRetry,
+	}
+}
+
+const insertAuditEventQuery = `
+	INSERT INTO audit_events (
+		id, user_id, event_type, ip_address, user_agent,
+		country, asn, as_organization, metadata, created_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+	)
+`
+
+// Create records a new audit event
+func (r *auditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	_, err := r.db.Exec(ctx, insertAuditEventQuery, r.auditEventArgs(event)...)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBatch records several audit events in one transaction
+func (r *auditRepository) CreateBatch(ctx context.Context, events []*models.AuditEvent) error {
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		batch.Queue(insertAuditEventQuery, r.auditEventArgs(event)...)
+	}
+
+	return withTxRetry(ctx, r.db, r.txRetry, func(tx pgx.Tx) error {
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to create audit events: %w", err)
+		}
+		return nil
+	})
+}
+
+// auditEventArgs fills in defaults and returns the insert arguments for an event
+func (r *auditRepository) auditEventArgs(event *models.AuditEvent) []any {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = r.clock.Now().UTC()
+	}
+	if event.Metadata == nil {
+		event.Metadata = map[string]string{}
+	}
+
+	return []any{
+		event.ID, event.UserID, event.EventType, event.IPAddress, event.UserAgent,
+		nullIfEmpty(event.Country), int64(event.ASN), nullIfEmpty(event.ASOrganization),
+		event.Metadata, event.CreatedAt,
+	}
+}
+
+// auditEventColumns are the columns scanned by list
+const auditEventColumns = `
+	id, user_id, event_type, COALESCE(ip_address, ''), COALESCE(user_agent, ''),
+	COALESCE(country, ''), COALESCE(asn, 0), COALESCE(as_organization, ''),
+	metadata, created_at
+`
+
+// ListByUser returns all audit events for a user, newest first
+func (r *auditRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.AuditEvent, error) {
+	query := `
+		SELECT ` + auditEventColumns + `
+		FROM audit_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.list(ctx, query, userID)
+}
+
+// ListByUserAndTypes returns a page of a user's audit events of the given types, newest first
+func (r *auditRepository) ListByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string, limit, offset int) ([]*models.AuditEvent, error) {
+	// Walks idx_audit_events_user_id in order, so a page costs limit+offset rows
+	query := `
+		SELECT ` + auditEventColumns + `
+		FROM audit_events
+		WHERE user_id = $1 AND event_type = ANY($2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.list(ctx, query, userID, eventTypes, limit, offset)
+}
+
+// CountByUserAndTypes returns how many of a user's audit events have one of the given types
+func (r *auditRepository) CountByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM audit_events
+		WHERE user_id = $1 AND event_type = ANY($2)
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, userID, eventTypes).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	return count, nil
+}
+
+// SummarizeLogins returns a user's latest successful login and how many
+// failed logins followed it
+func (r *auditRepository) SummarizeLogins(ctx context.Context, userID uuid.UUID) (*models.LoginSummary, error) {
+	// Both halves walk idx_audit_events_user_id: the lateral join stops at the
+	// newest success, and the count only reads the events after it
+	query := `
+		SELECT
+			last_login.created_at, COALESCE(last_login.ip_address, ''), COALESCE(last_login.country, ''),
+			COUNT(e.id) FILTER (WHERE e.event_type = $3)
+		FROM (SELECT 1) AS one
+		LEFT JOIN LATERAL (
+			SELECT created_at, ip_address, country
+			FROM audit_events
+			WHERE user_id = $1 AND event_type = $2
+			ORDER BY created_at DESC
+			LIMIT 1
+		) AS last_login ON true
+		LEFT JOIN audit_events e
+			ON e.user_id = $1 AND e.created_at > COALESCE(last_login.created_at, '-infinity')
+		GROUP BY last_login.created_at, last_login.ip_address, last_login.country
+	`
+
+	var lastLoginAt *time.Time
+	var ipAddress, country string
+	summary := &models.LoginSummary{}
+	err := r.db.QueryRow(ctx, query, userID, models.AuditEventLoginSuccess, models.AuditEventLoginFailure).
+		Scan(&lastLoginAt, &ipAddress, &country, &summary.FailedSinceLastLogin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize logins: %w", err)
+	}
+
+	if lastLoginAt != nil {
+		summary.LastLogin = &models.LoginRecord{At: *lastLoginAt, IPAddress: ipAddress, Country: country}
+	}
+
+	return summary, nil
+}
+
+// list runs a query selecting auditEventColumns and scans the events
+func (r *auditRepository) list(ctx context.Context, query string, args ...any) ([]*models.AuditEvent, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.AuditEvent{}
+	for rows.Next() {
+		event := &models.AuditEvent{}
+		var asn int64
+		if err := rows.Scan(
+			&event.ID, &event.UserID, &event.EventType, &event.IPAddress, &event.UserAgent,
+			&event.Country, &asn, &event.ASOrganization,
+			&event.Metadata, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		event.ASN = uint(asn)
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	return events, nil
+}
+
+// nullIfEmpty converts an empty string to a SQL NULL
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
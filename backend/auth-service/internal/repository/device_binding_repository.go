@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// DeviceBindingRepository defines the interface for device-to-user bindings
+type DeviceBindingRepository interface {
+	// GetByDeviceID retrieves the binding for a device ID
+	GetByDeviceID(ctx context.Context, deviceID string) (*models.DeviceBinding, error)
+
+	// Bind binds an unbound device ID to userID and returns the device's
+	// binding, which belongs to another user if the device was already bound
+	Bind(ctx context.Context, deviceID string, userID uuid.UUID) (*models.DeviceBinding, error)
+}
+
+// deviceBindingRepository implements DeviceBindingRepository
+type deviceBindingRepository struct {
+	db    *pgxpool.Pool
+	clock clock.Clock
+}
+
+// NewDeviceBindingRepository creates a new device binding repository
+func NewDeviceBindingRepository(db *pgxpool.Pool, opts ...Option) DeviceBindingRepository {
+	return &deviceBindingRepository{
+		db:    db,
+		clock: newOptions(opts).clock,
+	}
+}
+
+// GetByDeviceID retrieves the binding for a device ID
+func (r *deviceBindingRepository) GetByDeviceID(ctx context.Context, deviceID string) (*models.DeviceBinding, error) {
+	query := `
+		SELECT device_id, user_id, created_at
+		FROM device_bindings
+		WHERE device_id = $1
+	`
+
+	binding := &models.DeviceBinding{}
+	err := r.db.QueryRow(ctx, query, deviceID).Scan(&binding.DeviceID, &binding.UserID, &binding.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, appErrors.NewNotFound("device binding not found")
+		}
+		return nil, fmt.Errorf("failed to get device binding: %w", err)
+	}
+
+	return binding, nil
+}
+
+// Bind binds an unbound device ID to userID. The insert and the read of the
+// existing binding are one statement, so concurrent first binds of the same
+// device can't both succeed; the loser gets an error rather than a binding.
+func (r *deviceBindingRepository) Bind(ctx context.Context, deviceID string, userID uuid.UUID) (*models.DeviceBinding, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO device_bindings (device_id, user_id, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (device_id) DO NOTHING
+			RETURNING device_id, user_id, created_at
+		)
+		SELECT device_id, user_id, created_at FROM inserted
+		UNION ALL
+		SELECT device_id, user_id, created_at FROM device_bindings WHERE device_id = $1
+		LIMIT 1
+	`
+
+	binding := &models.DeviceBinding{}
+	err := r.db.QueryRow(ctx, query, deviceID, userID, r.clock.Now().UTC()).Scan(
+		&binding.DeviceID, &binding.UserID, &binding.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind device: %w", err)
+	}
+
+	return binding, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// EmailVerificationRepository defines the interface for email verification tokens
+type EmailVerificationRepository interface {
+	// Replace stores a user's verification token, replacing any earlier one
+	Replace(ctx context.Context, verification *models.EmailVerification) error
+
+	// Consume deletes and returns the verification with the given token hash
+	Consume(ctx context.Context, tokenHash string) (*models.EmailVerification, error)
+}
+
+// emailVerificationRepository implements EmailVerificationRepository
+type emailVerificationRepository struct {
+	db    *pgxpool.Pool
+	clock clock.Clock
+}
+
+// NewEmailVerificationRepository creates a new email verification repository
+func NewEmailVerificationRepository(db *pgxpool.Pool, opts ...Option) EmailVerificationRepository {
+	return &emailVerificationRepository{
+		db:    db,
+		clock: newOptions(opts).clock,
+	}
+}
+
+// Replace stores a user's verification token. A user has one outstanding
+// token, so resending invalidates the link that was sent before.
+func (r *emailVerificationRepository) Replace(ctx context.Context, verification *models.EmailVerification) error {
+	query := `
+		INSERT INTO email_verifications (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+
+	verification.CreatedAt = r.clock.Now().UTC()
+
+	_, err := r.db.Exec(ctx, query,
+		verification.UserID, verification.TokenHash, verification.ExpiresAt, verification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store email verification: %w", err)
+	}
+
+	return nil
+}
+
+// Consume deletes and returns the verification with the given token hash.
+// Deleting on read means a token can be used once, even by concurrent requests.
+func (r *emailVerificationRepository) Consume(ctx context.Context, tokenHash string) (*models.EmailVerification, error) {
+	query := `
+		DELETE FROM email_verifications
+		WHERE token_hash = $1
+		RETURNING user_id, token_hash, expires_at, created_at
+	`
+
+	verification := &models.EmailVerification{}
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&verification.UserID, &verification.TokenHash, &verification.ExpiresAt, &verification.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, appErrors.NewNotFound("email verification not found")
+		}
+		return nil, fmt.Errorf("failed to consume email verification: %w", err)
+	}
+
+	return verification, nil
+}
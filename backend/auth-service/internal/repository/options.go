@@ -0,0 +1,39 @@
+package repository
+
+import "github.com/protobankbankc/auth-service/internal/clock"
+
+// Option configures optional repository behaviour
+type Option func(*options)
+
+// options holds the settings shared by the repositories
+type options struct {
+	clock   clock.Clock
+	txRetry TxRetryConfig
+}
+
+// WithClock sets the clock used for created_at and updated_at timestamps
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithTxRetry sets how transactional methods retry serialization failures
+// and deadlocks. MaxAttempts of 0 keeps the default.
+func WithTxRetry(config TxRetryConfig) Option {
+	return func(o *options) {
+		if config.MaxAttempts <= 0 {
+			config.MaxAttempts = DefaultTxRetryConfig().MaxAttempts
+		}
+		o.txRetry = config
+	}
+}
+
+// newOptions applies opts over the defaults
+func newOptions(opts []Option) options {
+	o := options{clock: clock.System, txRetry: DefaultTxRetryConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
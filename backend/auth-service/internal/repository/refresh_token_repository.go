@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// ErrRefreshTokenRotated is returned by Rotate when the token was already
+// rotated, e.g. by a concurrent refresh with the same token
+var ErrRefreshTokenRotated = errors.New("refresh token already rotated")
+
+// ErrRefreshTokenRevoked is returned by Rotate when the token was revoked,
+// e.g. by logout or reuse detection
+var ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+
+// RefreshTokenRepository defines the interface for issued refresh token storage
+type RefreshTokenRepository interface {
+	// Create stores a newly issued refresh token
+	Create(ctx context.Context, token *models.RefreshTokenRecord) error
+
+	// GetByHash retrieves a refresh token by the hash of its value
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshTokenRecord, error)
+
+	// Rotate marks the token with the given ID rotated and stores its
+	// replacement, returning ErrRefreshTokenRevoked if it was revoked or
+	// ErrRefreshTokenRotated if it was already rotated
+	Rotate(ctx context.Context, id uuid.UUID, replacement *models.RefreshTokenRecord) error
+
+	// RevokeFamily revokes every token in a family that isn't already revoked
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+// refreshTokenRepository implements RefreshTokenRepository
+type refreshTokenRepository struct {
+	db      *pgxpool.Pool
+	clock   clock.Clock
+	txRetry TxRetryConfig
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *pgxpool.Pool, opts ...Option) RefreshTokenRepository {
+	o := newOptions(opts)
+	return &refreshTokenRepository{
+		db:      db,
+		clock:   o.clock,
+		txRetry: o.txRetry,
+	}
+}
+
+const insertRefreshTokenQuery = `
+	INSERT INTO refresh_tokens (
+		id, user_id, family_id, token_hash, expires_at, created_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6
+	)
+`
+
+// Create stores a newly issued refresh token
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshTokenRecord) error {
+	_, err := r.db.Exec(ctx, insertRefreshTokenQuery, r.refreshTokenArgs(token)...)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByHash retrieves a refresh token by the hash of its value
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshTokenRecord, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	token := &models.RefreshTokenRecord{}
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RotatedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, appErrors.NewNotFound("refresh token not found")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Rotate marks the token rotated and stores its replacement in one
+// transaction. The update only matches a token that is neither rotated nor
+// revoked, so of two concurrent rotations of the same token exactly one wins.
+// When it matches nothing, the token's state says which error to return.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, replacement *models.RefreshTokenRecord) error {
+	query := `
+		UPDATE refresh_tokens
+		SET rotated_at = $2
+		WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+	`
+
+	return withTxRetry(ctx, r.db, r.txRetry, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, id, r.clock.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			var revoked bool
+			err := tx.QueryRow(ctx, `SELECT revoked_at IS NOT NULL FROM refresh_tokens WHERE id = $1`, id).Scan(&revoked)
+			if err != nil && err != pgx.ErrNoRows {
+				return fmt.Errorf("failed to check refresh token: %w", err)
+			}
+			if revoked {
+				return ErrRefreshTokenRevoked
+			}
+			return ErrRefreshTokenRotated
+		}
+
+		if _, err := tx.Exec(ctx, insertRefreshTokenQuery, r.refreshTokenArgs(replacement)...); err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+		return nil
+	})
+}
+
+// RevokeFamily revokes every token in a family that isn't already revoked
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.Exec(ctx, query, familyID, r.clock.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+// refreshTokenArgs returns the insert arguments for a refresh token, defaulting
+// its creation time to now
+func (r *refreshTokenRepository) refreshTokenArgs(token *models.RefreshTokenRecord) []any {
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = r.clock.Now().UTC()
+	}
+
+	return []any{
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	}
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// SessionRepository defines the interface for session storage
+type SessionRepository interface {
+	// Create records a new session
+	Create(ctx context.Context, session *models.Session) error
+
+	// GetByID retrieves a session by ID, including revoked and expired ones
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error)
+
+	// ListActiveByUser returns a user's unrevoked, unexpired sessions, most recently seen first
+	ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+
+	// CountActiveByUser returns how many unrevoked, unexpired sessions a user has
+	CountActiveByUser(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// ListByUser returns all of a user's sessions, including revoked and expired ones, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+
+	// Touch records that a session was used at lastSeen
+	Touch(ctx context.Context, id uuid.UUID, lastSeen time.Time) error
+
+	// Revoke revokes one of a user's active sessions
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+
+	// RevokeAllExcept revokes all of a user's active sessions other than
+	// keepID (uuid.Nil keeps none) and returns how many were revoked
+	RevokeAllExcept(ctx context.Context, userID, keepID uuid.UUID) (int, error)
+}
+
+// sessionRepository implements SessionRepository
+type sessionRepository struct {
+	db    *pgxpool.Pool
+	clock clock.Clock
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *pgxpool.Pool, opts ...Option) SessionRepository {
+	return &sessionRepository{
+		db:    db,
+		clock: newOptions(opts).clock,
+	}
+}
+
+const sessionColumns = `
+	id, user_id, COALESCE(device_id, ''), COALESCE(device_type, ''),
+	COALESCE(ip_address, ''), COALESCE(user_agent, ''),
+	created_at, last_seen_at, expires_at, revoked_at
+`
+
+// Create records a new session
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (
+			id, user_id, device_id, device_type, ip_address, user_agent,
+			created_at, last_seen_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = r.clock.Now().UTC()
+	}
+	if session.LastSeenAt.IsZero() {
+		session.LastSeenAt = session.CreatedAt
+	}
+
+	_, err := r.db.Exec(ctx, query,
+		session.ID, session.UserID,
+		nullIfEmpty(session.DeviceID), nullIfEmpty(session.DeviceType),
+		nullIfEmpty(session.IPAddress), nullIfEmpty(session.UserAgent),
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a session by ID, including revoked and expired ones
+func (r *sessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+
+	session, err := scanSession(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, appErrors.NewNotFound("session not found")
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListActiveByUser returns a user's unrevoked, unexpired sessions, most recently seen first
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC
+	`
+
+	return r.list(ctx, query, userID, r.clock.Now().UTC())
+}
+
+// CountActiveByUser returns how many unrevoked, unexpired sessions a user has
+func (r *sessionRepository) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, userID, r.clock.Now().UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListByUser returns all of a user's sessions, including revoked and expired ones, newest first
+func (r *sessionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.list(ctx, query, userID)
+}
+
+// list runs a query selecting sessionColumns and scans the sessions
+func (r *sessionRepository) list(ctx context.Context, query string, args ...any) ([]*models.Session, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Touch records that a session was used at lastSeen
+func (r *sessionRepository) Touch(ctx context.Context, id uuid.UUID, lastSeen time.Time) error {
+	query := `UPDATE sessions SET last_seen_at = $2 WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id, lastSeen); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke revokes one of a user's active sessions
+func (r *sessionRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		UPDATE sessions
+		SET revoked_at = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, id, userID, r.clock.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return appErrors.NewNotFound("session not found")
+	}
+
+	return nil
+}
+
+// RevokeAllExcept revokes all of a user's active sessions other than keepID
+// (uuid.Nil keeps none) and returns how many were revoked
+func (r *sessionRepository) RevokeAllExcept(ctx context.Context, userID, keepID uuid.UUID) (int, error) {
+	query := `
+		UPDATE sessions
+		SET revoked_at = $3
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, keepID, r.clock.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// scanSession scans a row selected with sessionColumns
+func scanSession(row pgx.Row) (*models.Session, error) {
+	session := &models.Session{}
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.DeviceID, &session.DeviceType,
+		&session.IPAddress, &session.UserAgent,
+		&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
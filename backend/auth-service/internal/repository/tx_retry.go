@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// Postgres error codes for transactions that may succeed when retried
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// TxRetryConfig controls retries of transactions that fail with a
+// serialization failure or deadlock under concurrency
+type TxRetryConfig struct {
+	MaxAttempts int           // Attempts including the first; 1 disables retries
+	Backoff     time.Duration // Delay before the first retry, doubled per attempt
+}
+
+// DefaultTxRetryConfig returns the retry settings used unless overridden
+func DefaultTxRetryConfig() TxRetryConfig {
+	return TxRetryConfig{
+		MaxAttempts: 3,
+		Backoff:     20 * time.Millisecond,
+	}
+}
+
+// txBeginner starts transactions; *pgxpool.Pool implements it
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// withTxRetry runs fn in a transaction and commits it if fn succeeds. When
+// fn or the commit fails with a serialization failure or deadlock, the
+// transaction is rolled back and run again from the start, with backoff,
+// up to config.MaxAttempts times. If it still fails that way the caller gets
+// a 503 so clients retry later; any other error is returned as is.
+func withTxRetry(ctx context.Context, db txBeginner, config TxRetryConfig, fn func(tx pgx.Tx) error) error {
+	backoff := config.Backoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, db, fn)
+		if err == nil || !isRetryableTxError(err) {
+			return err
+		}
+
+		if attempt >= config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	appErr := appErrors.NewServiceUnavailable("database is busy, please try again")
+	appErr.Internal = err
+	return appErr
+}
+
+// runTx runs fn in a single transaction, committing if it succeeds
+func runTx(ctx context.Context, db txBeginner, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// isRetryableTxError reports whether err is a serialization failure or
+// deadlock, after which the whole transaction can be retried
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTx records whether it was committed or rolled back; other pgx.Tx
+// methods are unimplemented
+type fakeTx struct {
+	pgx.Tx
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	if tx.commitErr != nil {
+		return tx.commitErr
+	}
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
+
+// fakeBeginner hands out fake transactions, failing the commit of the first
+// commitFailures with commitErr
+type fakeBeginner struct {
+	txs            []*fakeTx
+	commitFailures int
+	commitErr      error
+}
+
+func (b *fakeBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	if len(b.txs) < b.commitFailures {
+		tx.commitErr = b.commitErr
+	}
+	b.txs = append(b.txs, tx)
+	return tx, nil
+}
+
+// TestWithTxRetry tests transactions are retried on serialization failures
+// and deadlocks, and only on those
+func TestWithTxRetry(t *testing.T) {
+	ctx := context.Background()
+	config := TxRetryConfig{MaxAttempts: 3, Backoff: time.Millisecond}
+	serializationFailure := &pgconn.PgError{Code: pgSerializationFailure}
+
+	t.Run("serialization failure succeeds on retry", func(t *testing.T) {
+		db := &fakeBeginner{}
+		calls := 0
+		err := withTxRetry(ctx, db, config, func(tx pgx.Tx) error {
+			calls++
+			if calls == 1 {
+				return fmt.Errorf("failed to rotate refresh token: %w", serializationFailure)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, calls)
+		require.Len(t, db.txs, 2)
+		assert.True(t, db.txs[0].rolledBack)
+		assert.True(t, db.txs[1].committed)
+	})
+
+	t.Run("deadlock at commit is retried", func(t *testing.T) {
+		db := &fakeBeginner{commitFailures: 1, commitErr: &pgconn.PgError{Code: pgDeadlockDetected}}
+		err := withTxRetry(ctx, db, config, func(tx pgx.Tx) error { return nil })
+		require.NoError(t, err)
+
+		require.Len(t, db.txs, 2)
+		assert.True(t, db.txs[1].committed)
+	})
+
+	t.Run("repeated failures return 503", func(t *testing.T) {
+		db := &fakeBeginner{}
+		err := withTxRetry(ctx, db, config, func(tx pgx.Tx) error { return serializationFailure })
+
+		var appErr *appErrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusServiceUnavailable, appErr.StatusCode)
+		assert.ErrorIs(t, appErr.Internal, serializationFailure)
+		assert.Len(t, db.txs, config.MaxAttempts)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		db := &fakeBeginner{}
+		err := withTxRetry(ctx, db, config, func(tx pgx.Tx) error { return ErrRefreshTokenRotated })
+
+		assert.ErrorIs(t, err, ErrRefreshTokenRotated)
+		assert.Len(t, db.txs, 1)
+	})
+
+	t.Run("cancelled context stops retrying", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		db := &fakeBeginner{}
+		err := withTxRetry(cancelled, db, TxRetryConfig{MaxAttempts: 3, Backoff: time.Hour}, func(tx pgx.Tx) error { return serializationFailure })
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, db.txs, 1)
+	})
+
+	t.Run("zero attempts keeps the default", func(t *testing.T) {
+		o := newOptions([]Option{WithTxRetry(TxRetryConfig{Backoff: time.Millisecond})})
+		assert.Equal(t, DefaultTxRetryConfig().MaxAttempts, o.txRetry.MaxAttempts)
+		assert.Equal(t, time.Millisecond, o.txRetry.Backoff)
+	})
+}
@@ -2,14 +2,17 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/clock"
 	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
 	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
 )
 
@@ -24,10 +27,17 @@ type UserRepository interface {
 	// GetByEmail retrieves a user by email
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 
+	// GetActiveByID retrieves a user by ID only if their account is active
+	GetActiveByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+
+	// GetActiveByEmail retrieves a user by email only if their account is active
+	GetActiveByEmail(ctx context.Context, email string) (*models.User, error)
+
 	// GetByPhone retrieves a user by phone
 	GetByPhone(ctx context.Context, phone string) (*models.User, error)
 
-	// Update updates an existing user
+	// Update writes a user's profile fields, leaving email, date of birth,
+	// KYC status, status and created_at untouched
 	Update(ctx context.Context, user *models.User) error
 
 	// Delete deletes a user by ID
@@ -36,19 +46,42 @@ type UserRepository interface {
 	// UpdateKYCStatus updates the KYC status for a user
 	UpdateKYCStatus(ctx context.Context, id uuid.UUID, status string, verifiedAt *time.Time) error
 
-	// SetInactive sets a user as inactive
+	// UpdatePasswordHash replaces the stored password hash for a user
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error
+
+	// MarkEmailVerified records that the user proved they own their email
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+
+	// SetInactive closes a user's account
 	SetInactive(ctx context.Context, id uuid.UUID) error
+
+	// FindByIdentity retrieves users with the same normalized name, date of birth and postcode
+	FindByIdentity(ctx context.Context, firstName, lastName string, dateOfBirth time.Time, postcode string) ([]*models.User, error)
+
+	// CountByKYCStatus returns the number of users in each KYC status
+	CountByKYCStatus(ctx context.Context) (map[string]int, error)
+
+	// RequestClosure records that the user asked to close their account
+	RequestClosure(ctx context.Context, id uuid.UUID, requestedAt time.Time) error
+
+	// CancelClosure withdraws a pending closure request
+	CancelClosure(ctx context.Context, id uuid.UUID) error
+
+	// FinalizeClosures anonymizes and closes accounts whose closure was requested before the given time
+	FinalizeClosures(ctx context.Context, requestedBefore time.Time) ([]uuid.UUID, error)
 }
 
 // userRepository implements UserRepository
 type userRepository struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	clock clock.Clock
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *pgxpool.Pool) UserRepository {
+func NewUserRepository(db *pgxpool.Pool, opts ...Option) UserRepository {
 	return &userRepository{
-		db: db,
+		db:    db,
+		clock: newOptions(opts).clock,
 	}
 }
 
@@ -58,29 +91,30 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 		INSERT INTO users (
 			id, email, phone, password_hash, first_name, last_name,
 			date_of_birth, address_line1, address_line2, city, postcode, country,
-			kyc_status, is_active, created_at, updated_at
+			kyc_status, status, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
 		)
 	`
 
-	now := time.Now()
+	now := r.clock.Now()
 	user.ID = uuid.New()
 	user.CreatedAt = now
 	user.UpdatedAt = now
-	user.IsActive = true
+	user.Status = models.UserStatusActive
 	user.KYCStatus = "pending"
+	user.Phone = utils.NormalizePhone(user.Phone)
 
 	_, err := r.db.Exec(ctx, query,
 		user.ID, user.Email, user.Phone, user.PasswordHash,
 		user.FirstName, user.LastName, user.DateOfBirth,
 		user.AddressLine1, user.AddressLine2, user.City, user.Postcode, user.Country,
-		user.KYCStatus, user.IsActive, user.CreatedAt, user.UpdatedAt,
+		user.KYCStatus, user.Status, user.CreatedAt, user.UpdatedAt,
 	)
 
 	if err != nil {
-		if isPgError(err, "23505") { // Unique violation
-			return appErrors.NewConflict("user with this email or phone already exists")
+		if conflict := uniqueViolation(err); conflict != nil {
+			return conflict
 		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -93,7 +127,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	query := `
 		SELECT id, email, phone, password_hash, first_name, last_name,
 			   date_of_birth, address_line1, address_line2, city, postcode, country,
-			   kyc_status, kyc_verified_at, is_active, created_at, updated_at
+			   kyc_status, kyc_verified_at, status, email_verified, closure_requested_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -103,7 +137,8 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		&user.ID, &user.Email, &user.Phone, &user.PasswordHash,
 		&user.FirstName, &user.LastName, &user.DateOfBirth,
 		&user.AddressLine1, &user.AddressLine2, &user.City, &user.Postcode, &user.Country,
-		&user.KYCStatus, &user.KYCVerifiedAt, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.KYCStatus, &user.KYCVerifiedAt, &user.Status, &user.EmailVerified, &user.ClosureRequestedAt,
+		&user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -121,7 +156,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 	query := `
 		SELECT id, email, phone, password_hash, first_name, last_name,
 			   date_of_birth, address_line1, address_line2, city, postcode, country,
-			   kyc_status, kyc_verified_at, is_active, created_at, updated_at
+			   kyc_status, kyc_verified_at, status, email_verified, closure_requested_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -131,7 +166,8 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 		&user.ID, &user.Email, &user.Phone, &user.PasswordHash,
 		&user.FirstName, &user.LastName, &user.DateOfBirth,
 		&user.AddressLine1, &user.AddressLine2, &user.City, &user.Postcode, &user.Country,
-		&user.KYCStatus, &user.KYCVerifiedAt, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.KYCStatus, &user.KYCVerifiedAt, &user.Status, &user.EmailVerified, &user.ClosureRequestedAt,
+		&user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -144,22 +180,76 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 	return user, nil
 }
 
+// GetActiveByID retrieves a user by ID, skipping accounts that are not
+// active. A missing or inactive user returns a not-found error wrapping
+// ErrUserInactive, for callers that don't need to know why the account is
+// unavailable; use GetByID when the status matters.
+func (r *userRepository) GetActiveByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return scanActiveUser(r.db.QueryRow(ctx, activeUserQuery("id"), id), "ID")
+}
+
+// GetActiveByEmail retrieves a user by email, skipping accounts that are not
+// active. See GetActiveByID for the not-found behaviour.
+func (r *userRepository) GetActiveByEmail(ctx context.Context, email string) (*models.User, error) {
+	return scanActiveUser(r.db.QueryRow(ctx, activeUserQuery("email"), email), "email")
+}
+
+// activeUserQuery selects an active user matching column = $1
+func activeUserQuery(column string) string {
+	return `
+		SELECT id, email, phone, password_hash, first_name, last_name,
+			   date_of_birth, address_line1, address_line2, city, postcode, country,
+			   kyc_status, kyc_verified_at, status, email_verified, closure_requested_at, created_at, updated_at
+		FROM users
+		WHERE ` + column + ` = $1 AND status = 'active'
+	`
+}
+
+// scanActiveUser scans a row from activeUserQuery, mapping no rows to an
+// inactive-user not-found error
+func scanActiveUser(row pgx.Row, lookup string) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(
+		&user.ID, &user.Email, &user.Phone, &user.PasswordHash,
+		&user.FirstName, &user.LastName, &user.DateOfBirth,
+		&user.AddressLine1, &user.AddressLine2, &user.City, &user.Postcode, &user.Country,
+		&user.KYCStatus, &user.KYCVerifiedAt, &user.Status, &user.EmailVerified, &user.ClosureRequestedAt,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, appErrors.NewInactiveNotFound("user not found")
+		}
+		return nil, fmt.Errorf("failed to get active user by %s: %w", lookup, err)
+	}
+
+	return user, nil
+}
+
 // GetByPhone retrieves a user by phone
 func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
 	query := `
 		SELECT id, email, phone, password_hash, first_name, last_name,
 			   date_of_birth, address_line1, address_line2, city, postcode, country,
-			   kyc_status, kyc_verified_at, is_active, created_at, updated_at
+			   kyc_status, kyc_verified_at, status, email_verified, closure_requested_at, created_at, updated_at
 		FROM users
 		WHERE phone = $1
 	`
 
+	// Users without a phone share the empty value, so it never identifies one
+	phone = utils.NormalizePhone(phone)
+	if phone == "" {
+		return nil, appErrors.NewNotFound("user not found")
+	}
+
 	user := &models.User{}
 	err := r.db.QueryRow(ctx, query, phone).Scan(
 		&user.ID, &user.Email, &user.Phone, &user.PasswordHash,
 		&user.FirstName, &user.LastName, &user.DateOfBirth,
 		&user.AddressLine1, &user.AddressLine2, &user.City, &user.Postcode, &user.Country,
-		&user.KYCStatus, &user.KYCVerifiedAt, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.KYCStatus, &user.KYCVerifiedAt, &user.Status, &user.EmailVerified, &user.ClosureRequestedAt,
+		&user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -172,25 +262,31 @@ func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*models.
 	return user, nil
 }
 
-// Update updates an existing user
+// Update writes a user's profile fields. Email, date of birth, KYC status,
+// status and created_at are never written here, so concurrent changes to them
+// (e.g. a KYC decision) aren't overwritten by a stale copy of the user.
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users
 		SET first_name = $2, last_name = $3, phone = $4,
 			address_line1 = $5, address_line2 = $6, city = $7,
-			postcode = $8, country = $9, updated_at = $10
+			region = $8, postcode = $9, country = $10, updated_at = $11
 		WHERE id = $1
 	`
 
-	user.UpdatedAt = time.Now()
+	user.UpdatedAt = r.clock.Now()
+	user.Phone = utils.NormalizePhone(user.Phone)
 
 	result, err := r.db.Exec(ctx, query,
 		user.ID, user.FirstName, user.LastName, user.Phone,
 		user.AddressLine1, user.AddressLine2, user.City,
-		user.Postcode, user.Country, user.UpdatedAt,
+		user.Region, user.Postcode, user.Country, user.UpdatedAt,
 	)
 
 	if err != nil {
+		if conflict := uniqueViolation(err); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -225,7 +321,7 @@ func (r *userRepository) UpdateKYCStatus(ctx context.Context, id uuid.UUID, stat
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, id, status, verifiedAt, time.Now())
+	result, err := r.db.Exec(ctx, query, id, status, verifiedAt, r.clock.Now())
 	if err != nil {
 		return fmt.Errorf("failed to update KYC status: %w", err)
 	}
@@ -237,15 +333,55 @@ func (r *userRepository) UpdateKYCStatus(ctx context.Context, id uuid.UUID, stat
 	return nil
 }
 
-// SetInactive sets a user as inactive
+// UpdatePasswordHash replaces the stored password hash for a user
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, id, passwordHash, r.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return appErrors.NewNotFound("user not found")
+	}
+
+	return nil
+}
+
+// MarkEmailVerified records that the user proved they own their email
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET email_verified = true, updated_at = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, id, r.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return appErrors.NewNotFound("user not found")
+	}
+
+	return nil
+}
+
+// SetInactive closes a user's account
 func (r *userRepository) SetInactive(ctx context.Context, id uuid.UUID) error {
 	query := `
 		UPDATE users
-		SET is_active = false, updated_at = $2
+		SET status = 'closed', updated_at = $2
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, id, time.Now())
+	result, err := r.db.Exec(ctx, query, id, r.clock.Now())
 	if err != nil {
 		return fmt.Errorf("failed to set user inactive: %w", err)
 	}
@@ -257,16 +393,192 @@ func (r *userRepository) SetInactive(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// isPgError checks if an error is a PostgreSQL error with a specific code
-func isPgError(err error, code string) bool {
-	if err == nil {
-		return false
+// FindByIdentity retrieves users with the same normalized name, date of birth and postcode.
+// Names are compared case-insensitively and postcodes ignoring case and spaces.
+func (r *userRepository) FindByIdentity(ctx context.Context, firstName, lastName string, dateOfBirth time.Time, postcode string) ([]*models.User, error) {
+	query := `
+		SELECT id, email, phone, password_hash, first_name, last_name,
+			   date_of_birth, address_line1, address_line2, city, postcode, country,
+			   kyc_status, kyc_verified_at, status, email_verified, closure_requested_at, created_at, updated_at
+		FROM users
+		WHERE lower(trim(first_name)) = lower(trim($1))
+		  AND lower(trim(last_name)) = lower(trim($2))
+		  AND date_of_birth = $3
+		  AND upper(replace(postcode, ' ', '')) = upper(replace($4, ' ', ''))
+		LIMIT 10
+	`
+
+	rows, err := r.db.Query(ctx, query, firstName, lastName, dateOfBirth, postcode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by identity: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(
+			&user.ID, &user.Email, &user.Phone, &user.PasswordHash,
+			&user.FirstName, &user.LastName, &user.DateOfBirth,
+			&user.AddressLine1, &user.AddressLine2, &user.City, &user.Postcode, &user.Country,
+			&user.KYCStatus, &user.KYCVerifiedAt, &user.Status, &user.EmailVerified, &user.ClosureRequestedAt,
+			&user.CreatedAt, &user.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find users by identity: %w", err)
+	}
+
+	return users, nil
+}
+
+// CountByKYCStatus returns the number of users in each KYC status. Statuses
+// with no users are omitted. The grouped count is served from
+// idx_users_kyc_status rather than a full table scan.
+func (r *userRepository) CountByKYCStatus(ctx context.Context) (map[string]int, error) {
+	query := `
+		SELECT COALESCE(kyc_status, 'pending'), COUNT(*)
+		FROM users
+		GROUP BY 1
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by KYC status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan KYC status count: %w", err)
+		}
+		counts[status] = int(count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count users by KYC status: %w", err)
+	}
+
+	return counts, nil
+}
+
+// RequestClosure records when the user asked to close their account. An
+// earlier pending request is kept, so asking twice doesn't restart the
+// cooling-off period.
+func (r *userRepository) RequestClosure(ctx context.Context, id uuid.UUID, requestedAt time.Time) error {
+	query := `
+		UPDATE users
+		SET closure_requested_at = COALESCE(closure_requested_at, $2), updated_at = $3
+		WHERE id = $1 AND status <> 'closed'
+	`
+
+	result, err := r.db.Exec(ctx, query, id, requestedAt, r.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to request account closure: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return appErrors.NewNotFound("user not found")
 	}
-	// Check if error message contains the code
-	// This is a simplified check - in production use proper pgx error handling
-	return contains(err.Error(), code)
+
+	return nil
+}
+
+// CancelClosure withdraws a pending closure request. It returns NotFound
+// when there is no pending request to cancel.
+func (r *userRepository) CancelClosure(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET closure_requested_at = NULL, updated_at = $2
+		WHERE id = $1 AND closure_requested_at IS NOT NULL AND status <> 'closed'
+	`
+
+	result, err := r.db.Exec(ctx, query, id, r.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to cancel account closure: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return appErrors.NewNotFound("no pending account closure")
+	}
+
+	return nil
+}
+
+// FinalizeClosures closes every account whose closure was requested before
+// requestedBefore and strips its personal data. The email is replaced with a
+// unique placeholder so the address can be registered again. Each account is
+// updated once, so concurrent runs don't finalize the same account twice.
+func (r *userRepository) FinalizeClosures(ctx context.Context, requestedBefore time.Time) ([]uuid.UUID, error) {
+	query := `
+		UPDATE users
+		SET status = 'closed',
+			email = 'closed-' || id || '@closed.invalid',
+			phone = '',
+			password_hash = '',
+			first_name = '',
+			last_name = '',
+			date_of_birth = DATE '1900-01-01',
+			address_line1 = '',
+			address_line2 = '',
+			city = '',
+			postcode = '',
+			updated_at = $2
+		WHERE closure_requested_at IS NOT NULL
+		  AND closure_requested_at <= $1
+		  AND status <> 'closed'
+		RETURNING id
+	`
+
+	rows, err := r.db.Query(ctx, query, requestedBefore, r.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize account closures: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan closed account: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to finalize account closures: %w", err)
+	}
+
+	return ids, nil
 }
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(substr) == 0 || s[0:len(substr)] == substr || contains(s[1:], substr))
+// Unique constraints on users, as named in database_schema.sql
+const (
+	constraintUserEmail = "users_email_key"
+	constraintUserPhone = "idx_users_phone_unique"
+)
+
+// uniqueViolation maps a unique constraint violation on users to a conflict
+// naming the duplicated field. It returns nil for any other error.
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return nil
+	}
+
+	switch pgErr.ConstraintName {
+	case constraintUserPhone:
+		return appErrors.NewPhoneConflict("user with this phone number already exists")
+	case constraintUserEmail:
+		return appErrors.NewConflict("user with this email already exists")
+	default:
+		return appErrors.NewConflict("user with this email or phone already exists")
+	}
 }
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUniqueViolation tests unique constraint errors map to field-specific conflicts
+func TestUniqueViolation(t *testing.T) {
+	conflictMessage := func(err error) string {
+		var appErr *appErrors.AppError
+		require.True(t, errors.As(err, &appErr))
+		assert.Equal(t, appErrors.CodeUserExists, appErr.Code)
+		return appErr.Message
+	}
+
+	phoneErr := fmt.Errorf("exec: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_phone_unique"})
+	assert.Equal(t, "user with this phone number already exists", conflictMessage(uniqueViolation(phoneErr)))
+
+	emailErr := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+	assert.Equal(t, "user with this email already exists", conflictMessage(uniqueViolation(emailErr)))
+
+	otherErr := &pgconn.PgError{Code: "23505", ConstraintName: "users_pkey"}
+	assert.Equal(t, "user with this email or phone already exists", conflictMessage(uniqueViolation(otherErr)))
+
+	assert.Nil(t, uniqueViolation(&pgconn.PgError{Code: "23503"}))
+	assert.Nil(t, uniqueViolation(errors.New("connection refused")))
+}
+
+// errRow is a pgx.Row whose Scan always fails with err
+type errRow struct{ err error }
+
+func (r errRow) Scan(dest ...any) error { return r.err }
+
+// TestActiveUserLookup tests the active-only getters filter on status and
+// report a missing or inactive user as an inactive not-found
+func TestActiveUserLookup(t *testing.T) {
+	assert.Contains(t, activeUserQuery("id"), "WHERE id = $1 AND status = 'active'")
+	assert.Contains(t, activeUserQuery("email"), "WHERE email = $1 AND status = 'active'")
+
+	user, err := scanActiveUser(errRow{err: pgx.ErrNoRows}, "ID")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, appErrors.ErrUserInactive)
+	appErr := appErrors.GetAppError(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, appErrors.CodeNotFound, appErr.Code)
+
+	user, err = scanActiveUser(errRow{err: errors.New("connection reset")}, "email")
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "failed to get active user by email: connection reset")
+	assert.NotErrorIs(t, err, appErrors.ErrUserInactive)
+}
@@ -0,0 +1,26 @@
+package requestinfo
+
+import "context"
+
+// Info holds metadata about the HTTP request that triggered a service call
+type Info struct {
+	IP        string
+	UserAgent string
+	RequestID string
+	Method    string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request info
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the request info stored in ctx, or an empty Info
+func FromContext(ctx context.Context) Info {
+	if info, ok := ctx.Value(contextKey{}).(Info); ok {
+		return info
+	}
+	return Info{}
+}
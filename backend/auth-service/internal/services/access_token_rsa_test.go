@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccessTokenRSAKey tests signing access tokens with RS256
+func TestAccessTokenRSAKey(t *testing.T) {
+	ctx := context.Background()
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys := &utils.RSAKeyPair{Private: privateKey, Public: &privateKey.PublicKey}
+
+	userID := uuid.New()
+	newUser := func() *models.User {
+		return &models.User{
+			ID:           userID,
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(newUser(), nil)
+	mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAccessTokenRSAKey(keys))
+
+	response, err := service.Login(ctx, "john.doe@example.com", password)
+	require.NoError(t, err)
+
+	t.Run("access tokens verify with the public key", func(t *testing.T) {
+		publicOnly := utils.SigningKey{RSA: &utils.RSAKeyPair{Public: keys.Public}}
+		claims, err := utils.ValidateTokenWithKey(response.AccessToken, publicOnly, 0, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, userID.String(), claims.UserID)
+
+		user, err := service.ValidateAccessToken(ctx, response.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, userID, user.ID)
+	})
+
+	t.Run("HS256 access tokens are rejected", func(t *testing.T) {
+		hmacToken, err := utils.GenerateAccessToken(userID.String(), "john.doe@example.com", 15*time.Minute, jwtSecret)
+		require.NoError(t, err)
+
+		_, err = service.ValidateAccessToken(ctx, hmacToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("refresh tokens stay HS256", func(t *testing.T) {
+		_, err := utils.ValidateToken(response.RefreshToken, jwtSecret)
+		assert.NoError(t, err)
+
+		refreshed, err := service.RefreshToken(ctx, response.RefreshToken)
+		require.NoError(t, err)
+
+		_, err = service.ValidateAccessToken(ctx, refreshed.AccessToken)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// DefaultClosureCoolingOff is how long a closure request waits before the
+// account is closed, giving the user time to change their mind
+const DefaultClosureCoolingOff = 14 * 24 * time.Hour
+
+// RequestAccountClosure schedules the authenticated caller's account for
+// closure after the cooling-off period. The account keeps working until then.
+// Asking again returns the existing schedule rather than restarting it.
+func (s *AuthService) RequestAccountClosure(ctx context.Context, user *models.User) (*models.AccountClosure, error) {
+	if user.ClosureRequestedAt != nil {
+		return s.accountClosure(*user.ClosureRequestedAt), nil
+	}
+
+	requestedAt := s.clock.Now().UTC()
+	if err := s.userRepo.RequestClosure(ctx, user.ID, requestedAt); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("user_id", user.ID).Info("Account closure requested")
+
+	return s.accountClosure(requestedAt), nil
+}
+
+// CancelAccountClosure withdraws the authenticated caller's pending closure request
+func (s *AuthService) CancelAccountClosure(ctx context.Context, user *models.User) error {
+	if user.ClosureRequestedAt == nil {
+		return appErrors.NewNotFound("no pending account closure")
+	}
+
+	if err := s.userRepo.CancelClosure(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.logger.WithField("user_id", user.ID).Info("Account closure cancelled")
+
+	return nil
+}
+
+// FinalizeDueClosures anonymizes and closes every account whose cooling-off
+// period has ended, and returns how many were closed
+func (s *AuthService) FinalizeDueClosures(ctx context.Context) (int, error) {
+	ids, err := s.userRepo.FinalizeClosures(ctx, s.clock.Now().UTC().Add(-s.closureCoolingOff))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		s.logger.WithField("user_id", id).Info("Account closed after cooling-off period")
+	}
+
+	return len(ids), nil
+}
+
+// RunClosureFinalizer finalizes due account closures every interval until
+// ctx is cancelled. Failures are logged and retried on the next run.
+func (s *AuthService) RunClosureFinalizer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.FinalizeDueClosures(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to finalize account closures")
+			}
+		}
+	}
+}
+
+// accountClosure describes a closure requested at requestedAt
+func (s *AuthService) accountClosure(requestedAt time.Time) *models.AccountClosure {
+	return &models.AccountClosure{
+		RequestedAt: requestedAt,
+		ClosesAt:    requestedAt.Add(s.closureCoolingOff),
+	}
+}
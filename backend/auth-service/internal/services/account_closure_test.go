@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountClosure tests scheduling, cancelling and finalizing account closure
+func TestAccountClosure(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	coolingOff := 14 * 24 * time.Hour
+
+	newService := func(repo *MockUserRepository) *AuthService {
+		return NewAuthService(repo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithClosureCoolingOff(coolingOff), WithClock(clock.NewFake(now)))
+	}
+
+	newUser := func() *models.User {
+		return &models.User{
+			ID:     uuid.New(),
+			Email:  "john.doe@example.com",
+			Status: models.UserStatusActive,
+		}
+	}
+
+	t.Run("request schedules closure after the cooling-off period", func(t *testing.T) {
+		user := newUser()
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("RequestClosure", mock.Anything, user.ID, now).Return(nil)
+
+		closure, err := newService(mockRepo).RequestAccountClosure(context.Background(), user)
+		require.NoError(t, err)
+
+		assert.Equal(t, now, closure.RequestedAt)
+		assert.Equal(t, now.Add(coolingOff), closure.ClosesAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repeated request keeps the original schedule", func(t *testing.T) {
+		user := newUser()
+		requestedAt := now.Add(-3 * 24 * time.Hour)
+		user.ClosureRequestedAt = &requestedAt
+
+		mockRepo := new(MockUserRepository)
+
+		closure, err := newService(mockRepo).RequestAccountClosure(context.Background(), user)
+		require.NoError(t, err)
+
+		assert.Equal(t, requestedAt, closure.RequestedAt)
+		assert.Equal(t, requestedAt.Add(coolingOff), closure.ClosesAt)
+		mockRepo.AssertNotCalled(t, "RequestClosure", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("login still works while closure is pending", func(t *testing.T) {
+		user := newUser()
+		requestedAt := now.Add(-time.Hour)
+		user.ClosureRequestedAt = &requestedAt
+		passwordHash, err := utils.HashPassword("SecurePass123!")
+		require.NoError(t, err)
+		user.PasswordHash = passwordHash
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+		response, err := newService(mockRepo).Login(context.Background(), user.Email, "SecurePass123!")
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+	})
+
+	t.Run("cancel withdraws a pending closure", func(t *testing.T) {
+		user := newUser()
+		requestedAt := now.Add(-time.Hour)
+		user.ClosureRequestedAt = &requestedAt
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CancelClosure", mock.Anything, user.ID).Return(nil)
+
+		err := newService(mockRepo).CancelAccountClosure(context.Background(), user)
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("cancel without a pending closure is not found", func(t *testing.T) {
+		user := newUser()
+
+		mockRepo := new(MockUserRepository)
+
+		err := newService(mockRepo).CancelAccountClosure(context.Background(), user)
+		require.Error(t, err)
+
+		appErr := appErrors.GetAppError(err)
+		require.NotNil(t, appErr)
+		assert.Equal(t, appErrors.CodeNotFound, appErr.Code)
+		mockRepo.AssertNotCalled(t, "CancelClosure", mock.Anything, mock.Anything)
+	})
+
+	t.Run("finalize closes accounts requested before the cooling-off period", func(t *testing.T) {
+		closed := []uuid.UUID{uuid.New(), uuid.New()}
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("FinalizeClosures", mock.Anything, now.Add(-coolingOff)).Return(closed, nil)
+
+		count, err := newService(mockRepo).FinalizeDueClosures(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("finalize surfaces repository errors", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("FinalizeClosures", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+		count, err := newService(mockRepo).FinalizeDueClosures(context.Background())
+		require.Error(t, err)
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// checkAccountStatus rejects accounts that are not active, with an error
+// specific to the account's status so clients can tell the user why
+func checkAccountStatus(user *models.User) error {
+	switch user.Status {
+	case models.UserStatusActive:
+		return nil
+	case models.UserStatusPending:
+		return appErrors.NewAccountStatusError(appErrors.CodeAccountPending, "account is inactive: pending verification")
+	case models.UserStatusSuspended:
+		return appErrors.NewAccountStatusError(appErrors.CodeAccountSuspended, "account is inactive: suspended")
+	default:
+		// Closed, or a status this version doesn't know about
+		return appErrors.NewAccountStatusError(appErrors.CodeAccountClosed, "account is inactive: closed")
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountStatus tests that login and token validation reject each non-active status distinctly
+func TestAccountStatus(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	tests := []struct {
+		status      models.UserStatus
+		code        appErrors.ErrorCode
+		errContains string
+	}{
+		{models.UserStatusPending, appErrors.CodeAccountPending, "pending verification"},
+		{models.UserStatusSuspended, appErrors.CodeAccountSuspended, "suspended"},
+		{models.UserStatusClosed, appErrors.CodeAccountClosed, "closed"},
+		{models.UserStatus("archived"), appErrors.CodeAccountClosed, "closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			user := &models.User{
+				ID:           uuid.New(),
+				Email:        "john.doe@example.com",
+				PasswordHash: passwordHash,
+				Status:       tt.status,
+			}
+
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+			mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+			service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+			_, err := service.Login(context.Background(), "john.doe@example.com", password)
+			require.Error(t, err)
+			appErr := appErrors.GetAppError(err)
+			require.NotNil(t, appErr)
+			assert.Equal(t, 403, appErr.StatusCode)
+			assert.Equal(t, tt.code, appErr.Code)
+			assert.Contains(t, appErr.Message, tt.errContains)
+
+			// Tokens issued before the status changed stop working too
+			token, err := utils.GenerateAccessToken(user.ID.String(), user.Email, 15*time.Minute, jwtSecret)
+			require.NoError(t, err)
+			_, err = service.ValidateAccessToken(context.Background(), token)
+			appErr = appErrors.GetAppError(err)
+			require.NotNil(t, appErr)
+			assert.Equal(t, tt.code, appErr.Code)
+		})
+	}
+
+	t.Run("active account logs in", func(t *testing.T) {
+		user := &models.User{
+			ID:           uuid.New(),
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		response, err := service.Login(context.Background(), "john.doe@example.com", password)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+	})
+}
+
+// TestUserIsActiveCompatibility tests that is_active is derived from the status
+func TestUserIsActiveCompatibility(t *testing.T) {
+	active := &models.User{Status: models.UserStatusActive}
+	suspended := &models.User{Status: models.UserStatusSuspended}
+
+	assert.True(t, active.IsActive())
+	assert.False(t, suspended.IsActive())
+
+	body, err := json.Marshal(suspended)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"status":"suspended"`)
+	assert.Contains(t, string(body), `"is_active":false`)
+}
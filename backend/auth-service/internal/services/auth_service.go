@@ -2,17 +2,22 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/mail"
-	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/features"
 	"github.com/protobankbankc/auth-service/internal/models"
 	"github.com/protobankbankc/auth-service/internal/repository"
 	"github.com/protobankbankc/auth-service/internal/utils"
 	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // Common weak passwords to block
@@ -37,8 +42,52 @@ var commonPasswords = map[string]bool{
 type AuthService struct {
 	userRepo             repository.UserRepository
 	jwtSecret            string
+	signingKeys          utils.SigningKeys
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
+	namePolicy           NamePolicy
+	logger               *logrus.Logger
+	auditRepo            repository.AuditRepository
+	geoResolver          GeoResolver
+	tokenBinding         TokenBindingMode
+	duplicateIdentity    DuplicateIdentityMode
+	postcodeCheck        PostcodeCheckMode
+	refreshTokensEnabled bool
+	rememberMeDuration   time.Duration
+
+	registrationCounter      Counter
+	registrationsPerIPPerDay int
+
+	passwordHasher *utils.HashRegistry
+	hashSlots      *hashLimiter
+
+	minimalClaims bool
+
+	passwordPolicy models.PasswordPolicy
+
+	jitter     accessTokenJitter
+	randInt63n func(n int64) int64
+
+	closureCoolingOff time.Duration
+
+	expiryGrace time.Duration
+
+	clock clock.Clock
+
+	revokedTokens RevocationStore
+
+	refreshTokenStore repository.RefreshTokenRepository
+	refreshReuseGrace time.Duration
+
+	sessions           repository.SessionRepository
+	sessionIdleTimeout time.Duration
+
+	deviceBindings repository.DeviceBindingRepository
+
+	emailVerifications       repository.EmailVerificationRepository
+	verificationSender       VerificationSender
+	emailVerificationTTL     time.Duration
+	requireEmailVerification bool
 }
 
 // NewAuthService creates a new auth service
@@ -47,42 +96,80 @@ func NewAuthService(
 	jwtSecret string,
 	accessTokenDuration time.Duration,
 	refreshTokenDuration time.Duration,
+	opts ...Option,
 ) *AuthService {
-	return &AuthService{
+	service := &AuthService{
 		userRepo:             userRepo,
 		jwtSecret:            jwtSecret,
+		signingKeys:          utils.SigningKeys{Access: jwtSecret},
 		accessTokenDuration:  accessTokenDuration,
 		refreshTokenDuration: refreshTokenDuration,
+		namePolicy:           DefaultNamePolicy(),
+		logger:               logrus.StandardLogger(),
+		tokenBinding:         TokenBindingNone,
+		duplicateIdentity:    DuplicateIdentityOff,
+		postcodeCheck:        PostcodeCheckOff,
+		refreshTokensEnabled: true,
+		passwordHasher:       utils.DefaultHashRegistry(),
+		passwordPolicy:       DefaultPasswordPolicy(),
+		randInt63n:           rand.Int63n,
+		closureCoolingOff:    DefaultClosureCoolingOff,
+		refreshReuseGrace:    DefaultRefreshReuseGrace,
+		clock:                clock.System,
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
 }
 
-// Register creates a new user account
+// Register creates a new user account. The outcome is counted in
+// auth_registration_outcomes_total.
 func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest) (*models.User, error) {
-	// Validate required fields
-	if err := s.validateRegistrationRequest(req); err != nil {
-		return nil, err
+	user, outcome, err := s.register(ctx, req)
+	registrationOutcomes.WithLabelValues(outcome).Inc()
+	return user, err
+}
+
+// register creates a new user account and reports which funnel outcome the
+// attempt ended in
+func (s *AuthService) register(ctx context.Context, req *models.RegisterRequest) (*models.User, string, error) {
+	// Validate every field, reporting all failures at once
+	if outcome, err := s.validateRegistrationRequest(req); err != nil {
+		return nil, outcome, err
 	}
 
-	// Validate email format
-	if err := s.validateEmail(req.Email); err != nil {
-		return nil, err
+	// Enforce the daily registrations-per-IP cap before any hashing, so
+	// rejected attempts don't cost a slow hash each
+	if err := s.checkRegistrationLimit(ctx); err != nil {
+		return nil, registrationRateLimited, err
 	}
 
-	// Validate password strength
-	if err := s.validatePassword(req.Password); err != nil {
-		return nil, err
+	// Hash password before the existence check, so registering an existing
+	// email takes as long as a new one and timing doesn't reveal which
+	// emails have accounts
+	passwordHash, err := s.hashPassword(ctx, req.Password)
+	if err != nil {
+		return nil, registrationError, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Check if user already exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil && existingUser != nil {
-		return nil, appErrors.NewConflict("user with this email already exists")
+		return nil, registrationDuplicateEmail, appErrors.NewConflict("user with this email already exists")
 	}
 
-	// Hash password
-	passwordHash, err := utils.HashPassword(req.Password)
-	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+	// Check for an existing account with the same identity
+	duplicates := s.findDuplicateIdentities(ctx, req)
+	if err := s.checkDuplicateIdentity(duplicates); err != nil {
+		return nil, registrationDuplicateIdentity, err
+	}
+
+	// Reject a device already bound to another user
+	if err := s.checkDeviceAvailable(ctx, req.DeviceID); err != nil {
+		return nil, registrationDeviceInUse, err
 	}
 
 	// Create user model
@@ -100,25 +187,40 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest)
 		Region:       req.Region,
 		Postcode:     req.Postcode,
 		Country:      req.Country,
-		IsActive:     true,
+		Status:       models.UserStatusActive,
 		KYCStatus:    "pending",
-		CreatedAt:    time.Now().UTC(),
-		UpdatedAt:    time.Now().UTC(),
+		CreatedAt:    s.clock.Now().UTC(),
+		UpdatedAt:    s.clock.Now().UTC(),
 	}
 
-	// Save user to database
+	// Save user to database. A concurrent registration with the same email
+	// or phone can pass the checks above and insert first; the unique
+	// constraint then rejects this insert as a conflict, returned as-is so
+	// the caller sees the same 409 as a sequential duplicate.
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		if appErrors.IsAppError(err) {
+			return nil, registrationCreateOutcome(err), err
+		}
+		return nil, registrationCreateOutcome(err), fmt.Errorf("failed to create user: %w", err)
 	}
 
+	s.reportDuplicateIdentity(ctx, user, duplicates)
+	s.bindRegisteredDevice(ctx, req.DeviceID, user.ID)
+	s.sendRegistrationVerification(ctx, user)
+
 	// Remove password hash before returning
 	user.PasswordHash = ""
 
-	return user, nil
+	return user, registrationSuccess, nil
 }
 
 // Login authenticates a user and returns tokens
 func (s *AuthService) Login(ctx context.Context, email, password string) (*models.LoginResponse, error) {
+	return s.LoginWithOptions(ctx, email, password, models.LoginOptions{})
+}
+
+// LoginWithOptions authenticates a user with per-login options such as remember me
+func (s *AuthService) LoginWithOptions(ctx context.Context, email, password string, opts models.LoginOptions) (*models.LoginResponse, error) {
 	// Validate inputs
 	if email == "" {
 		return nil, appErrors.NewBadRequest("email is required")
@@ -128,61 +230,125 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 	}
 
 	// Get user by email
-	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	normalizedEmail := strings.ToLower(strings.TrimSpace(email))
+	user, err := s.userRepo.GetByEmail(ctx, normalizedEmail)
 	if err != nil {
+		s.recordLoginAttempt(ctx, normalizedEmail, nil, false, loginFailureUnknownUser)
 		// Don't reveal if user exists or not
-		return nil, appErrors.NewUnauthorized("invalid email or password")
+		return nil, appErrors.NewInvalidCredentials("invalid email or password")
 	}
 
 	// Check if account is active
-	if !user.IsActive {
-		return nil, appErrors.NewForbidden("account is inactive")
+	if err := checkAccountStatus(user); err != nil {
+		s.recordLoginAttempt(ctx, normalizedEmail, &user.ID, false, loginFailureInactive)
+		return nil, err
 	}
 
 	// Verify password
-	if err := utils.ComparePassword(user.PasswordHash, password); err != nil {
-		return nil, appErrors.NewUnauthorized("invalid email or password")
+	if err := s.verifyPassword(ctx, user.PasswordHash, password); err != nil {
+		if appErr := appErrors.GetAppError(err); appErr != nil {
+			return nil, appErr
+		}
+		s.recordLoginAttempt(ctx, normalizedEmail, &user.ID, false, loginFailureInvalidPassword)
+		return nil, appErrors.NewInvalidCredentials("invalid email or password")
 	}
 
-	// Generate tokens
-	accessToken, err := s.generateAccessToken(user.ID.String(), user.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	// Reject unverified emails when verification is required
+	if err := s.checkEmailVerified(user); err != nil {
+		s.recordLoginAttempt(ctx, normalizedEmail, &user.ID, false, loginFailureEmailNotVerified)
+		return nil, err
+	}
+
+	// Bind the device on first use and reject one bound to another user
+	if err := s.bindDevice(ctx, opts.DeviceID, user.ID); err != nil {
+		if appErrors.IsAppError(err) {
+			s.recordLoginAttempt(ctx, normalizedEmail, &user.ID, false, loginFailureDeviceInUse)
+		}
+		return nil, err
+	}
+
+	// Migrate hashes from older schemes or parameters now the plain password is known
+	s.rehashPasswordIfNeeded(ctx, user, password)
+
+	// Generate tokens, bound to the client if the login asks for it
+	issuedAt := s.tokenIssueTime()
+	var binding string
+	if opts.BindToken {
+		binding = s.tokenBindingFor(ctx)
+	}
+
+	// Refresh tokens are omitted when disabled; each one gets a session when tracked
+	var refreshToken, sessionID string
+	refreshDuration, persistent := s.refreshDurationFor(opts)
+	if features.Enabled(ctx, features.RefreshTokens, s.refreshTokensEnabled) {
+		sessionID, err = s.startSession(ctx, user.ID, opts, issuedAt, refreshDuration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start session: %w", err)
+		}
+
+		refreshOpts := utils.AccessTokenOptions{IssuedAt: issuedAt, SessionID: sessionID, Binding: binding}
+		refreshToken, err = utils.GenerateRefreshTokenWithOptions(user.ID.String(), user.Email, refreshOpts, refreshDuration, s.signingKeys.ForType("refresh"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		}
+		if err := s.recordRefreshToken(ctx, user.ID, refreshToken, s.clock.Now().Add(refreshDuration)); err != nil {
+			return nil, err
+		}
 	}
 
-	refreshToken, err := s.generateRefreshToken(user.ID.String(), user.Email)
+	accessToken, accessExpiry, err := s.issueAccessTokenAt(ctx, user.ID.String(), user.Email, issuedAt, sessionID, binding)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
+	s.recordLoginSuccess(ctx, normalizedEmail, &user.ID, persistent)
+
 	// Remove password hash before returning
 	user.PasswordHash = ""
 
-	return &models.LoginResponse{
+	response := &models.LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
-		ExpiresIn:    int(s.accessTokenDuration.Seconds()),
+		ExpiresIn:    int(accessExpiry.Seconds()),
+		IssuedAt:     issuedAt,
+		ExpiresAt:    issuedAt.Add(accessExpiry).Truncate(time.Second),
 		User:         user,
-	}, nil
+	}
+	if refreshToken != "" {
+		response.RefreshExpiresIn = int(refreshDuration.Seconds())
+		response.Persistent = persistent
+	}
+
+	return response, nil
 }
 
-// RefreshToken validates a refresh token and issues a new access token
+// RefreshToken validates a refresh token and issues a new access token. With
+// rotation enabled the presented refresh token is replaced by a new one.
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.RefreshTokenResponse, error) {
+	if !features.Enabled(ctx, features.RefreshTokens, s.refreshTokensEnabled) {
+		return nil, appErrors.NewNotFound("refresh tokens are disabled")
+	}
+
 	// Validate input
 	if refreshToken == "" {
 		return nil, appErrors.NewBadRequest("refresh token is required")
 	}
 
 	// Validate refresh token
-	claims, err := utils.ValidateToken(refreshToken, s.jwtSecret)
+	claims, err := utils.ValidateRefreshToken(refreshToken, s.signingKeys.KeyFor("refresh"), s.clock.Now())
 	if err != nil {
-		return nil, appErrors.NewUnauthorized("invalid or expired refresh token")
+		return nil, tokenValidationError(err, "invalid or expired refresh token")
 	}
 
-	// Verify it's a refresh token
-	if claims.Type != "refresh" {
-		return nil, appErrors.NewUnauthorized("invalid token type")
+	// Reject refresh tokens revoked at logout
+	if s.isTokenRevoked(ctx, claims.ID) {
+		return nil, appErrors.NewUnauthorized("token has been revoked")
+	}
+
+	// A bound login can only be refreshed by the client it's bound to
+	if !s.verifyTokenBinding(ctx, claims.Binding) {
+		return nil, appErrors.NewUnauthorized("token is not valid for this client")
 	}
 
 	// Parse user ID
@@ -198,101 +364,230 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*m
 	}
 
 	// Check if account is active
-	if !user.IsActive {
-		return nil, appErrors.NewForbidden("account is inactive")
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
+	}
+
+	// Reject refresh tokens whose session was revoked or has gone idle
+	if err := s.checkSession(ctx, user.ID, claims.SessionID); err != nil {
+		return nil, err
+	}
+
+	// Replace the presented refresh token, detecting reuse of rotated ones
+	newRefreshToken, err := s.rotateRefreshToken(ctx, user, refreshToken, claims)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate new access token
-	accessToken, err := s.generateAccessToken(user.ID.String(), user.Email)
+	issuedAt := s.tokenIssueTime()
+	accessToken, accessExpiry, err := s.issueAccessTokenAt(ctx, user.ID.String(), user.Email, issuedAt, claims.SessionID, claims.Binding)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	return &models.RefreshTokenResponse{
-		AccessToken: accessToken,
-		TokenType:   "Bearer",
-		ExpiresIn:   int(s.accessTokenDuration.Seconds()),
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessExpiry.Seconds()),
+		IssuedAt:     issuedAt,
+		ExpiresAt:    issuedAt.Add(accessExpiry).Truncate(time.Second),
 	}, nil
 }
 
 // ValidateAccessToken validates an access token and returns the user
 func (s *AuthService) ValidateAccessToken(ctx context.Context, accessToken string) (*models.User, error) {
+	user, _, err := s.ValidateAccessTokenWithClaims(ctx, accessToken)
+	return user, err
+}
+
+// ValidateAccessTokenWithClaims validates an access token and returns the user
+// together with the token's registered claims. Latency is recorded in the
+// auth_token_validation_seconds histogram.
+func (s *AuthService) ValidateAccessTokenWithClaims(ctx context.Context, accessToken string) (*models.User, *utils.RegisteredTokenClaims, error) {
+	start := time.Now()
+	user, claims, err := s.validateAccessToken(ctx, accessToken)
+	observeTokenValidation(start, err)
+	return user, claims, err
+}
+
+// validateAccessToken validates an access token and looks up its user
+func (s *AuthService) validateAccessToken(ctx context.Context, accessToken string) (*models.User, *utils.RegisteredTokenClaims, error) {
 	// Validate input
 	if accessToken == "" {
-		return nil, appErrors.NewBadRequest("access token is required")
+		return nil, nil, appErrors.NewBadRequest("access token is required")
 	}
 
 	// Validate token
-	claims, err := utils.ValidateToken(accessToken, s.jwtSecret)
+	claims, err := utils.ValidateAccessToken(accessToken, s.signingKeys.KeyFor("access"), s.expiryGraceFor(ctx), s.clock.Now())
 	if err != nil {
-		return nil, appErrors.NewUnauthorized("invalid or expired access token")
+		return nil, nil, tokenValidationError(err, "invalid or expired access token")
 	}
 
-	// Verify it's an access token
-	if claims.Type != "access" {
-		return nil, appErrors.NewUnauthorized("invalid token type")
+	// Reject tokens revoked by jti
+	if s.isTokenRevoked(ctx, claims.ID) {
+		return nil, nil, appErrors.NewUnauthorized("token has been revoked")
+	}
+
+	// Verify client binding, if the token is bound
+	if !s.verifyTokenBinding(ctx, claims.Binding) {
+		return nil, nil, appErrors.NewUnauthorized("token is not valid for this client")
 	}
 
 	// Parse user ID
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		return nil, appErrors.NewUnauthorized("invalid user ID in token")
+		return nil, nil, appErrors.NewUnauthorized("invalid user ID in token")
+	}
+
+	// Reject tokens whose session was revoked or has gone idle
+	if err := s.checkSession(ctx, userID, claims.SessionID); err != nil {
+		return nil, nil, err
 	}
 
 	// Get user from database
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, appErrors.NewNotFound("user not found")
+		return nil, nil, appErrors.NewNotFound("user not found")
 	}
 
 	// Check if account is active
-	if !user.IsActive {
-		return nil, appErrors.NewForbidden("account is inactive")
+	if err := checkAccountStatus(user); err != nil {
+		return nil, nil, err
 	}
 
 	// Remove password hash before returning
 	user.PasswordHash = ""
 
-	return user, nil
+	return user, claims, nil
 }
 
-// validateRegistrationRequest validates all required fields
-func (s *AuthService) validateRegistrationRequest(req *models.RegisterRequest) error {
-	if req.Email == "" {
-		return appErrors.NewBadRequest("email is required")
+// tokenValidationError maps a token validation error to a 401, using message
+// for invalid signatures and expired tokens
+func tokenValidationError(err error, message string) error {
+	var typeErr *utils.TokenTypeError
+	switch {
+	case errors.Is(err, utils.ErrMalformedClaims):
+		return appErrors.NewUnauthorized("malformed token claims")
+	case errors.As(err, &typeErr):
+		return appErrors.NewUnauthorized("invalid token type")
+	default:
+		return appErrors.NewUnauthorized(message)
 	}
-	if req.Password == "" {
-		return appErrors.NewBadRequest("password is required")
+}
+
+// validateRegistrationRequest validates every field of the request and
+// normalizes the country. All failures are returned together as a
+// ValidationError, with the funnel outcome of the first check that failed.
+func (s *AuthService) validateRegistrationRequest(req *models.RegisterRequest) (string, error) {
+	validationErr := appErrors.NewValidationError()
+	outcome := ""
+	fail := func(failedOutcome, field string, err error) {
+		addFieldError(validationErr, field, err)
+		if outcome == "" {
+			outcome = failedOutcome
+		}
+	}
+
+	// Validate required fields
+	required := []struct {
+		field   string
+		label   string
+		missing bool
+	}{
+		{"email", "email", req.Email == ""},
+		{"password", "password", req.Password == ""},
+		{"first_name", "first name", req.FirstName == ""},
+		{"last_name", "last name", req.LastName == ""},
+		{"date_of_birth", "date of birth", req.DateOfBirth.IsZero()},
+		{"address_line1", "address line 1", req.AddressLine1 == ""},
+		{"city", "city", req.City == ""},
+		{"postcode", "postcode", req.Postcode == ""},
+		{"country", "country", req.Country == ""},
+	}
+	for _, r := range required {
+		if r.missing {
+			fail(registrationMissingField, r.field, appErrors.NewBadRequest(r.label+" is required"))
+		}
 	}
-	if req.FirstName == "" {
-		return appErrors.NewBadRequest("first name is required")
+
+	// Validate age (must be 18+)
+	if !req.DateOfBirth.IsZero() {
+		if err := validateAge(req.DateOfBirth, s.clock.Now()); err != nil {
+			fail(registrationUnderage, "date_of_birth", err)
+		}
 	}
-	if req.LastName == "" {
-		return appErrors.NewBadRequest("last name is required")
+
+	// Validate name length and characters
+	if err := s.validateNames(req); err != nil {
+		fail(registrationInvalidName, "", err)
 	}
-	if req.DateOfBirth.IsZero() {
-		return appErrors.NewBadRequest("date of birth is required")
+
+	// Validate email format
+	if req.Email != "" {
+		if err := s.validateEmail(req.Email); err != nil {
+			fail(registrationInvalidEmail, "email", err)
+		}
+	}
+
+	// Store the country as its ISO 3166-1 alpha-2 code, then check the
+	// postcode has its format
+	if req.Country != "" {
+		if country, ok := utils.NormalizeCountry(req.Country); ok {
+			req.Country = country
+			if req.Postcode != "" {
+				if err := s.checkPostcodeCountry(req); err != nil {
+					fail(registrationPostcodeMismatch, "postcode", err)
+				}
+			}
+		} else {
+			fail(registrationInvalidCountry, "country", appErrors.NewBadRequest("country must be an ISO 3166-1 alpha-2 code, e.g. GB"))
+		}
 	}
-	if req.AddressLine1 == "" {
-		return appErrors.NewBadRequest("address line 1 is required")
+
+	// Validate password strength
+	if req.Password != "" {
+		if err := s.validatePassword(req.Password); err != nil {
+			fail(registrationWeakPassword, "password", err)
+		}
 	}
-	if req.City == "" {
-		return appErrors.NewBadRequest("city is required")
+
+	if validationErr.HasErrors() {
+		return outcome, validationErr
 	}
-	if req.Postcode == "" {
-		return appErrors.NewBadRequest("postcode is required")
+	return "", nil
+}
+
+// addFieldError records err against field. Field errors from a nested
+// ValidationError keep their own fields, and every rule a password failed
+// is kept in the message.
+func addFieldError(validationErr *appErrors.ValidationError, field string, err error) {
+	validationErr.AddCause(err)
+
+	if nested := appErrors.GetValidationError(err); nested != nil {
+		for nestedField, message := range nested.Fields {
+			validationErr.Add(nestedField, message)
+		}
+		return
 	}
-	if req.Country == "" {
-		return appErrors.NewBadRequest("country is required")
+
+	if policyErr := appErrors.GetPasswordPolicyError(err); policyErr != nil {
+		validationErr.Add(field, strings.Join(policyErr.Violations, "; "))
+		return
 	}
 
-	// Validate age (must be 18+)
-	age := time.Now().Year() - req.DateOfBirth.Year()
+	validationErr.Add(field, err.Error())
+}
+
+// validateAge checks the applicant is at least 18 as of now
+func validateAge(dateOfBirth, now time.Time) error {
+	age := now.Year() - dateOfBirth.Year()
 	if age < 18 {
 		return appErrors.NewBadRequest("you must be at least 18 years old to register")
 	}
 	// More precise age calculation
-	if time.Now().YearDay() < req.DateOfBirth.YearDay() {
+	if now.YearDay() < dateOfBirth.YearDay() {
 		age--
 	}
 	if age < 18 {
@@ -328,55 +623,67 @@ func (s *AuthService) validateEmail(email string) error {
 	return nil
 }
 
-// validatePassword validates password strength
+// validatePassword validates password strength against the password policy,
+// reporting every rule the password fails rather than just the first
 func (s *AuthService) validatePassword(password string) error {
-	if len(password) < 8 {
-		return appErrors.NewBadRequest("password must be at least 8 characters long")
+	policy := s.passwordPolicy
+	policyErr := appErrors.NewPasswordPolicyError()
+
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		policyErr.Add(fmt.Sprintf("password must be at least %d characters long", policy.MinLength))
 	}
 
-	if len(password) > 72 {
-		return appErrors.NewBadRequest("password too long: maximum 72 characters")
+	// Counted in bytes: bcrypt truncates at 72 bytes, and multi-byte
+	// characters would otherwise pass a character count
+	if utils.PasswordTooLong(password, policy.MaxBytes) {
+		policyErr.Add(fmt.Sprintf("password too long: maximum %d bytes (some characters use more than one byte)", policy.MaxBytes))
 	}
 
 	// Check for uppercase letter
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	if !hasUpper {
-		return appErrors.NewBadRequest("password must contain at least one uppercase letter")
+	if policy.RequireUppercase && !strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		policyErr.Add("password must contain at least one uppercase letter")
 	}
 
 	// Check for lowercase letter
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	if !hasLower {
-		return appErrors.NewBadRequest("password must contain at least one lowercase letter")
+	if policy.RequireLowercase && !strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz") {
+		policyErr.Add("password must contain at least one lowercase letter")
 	}
 
 	// Check for number
-	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
-	if !hasNumber {
-		return appErrors.NewBadRequest("password must contain at least one number")
+	if policy.RequireDigit && !strings.ContainsAny(password, "0123456789") {
+		policyErr.Add("password must contain at least one number")
 	}
 
 	// Check for special character
-	hasSpecial := regexp.MustCompile(`[!@#$%^&*()_+\-=\[\]{};':"\\|,.<>\/?]`).MatchString(password)
-	if !hasSpecial {
-		return appErrors.NewBadRequest("password must contain at least one special character")
+	if policy.RequireSpecial && !strings.ContainsAny(password, policy.SpecialCharacters) {
+		policyErr.Add("password must contain at least one special character")
+	}
+
+	// Check for enough distinct characters, e.g. to reject "aaaaaaaa"
+	if policy.MinUniqueChars > 0 && countUniqueRunes(password) < policy.MinUniqueChars {
+		policyErr.Add(fmt.Sprintf("password must contain at least %d different characters", policy.MinUniqueChars))
 	}
 
 	// Check against common passwords
-	lowerPassword := strings.ToLower(password)
-	if commonPasswords[lowerPassword] {
-		return appErrors.NewBadRequest("password is too common, please choose a stronger password")
+	if policy.CommonPasswordCheck && commonPasswords[strings.ToLower(password)] {
+		policyErr.Add("password is too common, please choose a stronger password")
 	}
 
+	if policyErr.HasErrors() {
+		return policyErr
+	}
 	return nil
 }
 
-// generateAccessToken generates a JWT access token
+// generateAccessToken generates a JWT access token for a request with no
+// client, the way issueAccessToken does
 func (s *AuthService) generateAccessToken(userID, email string) (string, error) {
-	return utils.GenerateAccessToken(userID, email, s.accessTokenDuration, s.jwtSecret)
+	token, _, err := s.issueAccessToken(context.Background(), userID, email)
+	return token, err
 }
 
 // generateRefreshToken generates a JWT refresh token
 func (s *AuthService) generateRefreshToken(userID, email string) (string, error) {
-	return utils.GenerateRefreshToken(userID, email, s.refreshTokenDuration, s.jwtSecret)
+	opts := utils.AccessTokenOptions{IssuedAt: s.clock.Now()}
+	return utils.GenerateRefreshTokenWithOptions(userID, email, opts, s.refreshTokenDuration, s.signingKeys.ForType("refresh"))
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,6 +41,22 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*mod
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetActiveByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
 	args := m.Called(ctx, phone)
 	if args.Get(0) == nil {
@@ -63,11 +80,55 @@ func (m *MockUserRepository) UpdateKYCStatus(ctx context.Context, id uuid.UUID,
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) SetInactive(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) FindByIdentity(ctx context.Context, firstName, lastName string, dateOfBirth time.Time, postcode string) ([]*models.User, error) {
+	args := m.Called(ctx, firstName, lastName, dateOfBirth, postcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountByKYCStatus(ctx context.Context) (map[string]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockUserRepository) RequestClosure(ctx context.Context, id uuid.UUID, requestedAt time.Time) error {
+	args := m.Called(ctx, id, requestedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CancelClosure(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FinalizeClosures(ctx context.Context, requestedBefore time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, requestedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
 // TestRegister tests user registration
 func TestRegister(t *testing.T) {
 	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
@@ -220,7 +281,7 @@ func TestRegister(t *testing.T) {
 				assert.Equal(t, tt.request.FirstName, user.FirstName)
 				assert.Equal(t, tt.request.LastName, user.LastName)
 				assert.NotEmpty(t, user.ID)
-				assert.True(t, user.IsActive)
+				assert.True(t, user.IsActive())
 				assert.Equal(t, "pending", user.KYCStatus)
 			}
 
@@ -254,7 +315,7 @@ func TestLogin(t *testing.T) {
 					PasswordHash: "$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LewY5GyYFJ5NQjeFi", // bcrypt hash
 					FirstName:    "John",
 					LastName:     "Doe",
-					IsActive:     true,
+					Status:       models.UserStatusActive,
 					KYCStatus:    "verified",
 				}
 				repo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
@@ -281,7 +342,7 @@ func TestLogin(t *testing.T) {
 					ID:           uuid.New(),
 					Email:        "john.doe@example.com",
 					PasswordHash: "$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LewY5GyYFJ5NQjeFi",
-					IsActive:     true,
+					Status:       models.UserStatusActive,
 				}
 				repo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
 			},
@@ -298,7 +359,7 @@ func TestLogin(t *testing.T) {
 					ID:           uuid.New(),
 					Email:        "inactive@example.com",
 					PasswordHash: "$2a$12$LQv3c1yqBWVHxkd0LHAkCOYz6TtxMQJqhN8/LewY5GyYFJ5NQjeFi",
-					IsActive:     false,
+					Status:       models.UserStatusClosed,
 				}
 				repo.On("GetByEmail", mock.Anything, "inactive@example.com").Return(user, nil)
 			},
@@ -380,7 +441,7 @@ func TestRefreshToken(t *testing.T) {
 				user := &models.User{
 					ID:        uuid.New(),
 					Email:     "john.doe@example.com",
-					IsActive:  true,
+					Status:    models.UserStatusActive,
 					KYCStatus: "verified",
 				}
 				repo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(user, nil)
@@ -420,7 +481,7 @@ func TestRefreshToken(t *testing.T) {
 				user := &models.User{
 					ID:       uuid.New(),
 					Email:    "john.doe@example.com",
-					IsActive: false,
+					Status:   models.UserStatusClosed,
 				}
 				repo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(user, nil)
 			},
@@ -440,14 +501,11 @@ func TestRefreshToken(t *testing.T) {
 
 			// For successful test, generate a real refresh token
 			if !tt.wantErr && tt.name == "successful token refresh" {
-				// We need to get the user ID from the mock
-				var userID uuid.UUID
 				mockRepo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(func(_ context.Context, id uuid.UUID) *models.User {
-					userID = id
 					return &models.User{
 						ID:       id,
 						Email:    "john.doe@example.com",
-						IsActive: true,
+						Status:   models.UserStatusActive,
 					}
 				}, nil)
 
@@ -504,7 +562,7 @@ func TestValidateAccessToken(t *testing.T) {
 				user := &models.User{
 					ID:       uuid.New(),
 					Email:    "john.doe@example.com",
-					IsActive: true,
+					Status:   models.UserStatusActive,
 				}
 				repo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(user, nil)
 			},
@@ -551,7 +609,7 @@ func TestValidateAccessToken(t *testing.T) {
 				user := &models.User{
 					ID:       uuid.New(),
 					Email:    "john.doe@example.com",
-					IsActive: false,
+					Status:   models.UserStatusClosed,
 				}
 				repo.On("GetByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(user, nil)
 			},
@@ -648,6 +706,12 @@ func TestPasswordValidation(t *testing.T) {
 			wantErr:     true,
 			errContains: "common",
 		},
+		{
+			name:        "multi-byte characters over 72 bytes",
+			password:    "Secure1!" + strings.Repeat("é", 33), // 41 characters, 74 bytes
+			wantErr:     true,
+			errContains: "maximum 72 bytes",
+		},
 	}
 
 	for _, tt := range tests {
@@ -664,6 +728,26 @@ func TestPasswordValidation(t *testing.T) {
 	}
 }
 
+// TestMaxPasswordBytes tests a lowered maximum password length
+func TestMaxPasswordBytes(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	mockRepo := new(MockUserRepository)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithMaxPasswordBytes(16))
+	require.NoError(t, service.validatePassword("Secure1!abcdefgh"))
+
+	// 14 characters but 17 bytes
+	err := service.validatePassword("Secure1!" + strings.Repeat("é", 3) + "abc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum 16 bytes")
+
+	// Values above bcrypt's limit are ignored
+	service = NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithMaxPasswordBytes(100))
+	err = service.validatePassword("Secure1!" + strings.Repeat("a", 65))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum 72 bytes")
+}
+
 // TestEmailValidation tests email validation logic
 func TestEmailValidation(t *testing.T) {
 	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterCountryNormalization tests that registration stores the ISO
+// 3166-1 alpha-2 country code and rejects unknown countries
+func TestRegisterCountryNormalization(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+
+	for input, expected := range map[string]string{"UK": "GB", "gb": "GB", "IE": "IE"} {
+		t.Run(input, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(nil, appErrors.NewNotFound("user not found"))
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+				return user.Country == expected
+			})).Return(nil)
+
+			service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+			req := newNameTestRequest("John", "Doe")
+			req.Country = input
+
+			user, err := service.Register(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, expected, user.Country)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("unknown country", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		req := newNameTestRequest("John", "Doe")
+		req.Country = "Atlantis"
+
+		_, err := service.Register(context.Background(), req)
+		require.Error(t, err)
+
+		validationErr := appErrors.GetValidationError(err)
+		require.NotNil(t, validationErr)
+		assert.Contains(t, validationErr.Fields, "country")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
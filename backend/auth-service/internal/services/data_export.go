@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// ExportUserData assembles the personal data held about the authenticated
+// caller for a GDPR subject access request. The password hash is never
+// exported.
+func (s *AuthService) ExportUserData(ctx context.Context, user *models.User) (*models.DataExport, error) {
+	var err error
+	auditEvents := []*models.AuditEvent{}
+	if s.auditRepo != nil {
+		auditEvents, err = s.auditRepo.ListByUser(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audit events: %w", err)
+		}
+	}
+
+	sessions := []*models.Session{}
+	if s.sessions != nil {
+		sessions, err = s.sessions.ListByUser(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sessions: %w", err)
+		}
+	}
+
+	profile := *user
+	profile.PasswordHash = ""
+
+	return &models.DataExport{
+		ExportedAt: s.clock.Now().UTC(),
+		Profile:    &profile,
+		KYC: models.KYCExport{
+			Status:     user.KYCStatus,
+			VerifiedAt: user.KYCVerifiedAt,
+		},
+		AuditEvents: auditEvents,
+		Sessions:    sessions,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportUserData tests assembling a GDPR data export for the caller
+func TestExportUserData(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	verifiedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	newUser := func() *models.User {
+		return &models.User{
+			ID:            uuid.New(),
+			Email:         "john.doe@example.com",
+			PasswordHash:  "$2a$10$abcdefghijklmnopqrstuv",
+			FirstName:     "John",
+			LastName:      "Doe",
+			Status:        models.UserStatusActive,
+			KYCStatus:     "verified",
+			KYCVerifiedAt: &verifiedAt,
+		}
+	}
+
+	t.Run("export contains profile, KYC and audit sections", func(t *testing.T) {
+		user := newUser()
+
+		events := []*models.AuditEvent{
+			{ID: uuid.New(), UserID: &user.ID, EventType: models.AuditEventLoginSuccess, IPAddress: "203.0.113.7"},
+		}
+
+		mockRepo := new(MockUserRepository)
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("ListByUser", mock.Anything, user.ID).Return(events, nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAuditRepository(auditRepo))
+
+		export, err := service.ExportUserData(context.Background(), user)
+		require.NoError(t, err)
+
+		require.NotNil(t, export.Profile)
+		assert.Equal(t, user.ID, export.Profile.ID)
+		assert.Empty(t, export.Profile.PasswordHash)
+		assert.NotEmpty(t, user.PasswordHash, "the caller's user is left unchanged")
+		assert.Equal(t, "verified", export.KYC.Status)
+		assert.Equal(t, &verifiedAt, export.KYC.VerifiedAt)
+		assert.Equal(t, events, export.AuditEvents)
+		assert.False(t, export.ExportedAt.IsZero())
+
+		body, err := json.Marshal(export)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "password")
+		assert.Contains(t, string(body), `"audit_events"`)
+		assert.Contains(t, string(body), `"profile"`)
+	})
+
+	t.Run("export without audit or session repositories has empty sections", func(t *testing.T) {
+		user := newUser()
+
+		mockRepo := new(MockUserRepository)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		export, err := service.ExportUserData(context.Background(), user)
+		require.NoError(t, err)
+		assert.NotNil(t, export.AuditEvents)
+		assert.Empty(t, export.AuditEvents)
+		assert.NotNil(t, export.Sessions)
+		assert.Empty(t, export.Sessions)
+	})
+
+	t.Run("export includes revoked and expired sessions", func(t *testing.T) {
+		user := newUser()
+		now := time.Now().UTC()
+		revokedAt := now.Add(-time.Hour)
+
+		sessions := newMemorySessionRepository()
+		active := &models.Session{ID: uuid.New(), UserID: user.ID, DeviceID: "phone-1", CreatedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)}
+		revoked := &models.Session{ID: uuid.New(), UserID: user.ID, DeviceID: "tablet-1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}
+		other := &models.Session{ID: uuid.New(), UserID: uuid.New(), CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		for _, session := range []*models.Session{active, revoked, other} {
+			require.NoError(t, sessions.Create(context.Background(), session))
+		}
+
+		mockRepo := new(MockUserRepository)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithSessions(sessions))
+
+		export, err := service.ExportUserData(context.Background(), user)
+		require.NoError(t, err)
+		require.Len(t, export.Sessions, 2)
+		assert.Equal(t, active.ID, export.Sessions[0].ID)
+		assert.Equal(t, revoked.ID, export.Sessions[1].ID)
+
+		body, err := json.Marshal(export)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"sessions"`)
+		assert.Contains(t, string(body), `"revoked_at"`)
+	})
+
+	t.Run("audit lookup failure fails the export", func(t *testing.T) {
+		user := newUser()
+
+		mockRepo := new(MockUserRepository)
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("ListByUser", mock.Anything, user.ID).Return(nil, errors.New("connection refused"))
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAuditRepository(auditRepo))
+
+		_, err := service.ExportUserData(context.Background(), user)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// deviceInUseMessage is returned for a device bound to another user
+const deviceInUseMessage = "device is registered to another account"
+
+// checkDeviceAvailable rejects a registration from a device already bound to
+// a user. Without device binding or a device ID every device is available.
+func (s *AuthService) checkDeviceAvailable(ctx context.Context, deviceID string) error {
+	if s.deviceBindings == nil || deviceID == "" {
+		return nil
+	}
+
+	_, err := s.deviceBindings.GetByDeviceID(ctx, deviceID)
+	if err == nil {
+		return appErrors.NewDeviceConflict(deviceInUseMessage)
+	}
+	if appErrors.IsAppError(err) {
+		return nil
+	}
+	return fmt.Errorf("failed to check device binding: %w", err)
+}
+
+// bindDevice binds deviceID to userID on its first use and rejects a device
+// already bound to another user
+func (s *AuthService) bindDevice(ctx context.Context, deviceID string, userID uuid.UUID) error {
+	if s.deviceBindings == nil || deviceID == "" {
+		return nil
+	}
+
+	binding, err := s.deviceBindings.Bind(ctx, deviceID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to bind device: %w", err)
+	}
+	if binding.UserID != userID {
+		return appErrors.NewDeviceConflict(deviceInUseMessage)
+	}
+
+	return nil
+}
+
+// bindRegisteredDevice binds a new user's device after the user is created.
+// The user already exists by then, so a failure (such as another registration
+// binding the device first) is logged rather than failing the registration;
+// the device then stays with its first user.
+func (s *AuthService) bindRegisteredDevice(ctx context.Context, deviceID string, userID uuid.UUID) {
+	if err := s.bindDevice(ctx, deviceID, userID); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id":   userID,
+			"device_id": deviceID,
+		}).Warn("Failed to bind device at registration")
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryDeviceBindingRepository is an in-memory DeviceBindingRepository
+type memoryDeviceBindingRepository struct {
+	mu       sync.Mutex
+	bindings map[string]*models.DeviceBinding
+}
+
+func newMemoryDeviceBindingRepository() *memoryDeviceBindingRepository {
+	return &memoryDeviceBindingRepository{bindings: map[string]*models.DeviceBinding{}}
+}
+
+func (r *memoryDeviceBindingRepository) GetByDeviceID(ctx context.Context, deviceID string) (*models.DeviceBinding, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	binding, ok := r.bindings[deviceID]
+	if !ok {
+		return nil, appErrors.NewNotFound("device binding not found")
+	}
+	copied := *binding
+	return &copied, nil
+}
+
+func (r *memoryDeviceBindingRepository) Bind(ctx context.Context, deviceID string, userID uuid.UUID) (*models.DeviceBinding, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	binding, ok := r.bindings[deviceID]
+	if !ok {
+		binding = &models.DeviceBinding{DeviceID: deviceID, UserID: userID, CreatedAt: time.Now()}
+		r.bindings[deviceID] = binding
+	}
+	copied := *binding
+	return &copied, nil
+}
+
+// TestDeviceBindings tests a device ID is bound to its first user and
+// rejected for anyone else at login and registration
+func TestDeviceBindings(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	alice := &models.User{ID: uuid.New(), Email: "alice@example.com", Status: models.UserStatusActive}
+	bob := &models.User{ID: uuid.New(), Email: "bob@example.com", Status: models.UserStatusActive}
+
+	newService := func(bindings *memoryDeviceBindingRepository) *AuthService {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, alice.Email).Return(alice, nil)
+		mockRepo.On("GetByEmail", mock.Anything, bob.Email).Return(bob, nil)
+		mockRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, appErrors.NewNotFound("user not found"))
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithDeviceBindings(bindings),
+			WithPasswordHashing(utils.NewHashRegistry(utils.NewBcryptScheme(bcrypt.MinCost))))
+	}
+
+	login := func(service *AuthService, user *models.User, deviceID string) error {
+		// Login clears the hash on the returned user
+		user.PasswordHash = passwordHash
+		_, err := service.LoginWithOptions(context.Background(), user.Email, password, models.LoginOptions{DeviceID: deviceID})
+		return err
+	}
+
+	t.Run("the same user can sign in again on a bound device", func(t *testing.T) {
+		bindings := newMemoryDeviceBindingRepository()
+		service := newService(bindings)
+
+		require.NoError(t, login(service, alice, "token-1"))
+		require.NoError(t, login(service, alice, "token-1"))
+
+		binding, err := bindings.GetByDeviceID(context.Background(), "token-1")
+		require.NoError(t, err)
+		assert.Equal(t, alice.ID, binding.UserID)
+	})
+
+	t.Run("another user is rejected on a bound device", func(t *testing.T) {
+		service := newService(newMemoryDeviceBindingRepository())
+
+		require.NoError(t, login(service, alice, "token-1"))
+
+		err := login(service, bob, "token-1")
+		require.Error(t, err)
+		assert.Equal(t, http.StatusConflict, appErrors.GetStatusCode(err))
+		assert.Equal(t, appErrors.CodeDeviceInUse, appErrors.GetAppError(err).Code)
+
+		// Bob can still sign in from his own device
+		assert.NoError(t, login(service, bob, "token-2"))
+	})
+
+	t.Run("logins without a device ID are not bound", func(t *testing.T) {
+		bindings := newMemoryDeviceBindingRepository()
+		service := newService(bindings)
+
+		require.NoError(t, login(service, alice, ""))
+		assert.Empty(t, bindings.bindings)
+	})
+
+	t.Run("registration binds the device", func(t *testing.T) {
+		bindings := newMemoryDeviceBindingRepository()
+		service := newService(bindings)
+
+		req := newTimingTestRequest("new@example.com")
+		req.DeviceID = "token-3"
+		user, err := service.Register(context.Background(), req)
+		require.NoError(t, err)
+
+		binding, err := bindings.GetByDeviceID(context.Background(), "token-3")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, binding.UserID)
+	})
+
+	t.Run("registration is rejected on a bound device", func(t *testing.T) {
+		service := newService(newMemoryDeviceBindingRepository())
+
+		require.NoError(t, login(service, alice, "token-1"))
+
+		req := newTimingTestRequest("new@example.com")
+		req.DeviceID = "token-1"
+		_, err := service.Register(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeDeviceInUse, appErrors.GetAppError(err).Code)
+	})
+}
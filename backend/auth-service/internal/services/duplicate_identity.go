@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DuplicateIdentityMode selects how registrations matching an existing
+// identity (name + date of birth + postcode) are handled
+type DuplicateIdentityMode string
+
+const (
+	// DuplicateIdentityOff skips the check
+	DuplicateIdentityOff DuplicateIdentityMode = "off"
+	// DuplicateIdentityWarn logs likely duplicates
+	DuplicateIdentityWarn DuplicateIdentityMode = "warn"
+	// DuplicateIdentityFlag logs likely duplicates and records an audit
+	// event so they can be reviewed
+	DuplicateIdentityFlag DuplicateIdentityMode = "flag"
+	// DuplicateIdentityBlock rejects likely duplicates with a conflict
+	DuplicateIdentityBlock DuplicateIdentityMode = "block"
+)
+
+// ParseDuplicateIdentityMode parses a duplicate identity mode from configuration
+func ParseDuplicateIdentityMode(value string) (DuplicateIdentityMode, error) {
+	switch mode := DuplicateIdentityMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", DuplicateIdentityOff:
+		return DuplicateIdentityOff, nil
+	case DuplicateIdentityWarn, DuplicateIdentityFlag, DuplicateIdentityBlock:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid duplicate identity mode: %q", value)
+	}
+}
+
+// findDuplicateIdentities returns existing users sharing the registration's
+// identity. Lookup failures are logged and treated as no match so the check
+// never blocks registration on its own.
+func (s *AuthService) findDuplicateIdentities(ctx context.Context, req *models.RegisterRequest) []*models.User {
+	if s.duplicateIdentity == "" || s.duplicateIdentity == DuplicateIdentityOff {
+		return nil
+	}
+
+	matches, err := s.userRepo.FindByIdentity(ctx, req.FirstName, req.LastName, req.DateOfBirth, req.Postcode)
+	if err != nil {
+		s.logger.WithError(err).Warn("Duplicate identity check failed")
+		return nil
+	}
+
+	return matches
+}
+
+// checkDuplicateIdentity rejects the registration in block mode when it
+// matches an existing identity
+func (s *AuthService) checkDuplicateIdentity(matches []*models.User) error {
+	if len(matches) > 0 && s.duplicateIdentity == DuplicateIdentityBlock {
+		return appErrors.NewConflict("an account with these details already exists")
+	}
+	return nil
+}
+
+// reportDuplicateIdentity logs, and in flag mode audits, a newly registered
+// user that matches existing identities
+func (s *AuthService) reportDuplicateIdentity(ctx context.Context, user *models.User, matches []*models.User) {
+	if len(matches) == 0 {
+		return
+	}
+
+	matchIDs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		matchIDs = append(matchIDs, match.ID.String())
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":    user.ID,
+		"matched_id": strings.Join(matchIDs, ","),
+	}).Warn("Registration matches an existing identity")
+
+	if s.duplicateIdentity != DuplicateIdentityFlag || s.auditRepo == nil {
+		return
+	}
+
+	info := requestinfo.FromContext(ctx)
+	userID := user.ID
+	event := &models.AuditEvent{
+		ID:        uuid.New(),
+		UserID:    &userID,
+		EventType: models.AuditEventPossibleDuplicate,
+		IPAddress: info.IP,
+		UserAgent: info.UserAgent,
+		Metadata: map[string]string{
+			"matched_user_ids": strings.Join(matchIDs, ","),
+		},
+		CreatedAt: s.clock.Now().UTC(),
+	}
+
+	if info.RequestID != "" {
+		event.Metadata["request_id"] = info.RequestID
+	}
+
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Error("Failed to record duplicate identity audit event")
+	}
+}
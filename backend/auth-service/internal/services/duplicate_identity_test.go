@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDuplicateIdentityCheck tests detection of registrations matching an existing identity
+func TestDuplicateIdentityCheck(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	existing := &models.User{
+		ID:          uuid.New(),
+		Email:       "john.original@example.com",
+		FirstName:   "John",
+		LastName:    "Doe",
+		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Postcode:    "SW1A 1AA",
+	}
+
+	tests := []struct {
+		name        string
+		mode        DuplicateIdentityMode
+		matches     []*models.User
+		lookupErr   error
+		wantErr     bool
+		wantFlagged bool
+	}{
+		{
+			name:        "exact identity duplicate is flagged",
+			mode:        DuplicateIdentityFlag,
+			matches:     []*models.User{existing},
+			wantFlagged: true,
+		},
+		{
+			name:    "exact identity duplicate is blocked",
+			mode:    DuplicateIdentityBlock,
+			matches: []*models.User{existing},
+			wantErr: true,
+		},
+		{
+			name:    "exact identity duplicate is only logged in warn mode",
+			mode:    DuplicateIdentityWarn,
+			matches: []*models.User{existing},
+		},
+		{
+			name:    "distinct identity passes",
+			mode:    DuplicateIdentityBlock,
+			matches: nil,
+		},
+		{
+			name:      "lookup failure does not block registration",
+			mode:      DuplicateIdentityBlock,
+			lookupErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newNameTestRequest("John", "Doe")
+
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetByEmail", mock.Anything, req.Email).Return(nil, appErrors.NewNotFound("user not found"))
+			if tt.lookupErr != nil {
+				mockRepo.On("FindByIdentity", mock.Anything, "John", "Doe", req.DateOfBirth, "SW1A 1AA").Return(nil, tt.lookupErr)
+			} else {
+				mockRepo.On("FindByIdentity", mock.Anything, "John", "Doe", req.DateOfBirth, "SW1A 1AA").Return(tt.matches, nil)
+			}
+			if !tt.wantErr {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+			}
+
+			var flagged *models.AuditEvent
+			auditRepo := new(MockAuditRepository)
+			if tt.wantFlagged {
+				auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).
+					Run(func(args mock.Arguments) { flagged = args.Get(1).(*models.AuditEvent) }).
+					Return(nil)
+			}
+
+			service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+				WithDuplicateIdentityCheck(tt.mode), WithAuditRepository(auditRepo))
+
+			user, err := service.Register(context.Background(), req)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				appErr := appErrors.GetAppError(err)
+				require.NotNil(t, appErr)
+				assert.Equal(t, 409, appErr.StatusCode)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, user)
+
+			if tt.wantFlagged {
+				require.NotNil(t, flagged)
+				assert.Equal(t, models.AuditEventPossibleDuplicate, flagged.EventType)
+				assert.Equal(t, user.ID, *flagged.UserID)
+				assert.Equal(t, existing.ID.String(), flagged.Metadata["matched_user_ids"])
+			}
+			auditRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDuplicateIdentityCheckDisabled tests that no lookup happens when the check is off
+func TestDuplicateIdentityCheckDisabled(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	req := newNameTestRequest("John", "Doe")
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, req.Email).Return(nil, appErrors.NewNotFound("user not found"))
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+	_, err := service.Register(context.Background(), req)
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "FindByIdentity", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestParseDuplicateIdentityMode tests parsing duplicate identity modes from configuration
+func TestParseDuplicateIdentityMode(t *testing.T) {
+	for value, expected := range map[string]DuplicateIdentityMode{
+		"":      DuplicateIdentityOff,
+		"off":   DuplicateIdentityOff,
+		"WARN":  DuplicateIdentityWarn,
+		"flag":  DuplicateIdentityFlag,
+		"block": DuplicateIdentityBlock,
+	} {
+		mode, err := ParseDuplicateIdentityMode(value)
+		require.NoError(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	_, err := ParseDuplicateIdentityMode("reject")
+	assert.Error(t, err)
+}
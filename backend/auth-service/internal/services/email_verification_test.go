@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryEmailVerificationRepository is an in-memory EmailVerificationRepository
+type memoryEmailVerificationRepository struct {
+	mu            sync.Mutex
+	verifications map[uuid.UUID]*models.EmailVerification
+}
+
+func newMemoryEmailVerificationRepository() *memoryEmailVerificationRepository {
+	return &memoryEmailVerificationRepository{verifications: map[uuid.UUID]*models.EmailVerification{}}
+}
+
+func (r *memoryEmailVerificationRepository) Replace(ctx context.Context, verification *models.EmailVerification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *verification
+	r.verifications[verification.UserID] = &copied
+	return nil
+}
+
+func (r *memoryEmailVerificationRepository) Consume(ctx context.Context, tokenHash string) (*models.EmailVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for userID, verification := range r.verifications {
+		if verification.TokenHash == tokenHash {
+			delete(r.verifications, userID)
+			return verification, nil
+		}
+	}
+	return nil, appErrors.NewNotFound("email verification not found")
+}
+
+// recordingVerificationSender records the last token sent to each user
+type recordingVerificationSender struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]string
+}
+
+func (s *recordingVerificationSender) SendVerification(ctx context.Context, user *models.User, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = map[uuid.UUID]string{}
+	}
+	s.tokens[user.ID] = token
+	return nil
+}
+
+func (s *recordingVerificationSender) tokenFor(userID uuid.UUID) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[userID]
+}
+
+// TestEmailVerification tests issuing, resending and verifying email
+// verification tokens, and requiring verification at login
+func TestEmailVerification(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	unverified := &models.User{ID: uuid.New(), Email: "unverified@example.com", Status: models.UserStatusActive}
+	verified := &models.User{ID: uuid.New(), Email: "verified@example.com", Status: models.UserStatusActive, EmailVerified: true}
+
+	newService := func(required bool) (*AuthService, *MockUserRepository, *recordingVerificationSender, *clock.Fake) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, unverified.Email).Return(unverified, nil)
+		mockRepo.On("GetByEmail", mock.Anything, verified.Email).Return(verified, nil)
+		mockRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, appErrors.NewNotFound("user not found"))
+		mockRepo.On("GetActiveByEmail", mock.Anything, unverified.Email).Return(unverified, nil)
+		mockRepo.On("GetActiveByEmail", mock.Anything, verified.Email).Return(verified, nil)
+		mockRepo.On("GetActiveByEmail", mock.Anything, mock.Anything).Return(nil, appErrors.NewNotFound("user not found"))
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+		mockRepo.On("MarkEmailVerified", mock.Anything, mock.Anything).Return(nil)
+
+		sender := &recordingVerificationSender{}
+		fakeClock := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithEmailVerification(newMemoryEmailVerificationRepository(), sender, time.Hour),
+			WithRequireEmailVerification(required),
+			WithClock(fakeClock),
+			WithPasswordHashing(utils.NewHashRegistry(utils.NewBcryptScheme(bcrypt.MinCost))))
+		return service, mockRepo, sender, fakeClock
+	}
+
+	assertInvalidToken := func(t *testing.T, err error) {
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, appErrors.GetStatusCode(err))
+		assert.Equal(t, "invalid or expired verification token", appErrors.GetAppError(err).Message)
+	}
+
+	t.Run("registration sends a token that verifies the email once", func(t *testing.T) {
+		service, mockRepo, sender, _ := newService(false)
+
+		user, err := service.Register(context.Background(), newTimingTestRequest("new@example.com"))
+		require.NoError(t, err)
+		token := sender.tokenFor(user.ID)
+		require.NotEmpty(t, token)
+
+		require.NoError(t, service.VerifyEmail(context.Background(), token))
+		mockRepo.AssertCalled(t, "MarkEmailVerified", mock.Anything, user.ID)
+
+		assertInvalidToken(t, service.VerifyEmail(context.Background(), token))
+	})
+
+	t.Run("expired tokens are rejected", func(t *testing.T) {
+		service, mockRepo, sender, fakeClock := newService(false)
+
+		require.NoError(t, service.ResendVerification(context.Background(), unverified.Email))
+		fakeClock.Advance(time.Hour)
+
+		assertInvalidToken(t, service.VerifyEmail(context.Background(), sender.tokenFor(unverified.ID)))
+		mockRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("resending replaces the earlier token", func(t *testing.T) {
+		service, _, sender, _ := newService(false)
+
+		require.NoError(t, service.ResendVerification(context.Background(), unverified.Email))
+		first := sender.tokenFor(unverified.ID)
+		require.NoError(t, service.ResendVerification(context.Background(), " Unverified@Example.com "))
+		second := sender.tokenFor(unverified.ID)
+		require.NotEqual(t, first, second)
+
+		assertInvalidToken(t, service.VerifyEmail(context.Background(), first))
+		assert.NoError(t, service.VerifyEmail(context.Background(), second))
+	})
+
+	t.Run("resend sends nothing for unknown or verified emails", func(t *testing.T) {
+		service, _, sender, _ := newService(false)
+
+		assert.NoError(t, service.ResendVerification(context.Background(), "nobody@example.com"))
+		assert.NoError(t, service.ResendVerification(context.Background(), verified.Email))
+		assert.Empty(t, sender.tokens)
+	})
+
+	t.Run("login requires a verified email when required", func(t *testing.T) {
+		service, _, _, _ := newService(true)
+
+		unverified.PasswordHash = passwordHash
+		_, err := service.Login(context.Background(), unverified.Email, password)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, appErrors.GetStatusCode(err))
+		assert.Equal(t, appErrors.CodeEmailNotVerified, appErrors.GetAppError(err).Code)
+
+		verified.PasswordHash = passwordHash
+		_, err = service.Login(context.Background(), verified.Email, password)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unverified users can sign in when not required", func(t *testing.T) {
+		service, _, _, _ := newService(false)
+
+		unverified.PasswordHash = passwordHash
+		_, err := service.Login(context.Background(), unverified.Email, password)
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		err := service.VerifyEmail(context.Background(), "token")
+		assert.Equal(t, http.StatusNotFound, appErrors.GetStatusCode(err))
+		err = service.ResendVerification(context.Background(), unverified.Email)
+		assert.Equal(t, http.StatusNotFound, appErrors.GetStatusCode(err))
+	})
+}
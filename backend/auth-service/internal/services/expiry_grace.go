@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+)
+
+// MaxExpiredTokenGrace caps how long after expiry an access token may still
+// be accepted for read-only requests
+const MaxExpiredTokenGrace = 5 * time.Minute
+
+// expiryGraceFor returns how long past expiry an access token is accepted for
+// the request in ctx. Only read-only requests (GET, HEAD) get the grace
+// period, so a token caught mid-refresh can still load data but never
+// change it.
+func (s *AuthService) expiryGraceFor(ctx context.Context) time.Duration {
+	if s.expiryGrace <= 0 {
+		return 0
+	}
+
+	switch requestinfo.FromContext(ctx).Method {
+	case http.MethodGet, http.MethodHead:
+		return s.expiryGrace
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpiredTokenGrace tests that just-expired access tokens are accepted for
+// read-only requests within the grace period and rejected for mutations
+func TestExpiredTokenGrace(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+
+	expiredToken := expiredAccessToken(t, user, time.Second, jwtSecret)
+
+	withMethod := func(method string) context.Context {
+		return requestinfo.NewContext(context.Background(), requestinfo.Info{Method: method})
+	}
+
+	newService := func(opts ...Option) *AuthService {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, opts...)
+	}
+
+	t.Run("GET within grace is accepted", func(t *testing.T) {
+		service := newService(WithExpiredTokenGrace(30 * time.Second))
+
+		got, err := service.ValidateAccessToken(withMethod(http.MethodGet), expiredToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, got.ID)
+	})
+
+	t.Run("mutation within grace is rejected", func(t *testing.T) {
+		service := newService(WithExpiredTokenGrace(30 * time.Second))
+
+		_, err := service.ValidateAccessToken(withMethod(http.MethodPost), expiredToken)
+		require.Error(t, err)
+
+		appErr := appErrors.GetAppError(err)
+		require.NotNil(t, appErr)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErr.Code)
+	})
+
+	t.Run("without grace the expired token is rejected", func(t *testing.T) {
+		service := newService()
+
+		_, err := service.ValidateAccessToken(withMethod(http.MethodGet), expiredToken)
+		require.Error(t, err)
+	})
+
+	t.Run("token expired longer ago than the grace is rejected", func(t *testing.T) {
+		staleToken := expiredAccessToken(t, user, time.Minute, jwtSecret)
+		service := newService(WithExpiredTokenGrace(30 * time.Second))
+
+		_, err := service.ValidateAccessToken(withMethod(http.MethodGet), staleToken)
+		require.Error(t, err)
+	})
+
+	t.Run("grace is capped", func(t *testing.T) {
+		service := newService(WithExpiredTokenGrace(time.Hour))
+		assert.Equal(t, MaxExpiredTokenGrace, service.expiryGrace)
+	})
+}
+
+// TestAccessTokenExpiryWithFakeClock tests token expiry and the grace period
+// by advancing the service clock instead of sleeping
+func TestAccessTokenExpiryWithFakeClock(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+
+	fakeClock := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+		WithClock(fakeClock), WithExpiredTokenGrace(30*time.Second))
+
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{Method: http.MethodGet})
+	token, _, err := service.issueAccessToken(ctx, user.ID.String(), user.Email)
+	require.NoError(t, err)
+
+	fakeClock.Advance(14 * time.Minute)
+	_, err = service.ValidateAccessToken(ctx, token)
+	require.NoError(t, err, "token should be valid before expiry")
+
+	fakeClock.Advance(70 * time.Second)
+	_, err = service.ValidateAccessToken(ctx, token)
+	require.NoError(t, err, "token should be accepted within the grace period")
+
+	fakeClock.Advance(time.Minute)
+	_, err = service.ValidateAccessToken(ctx, token)
+	require.Error(t, err, "token should be rejected once the grace period has passed")
+}
+
+// expiredAccessToken signs an access token for user that expired expiredFor ago
+func expiredAccessToken(t *testing.T, user *models.User, expiredFor time.Duration, secret string) string {
+	t.Helper()
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":    user.ID.String(),
+		"email":      user.Email,
+		"token_type": "access",
+		"sub":        user.ID.String(),
+		"iat":        now.Add(-15 * time.Minute).Unix(),
+		"exp":        now.Add(-expiredFor).Unix(),
+	})
+
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
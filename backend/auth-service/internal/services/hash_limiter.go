@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// hashLimiter bounds concurrent password hash operations. bcrypt and argon2id
+// are CPU-bound, so a burst of logins or registrations would otherwise starve
+// every other request.
+type hashLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+// newHashLimiter creates a limiter allowing limit concurrent operations,
+// defaulting to GOMAXPROCS
+func newHashLimiter(limit int, queueTimeout time.Duration) *hashLimiter {
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	return &hashLimiter{
+		slots:        make(chan struct{}, limit),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire takes a slot, waiting up to the queue timeout for one to free up.
+// The returned function releases the slot.
+func (l *hashLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if l.queueTimeout <= 0 {
+		return nil, errHashBusy()
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, errHashBusy()
+	case <-ctx.Done():
+		return nil, errHashBusy()
+	}
+}
+
+// errHashBusy is returned when no hash slot frees up in time
+func errHashBusy() error {
+	return appErrors.NewServiceUnavailable("server is busy, please try again shortly")
+}
+
+// hashPassword hashes a password with the configured scheme, within the
+// concurrency limit when one is set
+func (s *AuthService) hashPassword(ctx context.Context, password string) (string, error) {
+	if s.hashSlots != nil {
+		release, err := s.hashSlots.acquire(ctx)
+		if err != nil {
+			return "", err
+		}
+		defer release()
+	}
+	return s.passwordHasher.Hash(password)
+}
+
+// verifyPassword checks a password against a stored hash, within the
+// concurrency limit when one is set
+func (s *AuthService) verifyPassword(ctx context.Context, hash, password string) error {
+	if s.hashSlots != nil {
+		release, err := s.hashSlots.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+	return s.passwordHasher.Verify(hash, password)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowHashScheme is a hash scheme that takes a while and records the peak
+// number of operations running at once
+type slowHashScheme struct {
+	delay    time.Duration
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (s *slowHashScheme) ID() string                   { return "slow" }
+func (s *slowHashScheme) Identifies(hash string) bool  { return strings.HasPrefix(hash, "$slow$") }
+func (s *slowHashScheme) NeedsRehash(hash string) bool { return false }
+
+func (s *slowHashScheme) Hash(password string) (string, error) {
+	s.track()
+	return "$slow$" + password, nil
+}
+
+func (s *slowHashScheme) Verify(hash, password string) error {
+	s.track()
+	return nil
+}
+
+// track marks an operation in flight for the scheme's delay
+func (s *slowHashScheme) track() {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(s.delay)
+}
+
+// TestPasswordHashConcurrency tests that concurrent hash operations are
+// bounded and that operations beyond the cap fail with 503 once they've
+// queued for too long
+func TestPasswordHashConcurrency(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("excess operations queue within the limit", func(t *testing.T) {
+		scheme := &slowHashScheme{delay: 20 * time.Millisecond}
+		service := NewAuthService(new(MockUserRepository), "secret", 15*time.Minute, 7*24*time.Hour,
+			WithPasswordHashing(utils.NewHashRegistry(scheme)),
+			WithPasswordHashConcurrency(2, 5*time.Second))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := service.hashPassword(ctx, "SecurePass123!")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, scheme.peak.Load(), int32(2))
+	})
+
+	t.Run("operations fail fast without a queue timeout", func(t *testing.T) {
+		scheme := &slowHashScheme{delay: 200 * time.Millisecond}
+		service := NewAuthService(new(MockUserRepository), "secret", 15*time.Minute, 7*24*time.Hour,
+			WithPasswordHashing(utils.NewHashRegistry(scheme)),
+			WithPasswordHashConcurrency(1, 0))
+
+		release, err := service.hashSlots.acquire(ctx)
+		require.NoError(t, err)
+		defer release()
+
+		err = service.verifyPassword(ctx, "$slow$x", "x")
+		require.Error(t, err)
+
+		appErr := appErrors.GetAppError(err)
+		require.NotNil(t, appErr)
+		assert.Equal(t, appErrors.CodeServiceUnavailable, appErr.Code)
+	})
+
+	t.Run("limit defaults to GOMAXPROCS", func(t *testing.T) {
+		limiter := newHashLimiter(0, time.Second)
+		assert.Positive(t, cap(limiter.slots))
+	})
+}
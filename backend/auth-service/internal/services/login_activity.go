@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// loginEventTypes are the audit events listed as login activity
+var loginEventTypes = []string{models.AuditEventLoginSuccess, models.AuditEventLoginFailure}
+
+// LoginActivity returns a page of the authenticated caller's successful and
+// failed login attempts from the audit trail, newest first. Without an audit
+// repository the page is empty.
+func (s *AuthService) LoginActivity(ctx context.Context, user *models.User, limit, offset int) (*models.LoginActivityPage, error) {
+	page := &models.LoginActivityPage{
+		Activity: []*models.LoginActivity{},
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if s.auditRepo == nil {
+		return page, nil
+	}
+
+	total, err := s.auditRepo.CountByUserAndTypes(ctx, user.ID, loginEventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+	page.Total = total
+	if offset >= total {
+		return page, nil
+	}
+
+	events, err := s.auditRepo.ListByUserAndTypes(ctx, user.ID, loginEventTypes, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit events: %w", err)
+	}
+
+	for _, event := range events {
+		result := models.LoginResultFailure
+		if event.EventType == models.AuditEventLoginSuccess {
+			result = models.LoginResultSuccess
+		}
+
+		page.Activity = append(page.Activity, &models.LoginActivity{
+			At:        event.CreatedAt,
+			Result:    result,
+			IPAddress: event.IPAddress,
+			UserAgent: event.UserAgent,
+			Country:   event.Country,
+		})
+	}
+	page.HasMore = offset+len(page.Activity) < total
+
+	return page, nil
+}
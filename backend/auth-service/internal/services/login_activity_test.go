@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoginActivity tests listing the caller's login attempts from the audit trail
+func TestLoginActivity(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+
+	events := []*models.AuditEvent{
+		{EventType: models.AuditEventLoginFailure, IPAddress: "198.51.100.1", UserAgent: "curl/8.0", CreatedAt: now},
+		{EventType: models.AuditEventLoginSuccess, IPAddress: "203.0.113.7", UserAgent: "ProtobankBankC/1.0 iOS", Country: "GB", CreatedAt: now.Add(-time.Hour)},
+		{EventType: models.AuditEventLoginFailure, IPAddress: "203.0.113.7", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	newService := func(auditRepo *MockAuditRepository) *AuthService {
+		mockRepo := new(MockUserRepository)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAuditRepository(auditRepo))
+	}
+
+	t.Run("lists successful and failed attempts newest first", func(t *testing.T) {
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("CountByUserAndTypes", mock.Anything, user.ID, loginEventTypes).Return(3, nil)
+		auditRepo.On("ListByUserAndTypes", mock.Anything, user.ID, loginEventTypes, 10, 0).Return(events, nil)
+
+		page, err := newService(auditRepo).LoginActivity(context.Background(), user, 10, 0)
+		require.NoError(t, err)
+
+		require.Len(t, page.Activity, 3)
+		assert.Equal(t, models.LoginResultFailure, page.Activity[0].Result)
+		assert.Equal(t, now, page.Activity[0].At)
+		assert.Equal(t, "curl/8.0", page.Activity[0].UserAgent)
+		assert.Equal(t, models.LoginResultSuccess, page.Activity[1].Result)
+		assert.Equal(t, "GB", page.Activity[1].Country)
+		assert.Equal(t, models.LoginResultFailure, page.Activity[2].Result)
+		assert.Equal(t, now.Add(-2*time.Hour), page.Activity[2].At)
+		assert.Equal(t, 3, page.Total)
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("pages with limit and offset in the repository", func(t *testing.T) {
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("CountByUserAndTypes", mock.Anything, user.ID, loginEventTypes).Return(3, nil)
+		auditRepo.On("ListByUserAndTypes", mock.Anything, user.ID, loginEventTypes, 1, 1).Return(events[1:2], nil)
+
+		page, err := newService(auditRepo).LoginActivity(context.Background(), user, 1, 1)
+		require.NoError(t, err)
+
+		require.Len(t, page.Activity, 1)
+		assert.Equal(t, models.LoginResultSuccess, page.Activity[0].Result)
+		assert.Equal(t, 1, page.Limit)
+		assert.Equal(t, 1, page.Offset)
+		assert.Equal(t, 3, page.Total)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("an offset past the end skips the list query", func(t *testing.T) {
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("CountByUserAndTypes", mock.Anything, user.ID, loginEventTypes).Return(3, nil)
+
+		page, err := newService(auditRepo).LoginActivity(context.Background(), user, 10, 3)
+		require.NoError(t, err)
+
+		assert.Empty(t, page.Activity)
+		assert.Equal(t, 3, page.Total)
+		assert.False(t, page.HasMore)
+		auditRepo.AssertNotCalled(t, "ListByUserAndTypes", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("without an audit repository the page is empty", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		page, err := service.LoginActivity(context.Background(), user, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, page.Activity)
+	})
+
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/sirupsen/logrus"
+)
+
+// Login failure reasons recorded in audit metadata
+const (
+	loginFailureUnknownUser      = "unknown_user"
+	loginFailureInactive         = "inactive"
+	loginFailureInvalidPassword  = "invalid_password"
+	loginFailureDeviceInUse      = "device_in_use"
+	loginFailureEmailNotVerified = "email_not_verified"
+)
+
+// GeoResolver resolves GeoIP data (country, ASN) for a client IP address
+type GeoResolver interface {
+	Lookup(ip string) (*models.GeoLocation, error)
+}
+
+// Session kinds recorded in successful login audit metadata
+const (
+	loginSessionStandard   = "standard"
+	loginSessionPersistent = "persistent"
+)
+
+// recordLoginAttempt writes a login audit event for the request in ctx, enriched
+// with GeoIP data when a resolver is configured. Audit failures are logged and
+// never fail the login itself.
+func (s *AuthService) recordLoginAttempt(ctx context.Context, email string, userID *uuid.UUID, success bool, reason string) {
+	metadata := map[string]string{}
+	if !success {
+		metadata["reason"] = reason
+	}
+	s.recordLoginEvent(ctx, email, userID, success, metadata)
+}
+
+// recordLoginSuccess writes a successful login audit event, noting whether the
+// session is persistent ("remember me")
+func (s *AuthService) recordLoginSuccess(ctx context.Context, email string, userID *uuid.UUID, persistent bool) {
+	session := loginSessionStandard
+	if persistent {
+		session = loginSessionPersistent
+	}
+	s.recordLoginEvent(ctx, email, userID, true, map[string]string{"session": session})
+}
+
+// recordLoginEvent builds and stores a login audit event with the given extra metadata
+func (s *AuthService) recordLoginEvent(ctx context.Context, email string, userID *uuid.UUID, success bool, metadata map[string]string) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	info := requestinfo.FromContext(ctx)
+
+	event := &models.AuditEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		EventType: models.AuditEventLoginFailure,
+		IPAddress: info.IP,
+		UserAgent: info.UserAgent,
+		Metadata:  metadata,
+		CreatedAt: s.clock.Now().UTC(),
+	}
+	// Only attempts on an existing account record the email. For unknown
+	// users it is whatever was submitted, which may be personal data of
+	// someone without an account here, or a mistyped password.
+	if userID != nil {
+		event.Metadata["email"] = email
+	}
+
+	if success {
+		event.EventType = models.AuditEventLoginSuccess
+	}
+
+	if info.RequestID != "" {
+		event.Metadata["request_id"] = info.RequestID
+	}
+
+	s.enrichWithGeo(event)
+
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"event_type": event.EventType,
+			"ip":         event.IPAddress,
+		}).Error("Failed to record login audit event")
+	}
+}
+
+// enrichWithGeo attaches GeoIP data to an audit event; lookups that fail are skipped
+func (s *AuthService) enrichWithGeo(event *models.AuditEvent) {
+	if s.geoResolver == nil || event.IPAddress == "" {
+		return
+	}
+
+	location, err := s.geoResolver.Lookup(event.IPAddress)
+	if err != nil || location == nil {
+		s.logger.WithError(err).WithField("ip", event.IPAddress).Debug("GeoIP lookup skipped")
+		return
+	}
+
+	event.Country = location.Country
+	event.ASN = location.ASN
+	event.ASOrganization = location.ASOrganization
+}
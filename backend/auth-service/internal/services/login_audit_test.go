@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditRepository mocks the audit repository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.AuditEvent, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AuditEvent), args.Error(1)
+}
+
+func (m *MockAuditRepository) ListByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string, limit, offset int) ([]*models.AuditEvent, error) {
+	args := m.Called(ctx, userID, eventTypes, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AuditEvent), args.Error(1)
+}
+
+func (m *MockAuditRepository) CountByUserAndTypes(ctx context.Context, userID uuid.UUID, eventTypes []string) (int, error) {
+	args := m.Called(ctx, userID, eventTypes)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAuditRepository) SummarizeLogins(ctx context.Context, userID uuid.UUID) (*models.LoginSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginSummary), args.Error(1)
+}
+
+// stubGeoResolver returns a fixed location, or an error if set
+type stubGeoResolver struct {
+	location *models.GeoLocation
+	err      error
+}
+
+func (r *stubGeoResolver) Lookup(ip string) (*models.GeoLocation, error) {
+	return r.location, r.err
+}
+
+// TestLoginAuditEnrichment tests that login attempts are audited with GeoIP data
+func TestLoginAuditEnrichment(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	activeUser := func() *models.User {
+		return &models.User{
+			ID:           uuid.New(),
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{
+		IP:        "81.2.69.142",
+		UserAgent: "test-agent/1.0",
+		RequestID: "req-123",
+	})
+
+	t.Run("successful login is enriched with geo data", func(t *testing.T) {
+		user := activeUser()
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+
+		var recorded *models.AuditEvent
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).
+			Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.AuditEvent) }).
+			Return(nil)
+
+		resolver := &stubGeoResolver{location: &models.GeoLocation{
+			Country:        "GB",
+			ASN:            20712,
+			ASOrganization: "Andrews & Arnold Ltd",
+		}}
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithAuditRepository(auditRepo), WithGeoResolver(resolver))
+
+		_, err := service.Login(ctx, "john.doe@example.com", password)
+		require.NoError(t, err)
+
+		require.NotNil(t, recorded)
+		assert.Equal(t, models.AuditEventLoginSuccess, recorded.EventType)
+		assert.Equal(t, user.ID, *recorded.UserID)
+		assert.Equal(t, "81.2.69.142", recorded.IPAddress)
+		assert.Equal(t, "test-agent/1.0", recorded.UserAgent)
+		assert.Equal(t, "GB", recorded.Country)
+		assert.Equal(t, uint(20712), recorded.ASN)
+		assert.Equal(t, "Andrews & Arnold Ltd", recorded.ASOrganization)
+		assert.Equal(t, "req-123", recorded.Metadata["request_id"])
+		assert.Equal(t, "john.doe@example.com", recorded.Metadata["email"])
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed login is audited with reason", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(nil, appErrors.NewNotFound("user not found"))
+
+		var recorded *models.AuditEvent
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).
+			Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.AuditEvent) }).
+			Return(nil)
+
+		resolver := &stubGeoResolver{location: &models.GeoLocation{Country: "GB"}}
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithAuditRepository(auditRepo), WithGeoResolver(resolver))
+
+		_, err := service.Login(ctx, "nobody@example.com", password)
+		require.Error(t, err)
+
+		require.NotNil(t, recorded)
+		assert.Equal(t, models.AuditEventLoginFailure, recorded.EventType)
+		assert.Nil(t, recorded.UserID)
+		assert.Equal(t, loginFailureUnknownUser, recorded.Metadata["reason"])
+		assert.NotContains(t, recorded.Metadata, "email", "the submitted email isn't stored for unknown users")
+		assert.Equal(t, "GB", recorded.Country)
+	})
+
+	t.Run("no geo database skips enrichment without error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(activeUser(), nil)
+
+		var recorded *models.AuditEvent
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).
+			Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.AuditEvent) }).
+			Return(nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithAuditRepository(auditRepo))
+
+		_, err := service.Login(ctx, "john.doe@example.com", password)
+		require.NoError(t, err)
+
+		require.NotNil(t, recorded)
+		assert.Empty(t, recorded.Country)
+		assert.Zero(t, recorded.ASN)
+	})
+
+	t.Run("geo lookup failure still records the attempt", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(activeUser(), nil)
+
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).Return(nil)
+
+		resolver := &stubGeoResolver{err: errors.New("address not found")}
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithAuditRepository(auditRepo), WithGeoResolver(resolver))
+
+		_, err := service.Login(ctx, "john.doe@example.com", password)
+		require.NoError(t, err)
+		auditRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("audit write failure does not fail login", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(activeUser(), nil)
+
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).Return(errors.New("db down"))
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithAuditRepository(auditRepo))
+
+		response, err := service.Login(ctx, "john.doe@example.com", password)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+	})
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// Logout revokes the authenticated caller's access token (accessClaims) and,
+// when given, their refresh token, so neither can be used again. Each jti is
+// kept in the revocation store for the token's remaining lifetime. Without a
+// revocation store the jtis aren't recorded, but a refresh token is still
+// revoked server-side when rotation or session tracking is enabled: its
+// stored token family and the session it belongs to (or the access token's
+// session) are revoked.
+func (s *AuthService) Logout(ctx context.Context, accessClaims *utils.RegisteredTokenClaims, refreshToken string) error {
+	var refreshClaims *utils.RegisteredTokenClaims
+	if refreshToken != "" {
+		var err error
+		refreshClaims, err = utils.ValidateRefreshToken(refreshToken, s.signingKeys.KeyFor("refresh"), s.clock.Now())
+		if err != nil || refreshClaims.UserID != accessClaims.UserID {
+			return appErrors.NewUnauthorized("invalid or expired refresh token")
+		}
+	}
+
+	if s.revokedTokens != nil {
+		// Access tokens are accepted for the expiry grace after they expire
+		if err := s.revokeUntil(ctx, accessClaims.ID, accessClaims.ExpiresAt, s.expiryGraceFor(ctx)); err != nil {
+			return err
+		}
+		if refreshClaims != nil {
+			if err := s.revokeUntil(ctx, refreshClaims.ID, refreshClaims.ExpiresAt, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	if refreshToken != "" {
+		if err := s.revokeStoredRefreshToken(ctx, refreshToken); err != nil {
+			return err
+		}
+	}
+
+	sessionID := accessClaims.SessionID
+	if refreshClaims != nil && refreshClaims.SessionID != "" {
+		sessionID = refreshClaims.SessionID
+	}
+	return s.endSession(ctx, accessClaims.UserID, sessionID)
+}
+
+// revokeStoredRefreshToken revokes the stored family of a refresh token when
+// rotation is enabled, so neither it nor any token rotated from it can be
+// refreshed. Tokens issued before rotation was enabled aren't stored.
+func (s *AuthService) revokeStoredRefreshToken(ctx context.Context, refreshToken string) error {
+	if s.refreshTokenStore == nil {
+		return nil
+	}
+
+	record, err := s.refreshTokenStore.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if appErrors.GetAppError(err) != nil {
+			return nil
+		}
+		return appErrors.NewInternalError(err, "failed to look up refresh token")
+	}
+
+	if err := s.refreshTokenStore.RevokeFamily(ctx, record.FamilyID); err != nil {
+		return appErrors.NewInternalError(err, "failed to revoke refresh token")
+	}
+
+	return nil
+}
+
+// endSession revokes the session a logged-out token belongs to when sessions
+// are tracked. A session that is already revoked is left as is.
+func (s *AuthService) endSession(ctx context.Context, userID, sessionID string) error {
+	if s.sessions == nil || sessionID == "" {
+		return nil
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return appErrors.NewUnauthorized("invalid user ID in token")
+	}
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return appErrors.NewUnauthorized("invalid session in token")
+	}
+
+	if err := s.sessions.Revoke(ctx, uid, id); err != nil {
+		if appErrors.GetAppError(err) != nil {
+			return nil
+		}
+		return appErrors.NewInternalError(err, "failed to revoke session")
+	}
+
+	return nil
+}
+
+// revokeUntil revokes the token with the given jti until expiresAt (Unix
+// seconds) plus grace, after which it is rejected as expired anyway
+func (s *AuthService) revokeUntil(ctx context.Context, jti string, expiresAt int64, grace time.Duration) error {
+	ttl := time.Unix(expiresAt, 0).Add(grace).Sub(s.clock.Now())
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+
+	if _, err := s.revokedTokens.Increment(ctx, revokedTokenKey(jti), ttl); err != nil {
+		return appErrors.NewInternalError(err, "failed to revoke token")
+	}
+
+	return nil
+}
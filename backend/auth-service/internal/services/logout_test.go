@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/cache"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogout tests that logout revokes the caller's access and refresh tokens
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+
+	newService := func(opts ...Option) *AuthService {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, opts...)
+	}
+
+	issueTokens := func(t *testing.T, service *AuthService) (string, string) {
+		accessToken, _, err := service.issueAccessToken(ctx, user.ID.String(), user.Email)
+		require.NoError(t, err)
+		refreshToken, err := utils.GenerateRefreshToken(user.ID.String(), user.Email, 7*24*time.Hour, jwtSecret)
+		require.NoError(t, err)
+		return accessToken, refreshToken
+	}
+
+	t.Run("revoked tokens are rejected", func(t *testing.T) {
+		service := newService(WithTokenRevocation(cache.NewMemoryCounter()))
+		accessToken, refreshToken := issueTokens(t, service)
+		otherAccessToken, otherRefreshToken := issueTokens(t, service)
+
+		require.NoError(t, logoutAs(ctx, service, accessToken, refreshToken))
+
+		_, err := service.ValidateAccessToken(ctx, accessToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+
+		_, err = service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+
+		// Tokens from other logins are unaffected
+		_, err = service.ValidateAccessToken(ctx, otherAccessToken)
+		assert.NoError(t, err)
+		_, err = service.RefreshToken(ctx, otherRefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("refresh token is optional", func(t *testing.T) {
+		service := newService(WithTokenRevocation(cache.NewMemoryCounter()))
+		accessToken, refreshToken := issueTokens(t, service)
+
+		require.NoError(t, logoutAs(ctx, service, accessToken, ""))
+
+		_, err := service.ValidateAccessToken(ctx, accessToken)
+		assert.Error(t, err)
+		_, err = service.RefreshToken(ctx, refreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("another user's refresh token is rejected", func(t *testing.T) {
+		service := newService(WithTokenRevocation(cache.NewMemoryCounter()))
+		accessToken, _ := issueTokens(t, service)
+		otherRefreshToken, err := utils.GenerateRefreshToken(uuid.NewString(), "jane@example.com", time.Hour, jwtSecret)
+		require.NoError(t, err)
+
+		err = logoutAs(ctx, service, accessToken, otherRefreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+
+		// Nothing was revoked
+		_, err = service.ValidateAccessToken(ctx, accessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("an access token is not accepted as the refresh token", func(t *testing.T) {
+		service := newService(WithTokenRevocation(cache.NewMemoryCounter()))
+		accessToken, _ := issueTokens(t, service)
+
+		err := logoutAs(ctx, service, accessToken, accessToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("without revocation tokens stay valid", func(t *testing.T) {
+		service := newService()
+		accessToken, refreshToken := issueTokens(t, service)
+
+		require.NoError(t, logoutAs(ctx, service, accessToken, refreshToken))
+
+		_, err := service.ValidateAccessToken(ctx, accessToken)
+		assert.NoError(t, err)
+	})
+}
+
+// TestLogoutRevokesServerSideState tests that logout revokes a stored refresh
+// token and its session, so refreshing fails even without a revocation store
+func TestLogoutRevokesServerSideState(t *testing.T) {
+	ctx := context.Background()
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john.doe@example.com",
+		PasswordHash: passwordHash,
+		Status:       models.UserStatusActive,
+	}
+
+	newService := func(opts ...Option) *AuthService {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+		mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, opts...)
+	}
+
+	login := func(t *testing.T, service *AuthService) *models.LoginResponse {
+		user.PasswordHash = passwordHash // Login clears it on the returned user
+		response, err := service.Login(ctx, "john.doe@example.com", password)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("refresh and access tokens fail after logout", func(t *testing.T) {
+		sessions := newMemorySessionRepository()
+		service := newService(
+			WithTokenRevocation(cache.NewMemoryCounter()),
+			WithRefreshTokenRotation(newMemoryRefreshTokenRepository()),
+			WithSessions(sessions),
+		)
+		response := login(t, service)
+		other := login(t, service)
+
+		require.NoError(t, logoutAs(ctx, service, response.AccessToken, response.RefreshToken))
+
+		// POST /auth/refresh
+		_, err := service.RefreshToken(ctx, response.RefreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+
+		// GET /auth/me
+		_, err = service.ValidateAccessToken(ctx, response.AccessToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+
+		// The session is gone from the listing; the other login's remains
+		active, err := sessions.ListActiveByUser(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+
+		_, err = service.RefreshToken(ctx, other.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("stored refresh token is revoked without a revocation store", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(WithRefreshTokenRotation(store))
+		response := login(t, service)
+
+		// A token rotated from it is revoked along with it
+		rotated, err := service.RefreshToken(ctx, response.RefreshToken)
+		require.NoError(t, err)
+
+		require.NoError(t, logoutAs(ctx, service, response.AccessToken, rotated.RefreshToken))
+
+		_, err = service.RefreshToken(ctx, rotated.RefreshToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "revoked")
+	})
+
+	t.Run("access token's session ends without a refresh token", func(t *testing.T) {
+		sessions := newMemorySessionRepository()
+		service := newService(WithSessions(sessions))
+		response := login(t, service)
+
+		_, claims, err := service.ValidateAccessTokenWithClaims(ctx, response.AccessToken)
+		require.NoError(t, err)
+		require.NoError(t, service.Logout(ctx, claims, ""))
+
+		_, err = service.RefreshToken(ctx, response.RefreshToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "session has been revoked")
+
+		// Logging out again is harmless
+		assert.NoError(t, service.Logout(ctx, claims, ""))
+	})
+}
+
+// logoutAs logs out the way POST /auth/logout does: RequireAuth validates the
+// access token and the handler passes its claims to Logout
+func logoutAs(ctx context.Context, service *AuthService, accessToken, refreshToken string) error {
+	_, claims, err := service.ValidateAccessTokenWithClaims(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+	return service.Logout(ctx, claims, refreshToken)
+}
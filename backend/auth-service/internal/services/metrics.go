@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// Token validation results and cache outcomes used as metric labels
+const (
+	validationSuccess = "success"
+	validationFailure = "failure"
+
+	// There is no user cache yet, so every validation is a miss
+	validationCacheMiss = "miss"
+)
+
+// Registration funnel outcomes used as metric labels. The set is fixed so
+// the metric's cardinality stays bounded.
+const (
+	registrationSuccess           = "success"
+	registrationMissingField      = "missing_field"
+	registrationUnderage          = "underage"
+	registrationInvalidName       = "invalid_name"
+	registrationInvalidEmail      = "invalid_email"
+	registrationInvalidCountry    = "invalid_country"
+	registrationPostcodeMismatch  = "postcode_mismatch"
+	registrationWeakPassword      = "weak_password"
+	registrationDuplicateEmail    = "duplicate_email"
+	registrationDuplicatePhone    = "duplicate_phone"
+	registrationDuplicateIdentity = "duplicate_identity"
+	registrationRateLimited       = "rate_limited"
+	registrationDeviceInUse       = "device_in_use"
+	registrationError             = "error"
+)
+
+var (
+	// Access token validation latency (signature check + user lookup)
+	tokenValidationDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "auth_token_validation_seconds",
+			Help:    "Access token validation latency in seconds",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 12),
+		},
+		[]string{"result", "cache"},
+	)
+
+	// Where registrations end, for funnel analysis
+	registrationOutcomes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_registration_outcomes_total",
+			Help: "Registration attempts by outcome",
+		},
+		[]string{"outcome"},
+	)
+
+	// Rotated refresh tokens presented again, each a possible stolen token
+	refreshReuseDetections = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "auth_refresh_reuse_detected_total",
+			Help: "Reuses of already-rotated refresh tokens that revoked their token family",
+		},
+	)
+)
+
+// observeTokenValidation records how long a token validation that started at start took
+func observeTokenValidation(start time.Time, err error) {
+	result := validationSuccess
+	if err != nil {
+		result = validationFailure
+	}
+
+	tokenValidationDuration.WithLabelValues(result, validationCacheMiss).Observe(time.Since(start).Seconds())
+}
+
+// registrationCreateOutcome classifies an error from creating the user row.
+// Unique violations that slip past the pre-checks (e.g. a concurrent
+// registration) count as duplicates.
+func registrationCreateOutcome(err error) string {
+	switch {
+	case errors.Is(err, appErrors.ErrPhoneInUse):
+		return registrationDuplicatePhone
+	case errors.Is(err, appErrors.ErrUserAlreadyExists):
+		return registrationDuplicateEmail
+	default:
+		return registrationError
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// validationSampleCount returns the number of observations recorded for a result label
+func validationSampleCount(t *testing.T, result string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "auth_token_validation_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" && label.GetValue() == result {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+// TestTokenValidationMetrics tests that validation latency is recorded per result
+func TestTokenValidationMetrics(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	userID := uuid.New()
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByID", mock.Anything, userID).Return(&models.User{
+		ID:     userID,
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}, nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+	token, err := utils.GenerateAccessToken(userID.String(), "john.doe@example.com", 15*time.Minute, jwtSecret)
+	require.NoError(t, err)
+
+	successBefore := validationSampleCount(t, validationSuccess)
+	failureBefore := validationSampleCount(t, validationFailure)
+
+	_, err = service.ValidateAccessToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, successBefore+1, validationSampleCount(t, validationSuccess))
+
+	_, err = service.ValidateAccessToken(context.Background(), "not-a-valid-token")
+	require.Error(t, err)
+	assert.Equal(t, failureBefore+1, validationSampleCount(t, validationFailure))
+	assert.Equal(t, successBefore+1, validationSampleCount(t, validationSuccess))
+}
+
+// registrationOutcomeCount returns the auth_registration_outcomes_total value for an outcome
+func registrationOutcomeCount(t *testing.T, outcome string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "auth_registration_outcomes_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "outcome" && label.GetValue() == outcome {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+// TestRegistrationOutcomeMetrics tests that each registration is counted under the step it ended at
+func TestRegistrationOutcomeMetrics(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "existing@example.com").Return(&models.User{ID: uuid.New(), Email: "existing@example.com"}, nil)
+	mockRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, appErrors.NewNotFound("user not found"))
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+	t.Run("underage", func(t *testing.T) {
+		before := registrationOutcomeCount(t, registrationUnderage)
+
+		req := newTimingTestRequest("teen@example.com")
+		req.DateOfBirth = time.Now().AddDate(-16, 0, 0)
+		_, err := service.Register(context.Background(), req)
+		require.Error(t, err)
+
+		assert.Equal(t, before+1, registrationOutcomeCount(t, registrationUnderage))
+	})
+
+	t.Run("weak password", func(t *testing.T) {
+		before := registrationOutcomeCount(t, registrationWeakPassword)
+
+		req := newTimingTestRequest("weak@example.com")
+		req.Password = "alllowercase1!"
+		_, err := service.Register(context.Background(), req)
+		require.Error(t, err)
+
+		assert.Equal(t, before+1, registrationOutcomeCount(t, registrationWeakPassword))
+	})
+
+	t.Run("duplicate email", func(t *testing.T) {
+		before := registrationOutcomeCount(t, registrationDuplicateEmail)
+
+		_, err := service.Register(context.Background(), newTimingTestRequest("existing@example.com"))
+		require.Error(t, err)
+
+		assert.Equal(t, before+1, registrationOutcomeCount(t, registrationDuplicateEmail))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		before := registrationOutcomeCount(t, registrationSuccess)
+
+		_, err := service.Register(context.Background(), newTimingTestRequest("new@example.com"))
+		require.NoError(t, err)
+
+		assert.Equal(t, before+1, registrationOutcomeCount(t, registrationSuccess))
+	})
+}
+
+// TestRegistrationCreateOutcome tests classifying errors from creating the user row
+func TestRegistrationCreateOutcome(t *testing.T) {
+	assert.Equal(t, registrationDuplicatePhone, registrationCreateOutcome(appErrors.NewPhoneConflict("user with this phone number already exists")))
+	assert.Equal(t, registrationDuplicateEmail, registrationCreateOutcome(appErrors.NewConflict("user with this email already exists")))
+	assert.Equal(t, registrationError, registrationCreateOutcome(errors.New("connection refused")))
+}
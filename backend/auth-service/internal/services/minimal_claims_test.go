@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMinimalClaims tests login and validation with minimal access tokens
+func TestMinimalClaims(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	newUser := func() *models.User {
+		return &models.User{
+			ID:           userID,
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(newUser(), nil)
+	mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithMinimalClaims(true))
+
+	response, err := service.Login(context.Background(), "john.doe@example.com", password)
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateTokenWithClaims(response.AccessToken, jwtSecret)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Email, "minimal tokens should not carry the email")
+	assert.Equal(t, userID.String(), claims.Subject)
+
+	// The user is still resolved from sub
+	user, err := service.ValidateAccessToken(context.Background(), response.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+	assert.Equal(t, "john.doe@example.com", user.Email)
+
+	// Refresh tokens keep the full claims, since they never travel in headers
+	refreshClaims, err := utils.ValidateTokenWithClaims(response.RefreshToken, jwtSecret)
+	require.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", refreshClaims.Email)
+}
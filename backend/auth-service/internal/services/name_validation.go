@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// NamePolicy holds the validation rules for first and last names
+type NamePolicy struct {
+	MinLength int // Minimum length in characters (runes)
+	MaxLength int // Maximum length in characters (runes)
+
+	// RestrictCharacters rejects digits, control characters and symbols.
+	// Unicode letters are always allowed for international names.
+	RestrictCharacters bool
+}
+
+// DefaultNamePolicy returns the default name validation rules
+func DefaultNamePolicy() NamePolicy {
+	return NamePolicy{
+		MinLength:          1,
+		MaxLength:          100, // users.first_name / last_name are VARCHAR(100)
+		RestrictCharacters: true,
+	}
+}
+
+// validateNames validates first and last name, returning field-level errors
+func (s *AuthService) validateNames(req *models.RegisterRequest) error {
+	validationErr := appErrors.NewValidationError()
+
+	if msg := s.validateName("first name", req.FirstName); msg != "" {
+		validationErr.Add("first_name", msg)
+	}
+	if msg := s.validateName("last name", req.LastName); msg != "" {
+		validationErr.Add("last_name", msg)
+	}
+
+	if validationErr.HasErrors() {
+		return validationErr
+	}
+	return nil
+}
+
+// validateName checks a single name against the policy and returns a message on failure
+func (s *AuthService) validateName(label, name string) string {
+	name = strings.TrimSpace(name)
+	length := utf8.RuneCountInString(name)
+
+	if length < s.namePolicy.MinLength {
+		return fmt.Sprintf("%s must be at least %d characters long", label, s.namePolicy.MinLength)
+	}
+
+	if s.namePolicy.MaxLength > 0 && length > s.namePolicy.MaxLength {
+		return fmt.Sprintf("%s must be at most %d characters long", label, s.namePolicy.MaxLength)
+	}
+
+	if s.namePolicy.RestrictCharacters {
+		for _, char := range name {
+			if !isAllowedNameRune(char) {
+				return fmt.Sprintf("%s contains invalid characters", label)
+			}
+		}
+	}
+
+	return ""
+}
+
+// isAllowedNameRune reports whether a rune may appear in a name:
+// letters (any script), combining marks, spaces, hyphens, apostrophes and
+// periods. Spacing marks (Mc) are needed for the vowel signs of Indic scripts.
+func isAllowedNameRune(char rune) bool {
+	if unicode.IsLetter(char) || unicode.In(char, unicode.Mn, unicode.Mc) {
+		return true
+	}
+
+	switch char {
+	case ' ', '-', '\'', '’', '.':
+		return true
+	}
+
+	return false
+}
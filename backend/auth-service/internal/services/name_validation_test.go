@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newNameTestRequest builds a valid registration request with the given names
+func newNameTestRequest(firstName, lastName string) *models.RegisterRequest {
+	return &models.RegisterRequest{
+		Email:        "john.doe@example.com",
+		Phone:        "+447700900123",
+		Password:     "SecurePass123!",
+		FirstName:    firstName,
+		LastName:     lastName,
+		DateOfBirth:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		AddressLine1: "123 Main St",
+		City:         "London",
+		Postcode:     "SW1A 1AA",
+		Country:      "UK",
+	}
+}
+
+// TestNameValidation tests configurable name length and character rules
+func TestNameValidation(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	policy := NamePolicy{
+		MinLength:          2,
+		MaxLength:          20,
+		RestrictCharacters: true,
+	}
+
+	tests := []struct {
+		name      string
+		firstName string
+		lastName  string
+		wantErr   bool
+		errFields map[string]string
+	}{
+		{
+			name:      "valid names",
+			firstName: "John",
+			lastName:  "O'Brien-Smith",
+			wantErr:   false,
+		},
+		{
+			name:      "unicode letters allowed",
+			firstName: "Zoë",
+			lastName:  "Łukasiewicz",
+			wantErr:   false,
+		},
+		{
+			name:      "non-latin script allowed",
+			firstName: "明美",
+			lastName:  "Дмитриев",
+			wantErr:   false,
+		},
+		{
+			// Vowel signs such as U+093F and U+093E are spacing marks (Mc)
+			name:      "devanagari name allowed",
+			firstName: "प्रिया",
+			lastName:  "शर्मा",
+			wantErr:   false,
+		},
+		{
+			name:      "tamil name allowed",
+			firstName: "சிவா",
+			lastName:  "ராமன்",
+			wantErr:   false,
+		},
+		{
+			name:      "too short",
+			firstName: "J",
+			lastName:  "Doe",
+			wantErr:   true,
+			errFields: map[string]string{
+				"first_name": "first name must be at least 2 characters long",
+			},
+		},
+		{
+			name:      "too long",
+			firstName: "John",
+			lastName:  strings.Repeat("a", 21),
+			wantErr:   true,
+			errFields: map[string]string{
+				"last_name": "last name must be at most 20 characters long",
+			},
+		},
+		{
+			name:      "digits not allowed",
+			firstName: "J0hn",
+			lastName:  "Doe",
+			wantErr:   true,
+			errFields: map[string]string{
+				"first_name": "first name contains invalid characters",
+			},
+		},
+		{
+			name:      "control characters not allowed",
+			firstName: "John",
+			lastName:  "Do\x00e",
+			wantErr:   true,
+			errFields: map[string]string{
+				"last_name": "last name contains invalid characters",
+			},
+		},
+		{
+			name:      "both names invalid",
+			firstName: "J",
+			lastName:  "Doe99",
+			wantErr:   true,
+			errFields: map[string]string{
+				"first_name": "first name must be at least 2 characters long",
+				"last_name":  "last name contains invalid characters",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			if !tt.wantErr {
+				mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(nil, appErrors.NewNotFound("user not found"))
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+			}
+
+			service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithNamePolicy(policy))
+
+			user, err := service.Register(context.Background(), newNameTestRequest(tt.firstName, tt.lastName))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, user)
+
+				validationErr := appErrors.GetValidationError(err)
+				require.NotNil(t, validationErr)
+				assert.Equal(t, tt.errFields, validationErr.Fields)
+				assert.Equal(t, 400, appErrors.GetStatusCode(err))
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, user)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestNameValidationCharacterRestrictionDisabled tests that the character check is optional
+func TestNameValidationCharacterRestrictionDisabled(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	policy := DefaultNamePolicy()
+	policy.RestrictCharacters = false
+
+	service := NewAuthService(new(MockUserRepository), jwtSecret, 15*time.Minute, 7*24*time.Hour, WithNamePolicy(policy))
+
+	err := service.validateNames(newNameTestRequest("J0hn", "Doe"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/repository"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures optional AuthService behaviour
+type Option func(*AuthService)
+
+// WithNamePolicy sets the validation rules applied to first and last names
+func WithNamePolicy(policy NamePolicy) Option {
+	return func(s *AuthService) {
+		s.namePolicy = policy
+	}
+}
+
+// WithLogger sets the logger used for non-fatal service errors
+func WithLogger(logger *logrus.Logger) Option {
+	return func(s *AuthService) {
+		s.logger = logger
+	}
+}
+
+// WithAuditRepository enables audit logging of login attempts
+func WithAuditRepository(auditRepo repository.AuditRepository) Option {
+	return func(s *AuthService) {
+		s.auditRepo = auditRepo
+	}
+}
+
+// WithGeoResolver enables GeoIP enrichment of audit events
+func WithGeoResolver(resolver GeoResolver) Option {
+	return func(s *AuthService) {
+		s.geoResolver = resolver
+	}
+}
+
+// WithTokenBinding sets what the tokens of logins that opt in to binding
+// (LoginOptions.BindToken) are bound to: the client IP or user agent
+func WithTokenBinding(mode TokenBindingMode) Option {
+	return func(s *AuthService) {
+		s.tokenBinding = mode
+	}
+}
+
+// WithDuplicateIdentityCheck enables detection of registrations matching an
+// existing user's name, date of birth and postcode
+func WithDuplicateIdentityCheck(mode DuplicateIdentityMode) Option {
+	return func(s *AuthService) {
+		s.duplicateIdentity = mode
+	}
+}
+
+// WithPostcodeCheck checks that a registration's postcode has the format of
+// its declared country, logging or rejecting mismatches
+func WithPostcodeCheck(mode PostcodeCheckMode) Option {
+	return func(s *AuthService) {
+		s.postcodeCheck = mode
+	}
+}
+
+// WithRefreshTokens enables or disables refresh tokens. When disabled, Login
+// issues only an access token and RefreshToken is rejected.
+func WithRefreshTokens(enabled bool) Option {
+	return func(s *AuthService) {
+		s.refreshTokensEnabled = enabled
+	}
+}
+
+// WithRememberMe sets the refresh token lifetime for logins with remember me.
+// Zero disables remember me, so every login gets the default refresh expiry.
+func WithRememberMe(refreshDuration time.Duration) Option {
+	return func(s *AuthService) {
+		s.rememberMeDuration = refreshDuration
+	}
+}
+
+// WithRegistrationLimit caps registrations per client IP per day (UTC),
+// counted in the given store
+func WithRegistrationLimit(counter Counter, perIPPerDay int) Option {
+	return func(s *AuthService) {
+		s.registrationCounter = counter
+		s.registrationsPerIPPerDay = perIPPerDay
+	}
+}
+
+// WithPasswordHashing sets the registry used to hash and verify passwords
+func WithPasswordHashing(registry *utils.HashRegistry) Option {
+	return func(s *AuthService) {
+		s.passwordHasher = registry
+	}
+}
+
+// WithPasswordHashConcurrency caps concurrent password hash operations at
+// limit (GOMAXPROCS when limit is not positive). Operations beyond the cap
+// wait up to queueTimeout for a free slot, then fail with 503.
+func WithPasswordHashConcurrency(limit int, queueTimeout time.Duration) Option {
+	return func(s *AuthService) {
+		s.hashSlots = newHashLimiter(limit, queueTimeout)
+	}
+}
+
+// WithMinimalClaims issues access tokens carrying only sub, exp, iat, jti and
+// token_type. Consumers needing the email call GET /auth/me instead.
+func WithMinimalClaims(enabled bool) Option {
+	return func(s *AuthService) {
+		s.minimalClaims = enabled
+	}
+}
+
+// WithPasswordPolicy sets the rules new passwords are validated against.
+// As with WithPasswordMinLength and WithMaxPasswordBytes, a minimum length
+// below DefaultPasswordMinLength or a maximum above bcrypt's 72-byte limit
+// keep the default, and no special characters keeps the default set.
+func WithPasswordPolicy(policy models.PasswordPolicy) Option {
+	return func(s *AuthService) {
+		defaults := DefaultPasswordPolicy()
+		if policy.MinLength < DefaultPasswordMinLength {
+			policy.MinLength = defaults.MinLength
+		}
+		if policy.MaxBytes <= 0 || policy.MaxBytes > utils.MaxPasswordBytes {
+			policy.MaxBytes = defaults.MaxBytes
+		}
+		if policy.SpecialCharacters == "" {
+			policy.SpecialCharacters = defaults.SpecialCharacters
+		}
+		s.passwordPolicy = policy
+	}
+}
+
+// WithMaxPasswordBytes sets the maximum password length in bytes of UTF-8.
+// Values above bcrypt's 72-byte limit are capped to it.
+func WithMaxPasswordBytes(maxBytes int) Option {
+	return func(s *AuthService) {
+		if maxBytes > 0 && maxBytes <= utils.MaxPasswordBytes {
+			s.passwordPolicy.MaxBytes = maxBytes
+		}
+	}
+}
+
+// WithPasswordMinLength sets the minimum password length in characters.
+// Values below DefaultPasswordMinLength are ignored.
+func WithPasswordMinLength(minLength int) Option {
+	return func(s *AuthService) {
+		if minLength >= DefaultPasswordMinLength {
+			s.passwordPolicy.MinLength = minLength
+		}
+	}
+}
+
+// WithRefreshTokenSecret signs and validates refresh tokens with their own
+// secret instead of the access token secret. An empty secret keeps the default.
+func WithRefreshTokenSecret(secret string) Option {
+	return func(s *AuthService) {
+		s.signingKeys.Refresh = secret
+	}
+}
+
+// WithAccessTokenRSAKey signs access tokens with RS256 using the given keys
+// instead of HS256 with the JWT secret, so other services can verify them
+// with the public key. Access tokens signed with the secret are then
+// rejected. A nil key pair keeps HS256.
+func WithAccessTokenRSAKey(keys *utils.RSAKeyPair) Option {
+	return func(s *AuthService) {
+		s.signingKeys.AccessRSA = keys
+	}
+}
+
+// WithRetiredAccessTokenRSAKeys keeps accepting RS256 access tokens signed
+// with previous keys after a key rotation, until they expire. Only their
+// public keys are needed.
+func WithRetiredAccessTokenRSAKeys(keys []*utils.RSAKeyPair) Option {
+	return func(s *AuthService) {
+		s.signingKeys.RetiredAccessRSA = keys
+	}
+}
+
+// WithAccessTokenJitter shifts each access token's expiry by a random offset
+// of up to ±fraction of the configured expiry (e.g. 0.1 for ±10%), capped at
+// max when max is positive. Fractions outside (0, MaxAccessTokenJitter] disable jitter.
+func WithAccessTokenJitter(fraction float64, max time.Duration) Option {
+	return func(s *AuthService) {
+		if fraction <= 0 || fraction > MaxAccessTokenJitter {
+			s.jitter = accessTokenJitter{}
+			return
+		}
+		s.jitter = accessTokenJitter{fraction: fraction, max: max}
+	}
+}
+
+// WithTokenRevocation enables revoking individual tokens by jti, tracked in
+// the given store. Logout then revokes the caller's tokens.
+func WithTokenRevocation(store RevocationStore) Option {
+	return func(s *AuthService) {
+		s.revokedTokens = store
+	}
+}
+
+// WithRefreshTokenRotation stores issued refresh tokens (hashed) and rotates
+// them on every refresh. Presenting an already-rotated token after the reuse
+// grace period (see WithRefreshReuseGrace) revokes every token descended from
+// the same login.
+func WithRefreshTokenRotation(store repository.RefreshTokenRepository) Option {
+	return func(s *AuthService) {
+		s.refreshTokenStore = store
+	}
+}
+
+// WithRefreshReuseGrace sets how long after a refresh token is rotated
+// presenting it again is taken as a concurrent refresh, rejected without
+// revoking the token family. Zero treats every reuse as theft; negative
+// values are ignored.
+func WithRefreshReuseGrace(grace time.Duration) Option {
+	return func(s *AuthService) {
+		if grace >= 0 {
+			s.refreshReuseGrace = grace
+		}
+	}
+}
+
+// WithSessions tracks a session per refresh token in repo, so users can list
+// where they're signed in and revoke sessions
+func WithSessions(repo repository.SessionRepository) Option {
+	return func(s *AuthService) {
+		s.sessions = repo
+	}
+}
+
+// WithSessionIdleTimeout rejects tokens from a session that hasn't been used
+// for longer than timeout, so the user has to log in again. Zero disables the
+// idle timeout; negative values are ignored.
+func WithSessionIdleTimeout(timeout time.Duration) Option {
+	return func(s *AuthService) {
+		if timeout >= 0 {
+			s.sessionIdleTimeout = timeout
+		}
+	}
+}
+
+// WithDeviceBindings binds each device ID to the first user to register or
+// sign in with it, rejecting the device for any other user
+func WithDeviceBindings(repo repository.DeviceBindingRepository) Option {
+	return func(s *AuthService) {
+		s.deviceBindings = repo
+	}
+}
+
+// WithEmailVerification issues a verification token to each new user,
+// stored (hashed) in repo and delivered with sender, and serves verify and
+// resend requests. Tokens expire after ttl; zero or less uses
+// DefaultEmailVerificationTTL.
+func WithEmailVerification(repo repository.EmailVerificationRepository, sender VerificationSender, ttl time.Duration) Option {
+	return func(s *AuthService) {
+		if ttl <= 0 {
+			ttl = DefaultEmailVerificationTTL
+		}
+		s.emailVerifications = repo
+		s.verificationSender = sender
+		s.emailVerificationTTL = ttl
+	}
+}
+
+// WithRequireEmailVerification rejects logins by users who haven't verified
+// their email address
+func WithRequireEmailVerification(required bool) Option {
+	return func(s *AuthService) {
+		s.requireEmailVerification = required
+	}
+}
+
+// WithClosureCoolingOff sets how long a closure request waits before the
+// account is anonymized and closed. Negative values are ignored.
+func WithClosureCoolingOff(coolingOff time.Duration) Option {
+	return func(s *AuthService) {
+		if coolingOff >= 0 {
+			s.closureCoolingOff = coolingOff
+		}
+	}
+}
+
+// WithExpiredTokenGrace accepts access tokens up to grace past their expiry
+// for read-only requests, smoothing over clients refreshing just too late.
+// Mutating requests always require an unexpired token. Zero disables the
+// grace period and values above MaxExpiredTokenGrace are capped to it.
+func WithExpiredTokenGrace(grace time.Duration) Option {
+	return func(s *AuthService) {
+		if grace > MaxExpiredTokenGrace {
+			grace = MaxExpiredTokenGrace
+		}
+		s.expiryGrace = grace
+	}
+}
+
+// WithClock sets the clock used for token issue and expiry, age checks and
+// timestamps. Tests use a fake clock to move time without sleeping.
+func WithClock(c clock.Clock) Option {
+	return func(s *AuthService) {
+		s.clock = c
+	}
+}
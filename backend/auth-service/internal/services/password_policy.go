@@ -0,0 +1,41 @@
+package services
+
+import (
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+)
+
+// passwordSpecialCharacters are the characters accepted as special characters
+const passwordSpecialCharacters = `!@#$%^&*()_+-=[]{};':"\|,.<>/?`
+
+// DefaultPasswordMinLength is the shortest password accepted by default
+const DefaultPasswordMinLength = 8
+
+// DefaultPasswordPolicy returns the default password rules. There is no
+// breached-password check, so BreachCheck is always false.
+func DefaultPasswordPolicy() models.PasswordPolicy {
+	return models.PasswordPolicy{
+		MinLength:           DefaultPasswordMinLength,
+		MaxBytes:            utils.MaxPasswordBytes,
+		RequireUppercase:    true,
+		RequireLowercase:    true,
+		RequireDigit:        true,
+		RequireSpecial:      true,
+		SpecialCharacters:   passwordSpecialCharacters,
+		CommonPasswordCheck: true,
+	}
+}
+
+// PasswordPolicy returns the rules new passwords are validated against
+func (s *AuthService) PasswordPolicy() models.PasswordPolicy {
+	return s.passwordPolicy
+}
+
+// countUniqueRunes returns the number of distinct characters in s
+func countUniqueRunes(s string) int {
+	seen := make(map[rune]struct{})
+	for _, char := range s {
+		seen[char] = struct{}{}
+	}
+	return len(seen)
+}
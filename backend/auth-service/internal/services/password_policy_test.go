@@ -0,0 +1,100 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPasswordPolicy tests that the published policy matches the configured rules
+func TestPasswordPolicy(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	mockRepo := new(MockUserRepository)
+
+	t.Run("defaults", func(t *testing.T) {
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		policy := service.PasswordPolicy()
+		assert.Equal(t, DefaultPasswordPolicy(), policy)
+		assert.Equal(t, 8, policy.MinLength)
+		assert.Equal(t, 72, policy.MaxBytes)
+		assert.True(t, policy.CommonPasswordCheck)
+		assert.False(t, policy.BreachCheck)
+	})
+
+	t.Run("configured lengths are published and enforced", func(t *testing.T) {
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithPasswordMinLength(12), WithMaxPasswordBytes(32))
+
+		policy := service.PasswordPolicy()
+		assert.Equal(t, 12, policy.MinLength)
+		assert.Equal(t, 32, policy.MaxBytes)
+
+		err := service.validatePassword("Secure1!abc")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at least 12 characters")
+		assert.NoError(t, service.validatePassword("Secure1!abcd"))
+	})
+
+	t.Run("minimum below the default is ignored", func(t *testing.T) {
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithPasswordMinLength(4))
+		assert.Equal(t, DefaultPasswordMinLength, service.PasswordPolicy().MinLength)
+	})
+}
+
+// TestValidatePasswordPolicy tests configured policies are enforced and that
+// every failed rule is reported
+func TestValidatePasswordPolicy(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	mockRepo := new(MockUserRepository)
+
+	t.Run("every failed rule is listed", func(t *testing.T) {
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		err := service.validatePassword("weak")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, appErrors.ErrWeakPassword)
+
+		policyErr := appErrors.GetPasswordPolicyError(err)
+		require.NotNil(t, policyErr)
+		assert.Equal(t, http.StatusBadRequest, policyErr.StatusCode)
+		assert.Equal(t, []string{
+			"password must be at least 8 characters long",
+			"password must contain at least one uppercase letter",
+			"password must contain at least one number",
+			"password must contain at least one special character",
+		}, policyErr.Violations)
+	})
+
+	t.Run("length-only policy", func(t *testing.T) {
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithPasswordPolicy(models.PasswordPolicy{MinLength: 15, CommonPasswordCheck: true}))
+
+		assert.NoError(t, service.validatePassword("correct horse battery"))
+
+		err := service.validatePassword("short phrase")
+		require.Error(t, err)
+		assert.Equal(t, []string{"password must be at least 15 characters long"}, appErrors.GetPasswordPolicyError(err).Violations)
+
+		// Unset limits keep their defaults
+		policy := service.PasswordPolicy()
+		assert.Equal(t, 72, policy.MaxBytes)
+		assert.Equal(t, DefaultPasswordPolicy().SpecialCharacters, policy.SpecialCharacters)
+	})
+
+	t.Run("minimum unique characters", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+		policy.MinUniqueChars = 6
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithPasswordPolicy(policy))
+
+		err := service.validatePassword("Aa1!Aa1!Aa1!")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at least 6 different characters")
+		assert.NoError(t, service.validatePassword("Secure1!abc"))
+	})
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// rehashPasswordIfNeeded replaces a hash from an older scheme or weaker
+// parameters after a successful login. Failures are logged and the old hash
+// is kept, so they never fail the login.
+func (s *AuthService) rehashPasswordIfNeeded(ctx context.Context, user *models.User, password string) {
+	if !s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	passwordHash, err := s.hashPassword(ctx, password)
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Warn("Failed to rehash password")
+		return
+	}
+
+	if err := s.userRepo.UpdatePasswordHash(ctx, user.ID, passwordHash); err != nil {
+		s.logger.WithError(err).WithField("user_id", user.ID).Warn("Failed to store rehashed password")
+		return
+	}
+	user.PasswordHash = passwordHash
+}
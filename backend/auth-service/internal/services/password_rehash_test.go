@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestLoginRehashesPassword tests that login migrates hashes to the configured scheme
+func TestLoginRehashesPassword(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+
+	bcryptHash, err := utils.NewBcryptScheme(bcrypt.DefaultCost).Hash(password)
+	require.NoError(t, err)
+
+	argon2id, err := utils.NewPasswordHashRegistry(utils.HashSchemeArgon2id)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		hasher     *utils.HashRegistry
+		updateErr  error
+		wantRehash bool
+	}{
+		{
+			name:       "bcrypt hash is migrated to argon2id",
+			hasher:     argon2id,
+			wantRehash: true,
+		},
+		{
+			name:   "hash from the default scheme is kept",
+			hasher: utils.DefaultHashRegistry(),
+		},
+		{
+			name:       "failure to store the new hash does not fail login",
+			hasher:     argon2id,
+			updateErr:  errors.New("connection refused"),
+			wantRehash: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{
+				ID:           uuid.New(),
+				Email:        "john.doe@example.com",
+				PasswordHash: bcryptHash,
+				Status:       models.UserStatusActive,
+			}
+
+			var storedHash string
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+			if tt.wantRehash {
+				mockRepo.On("UpdatePasswordHash", mock.Anything, user.ID, mock.AnythingOfType("string")).
+					Run(func(args mock.Arguments) { storedHash = args.String(2) }).
+					Return(tt.updateErr)
+			}
+
+			service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+				WithPasswordHashing(tt.hasher))
+
+			response, err := service.Login(context.Background(), user.Email, password)
+			require.NoError(t, err)
+			require.NotNil(t, response)
+
+			if tt.wantRehash {
+				assert.True(t, tt.hasher.NeedsRehash(bcryptHash))
+				assert.False(t, tt.hasher.NeedsRehash(storedHash))
+				assert.NoError(t, tt.hasher.Verify(storedHash, password))
+			} else {
+				mockRepo.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PostcodeCheckMode selects how registrations whose postcode doesn't match
+// the declared country's format are handled
+type PostcodeCheckMode string
+
+const (
+	// PostcodeCheckOff skips the check
+	PostcodeCheckOff PostcodeCheckMode = "off"
+	// PostcodeCheckWarn logs mismatches and lets the registration through
+	PostcodeCheckWarn PostcodeCheckMode = "warn"
+	// PostcodeCheckBlock rejects mismatches with a postcode field error
+	PostcodeCheckBlock PostcodeCheckMode = "block"
+)
+
+// ParsePostcodeCheckMode parses a postcode check mode from configuration
+func ParsePostcodeCheckMode(value string) (PostcodeCheckMode, error) {
+	switch mode := PostcodeCheckMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", PostcodeCheckOff:
+		return PostcodeCheckOff, nil
+	case PostcodeCheckWarn, PostcodeCheckBlock:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid postcode check mode: %q", value)
+	}
+}
+
+// checkPostcodeCountry checks the postcode has the format of the declared
+// country. Countries without a known format always pass.
+func (s *AuthService) checkPostcodeCountry(req *models.RegisterRequest) error {
+	if s.postcodeCheck == "" || s.postcodeCheck == PostcodeCheckOff {
+		return nil
+	}
+
+	if matches, _ := utils.PostcodeMatchesCountry(req.Postcode, req.Country); matches {
+		return nil
+	}
+
+	if s.postcodeCheck == PostcodeCheckWarn {
+		s.logger.WithFields(logrus.Fields{
+			"country": req.Country,
+		}).Warn("Registration postcode does not match the declared country")
+		return nil
+	}
+
+	validationErr := appErrors.NewValidationError()
+	validationErr.Add("postcode", fmt.Sprintf("postcode is not valid for country %s", strings.ToUpper(strings.TrimSpace(req.Country))))
+	return validationErr
+}
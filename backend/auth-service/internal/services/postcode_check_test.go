@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestPostcodeCheck tests registrations whose postcode doesn't match the
+// declared country are logged or rejected depending on the mode
+func TestPostcodeCheck(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+
+	newService := func(mode PostcodeCheckMode) *AuthService {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, appErrors.NewNotFound("user not found"))
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+			WithPostcodeCheck(mode),
+			WithPasswordHashing(utils.NewHashRegistry(utils.NewBcryptScheme(bcrypt.MinCost))))
+	}
+
+	t.Run("UK country with a UK postcode passes", func(t *testing.T) {
+		req := newTimingTestRequest("john.doe@example.com")
+		_, err := newService(PostcodeCheckBlock).Register(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("UK country with a US ZIP code is rejected in block mode", func(t *testing.T) {
+		req := newTimingTestRequest("john.doe@example.com")
+		req.Postcode = "90210"
+
+		_, err := newService(PostcodeCheckBlock).Register(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, appErrors.GetStatusCode(err))
+
+		validationErr := appErrors.GetValidationError(err)
+		require.NotNil(t, validationErr)
+		assert.Equal(t, "postcode is not valid for country GB", validationErr.Fields["postcode"])
+	})
+
+	t.Run("mismatch is allowed in warn mode", func(t *testing.T) {
+		req := newTimingTestRequest("john.doe@example.com")
+		req.Postcode = "90210"
+
+		_, err := newService(PostcodeCheckWarn).Register(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("countries without a known format are not checked", func(t *testing.T) {
+		req := newTimingTestRequest("john.doe@example.com")
+		req.Country = "JP"
+		req.Postcode = "90210"
+
+		_, err := newService(PostcodeCheckBlock).Register(context.Background(), req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := ParsePostcodeCheckMode("strict")
+		assert.Error(t, err)
+
+		mode, err := ParsePostcodeCheckMode("")
+		require.NoError(t, err)
+		assert.Equal(t, PostcodeCheckOff, mode)
+	})
+}
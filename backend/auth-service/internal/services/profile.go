@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// UpdateProfile applies the profile changes of the authenticated caller and
+// returns the updated user. Only fields present in req change; email and
+// date of birth can't be changed here.
+func (s *AuthService) UpdateProfile(ctx context.Context, caller *models.User, req *models.UpdateProfileRequest) (*models.User, error) {
+	// Change a copy, leaving the caller as authenticated
+	user := *caller
+	if err := s.applyProfileUpdate(&user, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, &user); err != nil {
+		return nil, err
+	}
+
+	// Re-read rather than return our copy, so columns the update doesn't
+	// write (KYC status, created_at) reflect any change made meanwhile
+	updated, err := s.userRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("user_id", user.ID).Info("Profile updated")
+	s.recordProfileUpdate(ctx, caller, &user)
+
+	updated.PasswordHash = ""
+	return updated, nil
+}
+
+// applyProfileUpdate validates the fields present in req and copies them onto user
+func (s *AuthService) applyProfileUpdate(user *models.User, req *models.UpdateProfileRequest) error {
+	if req == nil || *req == (models.UpdateProfileRequest{}) {
+		return appErrors.NewBadRequest("no profile fields to update")
+	}
+
+	validationErr := appErrors.NewValidationError()
+
+	if req.FirstName != nil {
+		if msg := s.validateName("first name", *req.FirstName); msg != "" {
+			validationErr.Add("first_name", msg)
+		}
+		user.FirstName = strings.TrimSpace(*req.FirstName)
+	}
+	if req.LastName != nil {
+		if msg := s.validateName("last name", *req.LastName); msg != "" {
+			validationErr.Add("last_name", msg)
+		}
+		user.LastName = strings.TrimSpace(*req.LastName)
+	}
+	if req.Phone != nil {
+		phone := utils.NormalizePhone(*req.Phone)
+		if !utils.IsValidPhone(phone) {
+			validationErr.Add("phone", "phone must be 7 to 15 digits, e.g. +447700900123")
+		}
+		user.Phone = phone
+	}
+
+	// Required address fields may change but not be cleared
+	required := []struct {
+		field, label string
+		value        *string
+		target       *string
+	}{
+		{"address_line1", "address line 1", req.AddressLine1, &user.AddressLine1},
+		{"city", "city", req.City, &user.City},
+		{"postcode", "postcode", req.Postcode, &user.Postcode},
+	}
+	for _, r := range required {
+		if r.value == nil {
+			continue
+		}
+		if strings.TrimSpace(*r.value) == "" {
+			validationErr.Add(r.field, r.label+" must not be empty")
+		}
+		*r.target = strings.TrimSpace(*r.value)
+	}
+
+	if req.AddressLine2 != nil {
+		user.AddressLine2 = strings.TrimSpace(*req.AddressLine2)
+	}
+	if req.Region != nil {
+		user.Region = strings.TrimSpace(*req.Region)
+	}
+	if req.Country != nil {
+		country, ok := utils.NormalizeCountry(*req.Country)
+		if !ok {
+			validationErr.Add("country", "country must be an ISO 3166-1 alpha-2 code, e.g. GB")
+		}
+		user.Country = country
+	}
+
+	if validationErr.HasErrors() {
+		return validationErr
+	}
+	return nil
+}
+
+// recordProfileUpdate writes an audit event listing the profile fields that
+// changed from before to after, each as "old -> new". Values other than the
+// country are personal data, so they're recorded as fingerprints support can
+// compare against a value the user gives them. Failures are logged.
+func (s *AuthService) recordProfileUpdate(ctx context.Context, before, after *models.User) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"first_name", before.FirstName, after.FirstName},
+		{"last_name", before.LastName, after.LastName},
+		{"phone", before.Phone, after.Phone},
+		{"address_line1", before.AddressLine1, after.AddressLine1},
+		{"address_line2", before.AddressLine2, after.AddressLine2},
+		{"city", before.City, after.City},
+		{"region", before.Region, after.Region},
+		{"postcode", before.Postcode, after.Postcode},
+		{"country", before.Country, after.Country},
+	}
+
+	metadata := map[string]string{}
+	var changed []string
+	for _, f := range fields {
+		if f.before == f.after {
+			continue
+		}
+		changed = append(changed, f.name)
+		if f.name == "country" {
+			metadata[f.name] = f.before + " -> " + f.after
+		} else {
+			metadata[f.name] = profileValueFingerprint(f.before) + " -> " + profileValueFingerprint(f.after)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+	metadata["changed_fields"] = strings.Join(changed, ",")
+
+	info := requestinfo.FromContext(ctx)
+	if info.RequestID != "" {
+		metadata["request_id"] = info.RequestID
+	}
+
+	userID := after.ID
+	event := &models.AuditEvent{
+		ID:        uuid.New(),
+		UserID:    &userID,
+		EventType: models.AuditEventProfileUpdated,
+		IPAddress: info.IP,
+		UserAgent: info.UserAgent,
+		Metadata:  metadata,
+		CreatedAt: s.clock.Now().UTC(),
+	}
+
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"event_type": event.EventType,
+			"user_id":    userID,
+		}).Error("Failed to record profile update audit event")
+	}
+}
+
+// profileValueFingerprint returns a short SHA-256 fingerprint of a profile
+// value, or "(empty)" for a cleared one
+func profileValueFingerprint(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateProfile tests partial profile updates by the account holder
+func TestUpdateProfile(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	userID := uuid.New()
+	dateOfBirth := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newUser := func() *models.User {
+		return &models.User{
+			ID:           userID,
+			Email:        "john.doe@example.com",
+			Phone:        "+447700900123",
+			PasswordHash: "hashed",
+			FirstName:    "John",
+			LastName:     "Doe",
+			DateOfBirth:  dateOfBirth,
+			AddressLine1: "123 Main Street",
+			City:         "London",
+			Postcode:     "SW1A 1AA",
+			Country:      "GB",
+			KYCStatus:    "pending",
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	str := func(s string) *string { return &s }
+
+	t.Run("updates only the fields sent and returns the stored user", func(t *testing.T) {
+		stored := newUser()
+		stored.LastName = "Smith"
+		stored.Phone = "+447700900456"
+		stored.KYCStatus = "verified" // changed by a KYC decision meanwhile
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.FirstName == "John" && u.LastName == "Smith" && u.Phone == "+447700900456" &&
+				u.Email == "john.doe@example.com" && u.DateOfBirth.Equal(dateOfBirth)
+		})).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(stored, nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		caller := newUser()
+		user, err := service.UpdateProfile(context.Background(), caller, &models.UpdateProfileRequest{
+			LastName: str(" Smith "),
+			Phone:    str("+44 7700 900456"),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Smith", user.LastName)
+		assert.Equal(t, "verified", user.KYCStatus)
+		assert.Empty(t, user.PasswordHash)
+		assert.Equal(t, "Doe", caller.LastName, "the caller is left as authenticated")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid fields are rejected without writing", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		_, err := service.UpdateProfile(context.Background(), newUser(), &models.UpdateProfileRequest{
+			Phone:   str("12345"),
+			City:    str("  "),
+			Country: str("Narnia"),
+		})
+
+		validationErr := appErrors.GetValidationError(err)
+		require.NotNil(t, validationErr)
+		assert.Contains(t, validationErr.Fields, "phone")
+		assert.Contains(t, validationErr.Fields, "city")
+		assert.Contains(t, validationErr.Fields, "country")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an empty update is rejected", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		_, err := service.UpdateProfile(context.Background(), newUser(), &models.UpdateProfileRequest{})
+		assert.True(t, errors.Is(err, appErrors.ErrInvalidInput))
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an empty string clears optional fields only", func(t *testing.T) {
+		caller := newUser()
+		caller.AddressLine2 = "Flat 2"
+		caller.Region = "Greater London"
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.AddressLine2 == "" && u.Region == "Greater London" && u.City == "London"
+		})).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		_, err := service.UpdateProfile(context.Background(), caller, &models.UpdateProfileRequest{
+			AddressLine2: str(""),
+		})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+
+		_, err = service.UpdateProfile(context.Background(), caller, &models.UpdateProfileRequest{
+			City: str(""),
+		})
+		validationErr := appErrors.GetValidationError(err)
+		require.NotNil(t, validationErr)
+		assert.Equal(t, "city must not be empty", validationErr.Fields["city"])
+	})
+
+	t.Run("changed fields are audited without their values", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+
+		var event *models.AuditEvent
+		audit := new(MockAuditRepository)
+		audit.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			event = args.Get(1).(*models.AuditEvent)
+		}).Return(nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAuditRepository(audit))
+
+		_, err := service.UpdateProfile(context.Background(), newUser(), &models.UpdateProfileRequest{
+			Phone:     str("+447700900456"),
+			FirstName: str("John"), // unchanged
+			Country:   str("ie"),
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, event)
+		assert.Equal(t, models.AuditEventProfileUpdated, event.EventType)
+		assert.Equal(t, userID, *event.UserID)
+		assert.Equal(t, "country,phone", event.Metadata["changed_fields"])
+		assert.Equal(t, "GB -> IE", event.Metadata["country"])
+		assert.Equal(t, profileValueFingerprint("+447700900123")+" -> "+profileValueFingerprint("+447700900456"), event.Metadata["phone"])
+		assert.NotContains(t, event.Metadata["phone"], "7700")
+		assert.NotContains(t, event.Metadata, "first_name")
+	})
+
+	t.Run("an update changing nothing is not audited", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+		audit := new(MockAuditRepository)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAuditRepository(audit))
+
+		_, err := service.UpdateProfile(context.Background(), newUser(), &models.UpdateProfileRequest{
+			City: str("London"),
+		})
+		require.NoError(t, err)
+		audit.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a phone already in use is a conflict", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(appErrors.NewPhoneConflict("phone number already in use"))
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		_, err := service.UpdateProfile(context.Background(), newUser(), &models.UpdateProfileRequest{
+			Phone: str("+447700900999"),
+		})
+		assert.True(t, errors.Is(err, appErrors.ErrPhoneInUse))
+	})
+}
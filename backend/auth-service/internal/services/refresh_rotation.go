@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/repository"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// recordRefreshToken stores a refresh token issued at login as the first of a
+// new token family. It does nothing when rotation is disabled.
+func (s *AuthService) recordRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string, expiresAt time.Time) error {
+	if s.refreshTokenStore == nil {
+		return nil
+	}
+
+	record := s.newRefreshTokenRecord(userID, uuid.New(), refreshToken, expiresAt)
+	if err := s.refreshTokenStore.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// DefaultRefreshReuseGrace is how long after a refresh token is rotated
+// presenting it again counts as a concurrent refresh rather than reuse
+const DefaultRefreshReuseGrace = 10 * time.Second
+
+// rotateRefreshToken invalidates the presented refresh token and returns its
+// replacement, which keeps the presented token's expiry so rotation doesn't
+// extend a session. A token that was already rotated is treated as stolen
+// (see rejectRotatedRefreshToken). It returns an empty token when rotation is
+// disabled.
+func (s *AuthService) rotateRefreshToken(ctx context.Context, user *models.User, presented string, claims *utils.RegisteredTokenClaims) (string, error) {
+	if s.refreshTokenStore == nil {
+		return "", nil
+	}
+
+	record, err := s.refreshTokenStore.GetByHash(ctx, hashRefreshToken(presented))
+	if err != nil {
+		// Tokens issued before rotation was enabled aren't stored
+		if appErrors.GetAppError(err) != nil {
+			return "", appErrors.NewUnauthorized("invalid or expired refresh token")
+		}
+		return "", appErrors.NewInternalError(err, "failed to look up refresh token")
+	}
+
+	if record.RevokedAt != nil {
+		return "", appErrors.NewUnauthorized("refresh token has been revoked")
+	}
+	if record.RotatedAt != nil {
+		return "", s.rejectRotatedRefreshToken(ctx, record, *record.RotatedAt)
+	}
+
+	now := s.clock.Now()
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	// The replacement stays in the presented token's session and binding, if any
+	refreshOpts := utils.AccessTokenOptions{IssuedAt: now, SessionID: claims.SessionID, Binding: claims.Binding}
+	refreshToken, err := utils.GenerateRefreshTokenWithOptions(user.ID.String(), user.Email, refreshOpts, expiresAt.Sub(now), s.signingKeys.ForType("refresh"))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	replacement := s.newRefreshTokenRecord(user.ID, record.FamilyID, refreshToken, expiresAt)
+	if err := s.refreshTokenStore.Rotate(ctx, record.ID, replacement); err != nil {
+		// Logout or reuse detection revoked the token since the lookup
+		if errors.Is(err, repository.ErrRefreshTokenRevoked) {
+			return "", appErrors.NewUnauthorized("refresh token has been revoked")
+		}
+		// Another refresh rotated the token between the lookup and now
+		if errors.Is(err, repository.ErrRefreshTokenRotated) {
+			return "", s.rejectRotatedRefreshToken(ctx, record, s.clock.Now())
+		}
+		// e.g. a 503 when the database stays too busy to rotate
+		if appErr := appErrors.GetAppError(err); appErr != nil {
+			return "", appErr
+		}
+		return "", appErrors.NewInternalError(err, "failed to rotate refresh token")
+	}
+
+	return refreshToken, nil
+}
+
+// rejectRotatedRefreshToken handles a presented token that was rotated at
+// rotatedAt. Within the reuse grace period this is taken to be a concurrent
+// refresh by the same client that lost the race: it gets a plain 401 and the
+// winner's replacement keeps working. Later reuse is treated as theft and
+// revokes the whole family, so the user has to log in again.
+func (s *AuthService) rejectRotatedRefreshToken(ctx context.Context, record *models.RefreshTokenRecord, rotatedAt time.Time) error {
+	if s.clock.Now().Sub(rotatedAt) < s.refreshReuseGrace {
+		return appErrors.NewUnauthorized("refresh token was already used by a concurrent refresh")
+	}
+	return s.revokeRefreshTokenFamily(ctx, record)
+}
+
+// revokeRefreshTokenFamily handles reuse of a rotated refresh token by revoking
+// every token in its family, and returns the error to send the client
+func (s *AuthService) revokeRefreshTokenFamily(ctx context.Context, record *models.RefreshTokenRecord) error {
+	s.logger.WithFields(logrus.Fields{
+		"user_id":   record.UserID,
+		"family_id": record.FamilyID,
+	}).Warn("Rotated refresh token reused; revoking token family")
+	refreshReuseDetections.Inc()
+
+	if err := s.refreshTokenStore.RevokeFamily(ctx, record.FamilyID); err != nil {
+		return appErrors.NewInternalError(err, "failed to revoke refresh tokens")
+	}
+
+	s.recordRefreshTokenReuse(ctx, record)
+	return appErrors.NewUnauthorized("refresh token reuse detected; please log in again")
+}
+
+// recordRefreshTokenReuse writes a refresh token reuse audit event; failures are logged
+func (s *AuthService) recordRefreshTokenReuse(ctx context.Context, record *models.RefreshTokenRecord) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	info := requestinfo.FromContext(ctx)
+
+	event := &models.AuditEvent{
+		ID:        uuid.New(),
+		UserID:    &record.UserID,
+		EventType: models.AuditEventRefreshTokenReuse,
+		IPAddress: info.IP,
+		UserAgent: info.UserAgent,
+		Metadata:  map[string]string{"family_id": record.FamilyID.String()},
+		CreatedAt: s.clock.Now().UTC(),
+	}
+	if info.RequestID != "" {
+		event.Metadata["request_id"] = info.RequestID
+	}
+
+	s.enrichWithGeo(event)
+
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"event_type": event.EventType,
+			"user_id":    record.UserID,
+		}).Error("Failed to record refresh token reuse audit event")
+	}
+}
+
+// newRefreshTokenRecord builds the stored form of a refresh token in a family
+func (s *AuthService) newRefreshTokenRecord(userID, familyID uuid.UUID, refreshToken string, expiresAt time.Time) *models.RefreshTokenRecord {
+	return &models.RefreshTokenRecord{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt.UTC(),
+		CreatedAt: s.clock.Now().UTC(),
+	}
+}
+
+// hashRefreshToken returns the hex SHA-256 of a refresh token. Refresh tokens
+// are long and random, so an unsalted hash is enough to keep stored values
+// from being usable as tokens.
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/repository"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRefreshTokenRepository is an in-memory RefreshTokenRepository
+type memoryRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*models.RefreshTokenRecord // by hash
+
+	// beforeRotate, if set, runs before Rotate checks the token
+	beforeRotate func(id uuid.UUID)
+
+	// now, if set, replaces time.Now for rotation and revocation times
+	now func() time.Time
+}
+
+func newMemoryRefreshTokenRepository() *memoryRefreshTokenRepository {
+	return &memoryRefreshTokenRepository{tokens: map[string]*models.RefreshTokenRecord{}}
+}
+
+func (r *memoryRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshTokenRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *token
+	r.tokens[token.TokenHash] = &stored
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshTokenRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, appErrors.NewNotFound("refresh token not found")
+	}
+	found := *token
+	return &found, nil
+}
+
+func (r *memoryRefreshTokenRepository) timeNow() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+func (r *memoryRefreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, replacement *models.RefreshTokenRecord) error {
+	if r.beforeRotate != nil {
+		r.beforeRotate(id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ID != id {
+			continue
+		}
+		if token.RevokedAt != nil {
+			return repository.ErrRefreshTokenRevoked
+		}
+		if token.RotatedAt != nil {
+			return repository.ErrRefreshTokenRotated
+		}
+		now := r.timeNow()
+		token.RotatedAt = &now
+		stored := *replacement
+		r.tokens[replacement.TokenHash] = &stored
+		return nil
+	}
+	return repository.ErrRefreshTokenRotated
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.timeNow()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// TestRefreshTokenRotation tests that refresh tokens are replaced on every
+// refresh and that reusing a replaced token after the grace period revokes
+// the whole family
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	newUser := func() *models.User {
+		return &models.User{
+			ID:           userID,
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	fakeClock := clock.NewFake(time.Now())
+
+	newService := func(store *memoryRefreshTokenRepository, opts ...Option) *AuthService {
+		mockRepo := new(MockUserRepository)
+		// Login clears the password hash on the returned user, so restore it each call
+		loginUser := newUser()
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Run(func(mock.Arguments) {
+			loginUser.PasswordHash = passwordHash
+		}).Return(loginUser, nil)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+		store.now = fakeClock.Now
+		opts = append(opts, WithRefreshTokenRotation(store), WithClock(fakeClock))
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, opts...)
+	}
+
+	login := func(t *testing.T, service *AuthService) string {
+		response, err := service.Login(ctx, "john.doe@example.com", password)
+		require.NoError(t, err)
+		require.NotEmpty(t, response.RefreshToken)
+		return response.RefreshToken
+	}
+
+	t.Run("refresh returns a new refresh token", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store)
+		refreshToken := login(t, service)
+
+		response, err := service.RefreshToken(ctx, refreshToken)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+		require.NotEmpty(t, response.RefreshToken)
+		assert.NotEqual(t, refreshToken, response.RefreshToken)
+
+		// The replacement is in the same family and keeps the original expiry
+		original, err := store.GetByHash(ctx, hashRefreshToken(refreshToken))
+		require.NoError(t, err)
+		assert.NotNil(t, original.RotatedAt)
+		replacement, err := store.GetByHash(ctx, hashRefreshToken(response.RefreshToken))
+		require.NoError(t, err)
+		assert.Equal(t, original.FamilyID, replacement.FamilyID)
+		assert.Equal(t, userID, replacement.UserID)
+		assert.WithinDuration(t, original.ExpiresAt, replacement.ExpiresAt, time.Second)
+
+		// The replacement can itself be rotated
+		next, err := service.RefreshToken(ctx, response.RefreshToken)
+		require.NoError(t, err)
+		assert.NotEmpty(t, next.RefreshToken)
+	})
+
+	t.Run("only hashes are stored", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store)
+		refreshToken := login(t, service)
+
+		for hash := range store.tokens {
+			assert.NotContains(t, refreshToken, hash)
+			assert.Len(t, hash, 64)
+		}
+	})
+
+	t.Run("reusing a rotated token revokes the family", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		audit := new(MockAuditRepository)
+		audit.On("Create", mock.Anything, mock.MatchedBy(func(event *models.AuditEvent) bool {
+			return event.EventType == models.AuditEventRefreshTokenReuse
+		})).Return(nil).Once()
+		audit.On("Create", mock.Anything, mock.Anything).Return(nil)
+		service := newService(store, WithAuditRepository(audit))
+
+		refreshToken := login(t, service)
+		otherLogin := login(t, service)
+
+		rotated, err := service.RefreshToken(ctx, refreshToken)
+		require.NoError(t, err)
+		detectionsBefore := testutil.ToFloat64(refreshReuseDetections)
+
+		// The old token is presented again after the grace period, e.g. by an
+		// attacker who stole it
+		fakeClock.Advance(DefaultRefreshReuseGrace)
+		_, err = service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+		assert.Contains(t, err.Error(), "reuse detected")
+		assert.Equal(t, detectionsBefore+1, testutil.ToFloat64(refreshReuseDetections))
+
+		// The legitimate client's replacement is revoked too, forcing re-login
+		_, err = service.RefreshToken(ctx, rotated.RefreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+
+		// Other logins are unaffected
+		_, err = service.RefreshToken(ctx, otherLogin)
+		assert.NoError(t, err)
+
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("reuse within the grace period does not revoke the family", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store)
+		refreshToken := login(t, service)
+
+		rotated, err := service.RefreshToken(ctx, refreshToken)
+		require.NoError(t, err)
+		detectionsBefore := testutil.ToFloat64(refreshReuseDetections)
+
+		fakeClock.Advance(DefaultRefreshReuseGrace - time.Second)
+		_, err = service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+		assert.Contains(t, err.Error(), "concurrent refresh")
+		assert.Equal(t, detectionsBefore, testutil.ToFloat64(refreshReuseDetections))
+
+		// The winner's replacement keeps working
+		_, err = service.RefreshToken(ctx, rotated.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("losing a concurrent rotation is a plain 401", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store)
+		refreshToken := login(t, service)
+
+		// Another refresh rotates the token between lookup and rotation
+		store.beforeRotate = func(id uuid.UUID) {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			now := fakeClock.Now()
+			store.tokens[hashRefreshToken(refreshToken)].RotatedAt = &now
+		}
+
+		_, err := service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "concurrent refresh")
+
+		record, err := store.GetByHash(ctx, hashRefreshToken(refreshToken))
+		require.NoError(t, err)
+		assert.Nil(t, record.RevokedAt)
+	})
+
+	t.Run("losing a concurrent rotation without a grace period counts as reuse", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store, WithRefreshReuseGrace(0))
+		refreshToken := login(t, service)
+
+		store.beforeRotate = func(id uuid.UUID) {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			now := fakeClock.Now()
+			store.tokens[hashRefreshToken(refreshToken)].RotatedAt = &now
+		}
+
+		_, err := service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reuse detected")
+
+		record, err := store.GetByHash(ctx, hashRefreshToken(refreshToken))
+		require.NoError(t, err)
+		assert.NotNil(t, record.RevokedAt)
+	})
+
+	t.Run("a token revoked during rotation is not treated as reuse", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store)
+		refreshToken := login(t, service)
+		detectionsBefore := testutil.ToFloat64(refreshReuseDetections)
+
+		// A logout revokes the token between lookup and rotation
+		store.beforeRotate = func(id uuid.UUID) {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			now := fakeClock.Now()
+			store.tokens[hashRefreshToken(refreshToken)].RevokedAt = &now
+		}
+
+		_, err := service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Equal(t, "refresh token has been revoked", appErrors.GetAppError(err).Message)
+		assert.Equal(t, detectionsBefore, testutil.ToFloat64(refreshReuseDetections))
+	})
+
+	t.Run("concurrent refreshes with the same token", func(t *testing.T) {
+		store := newMemoryRefreshTokenRepository()
+		service := newService(store)
+		refreshToken := login(t, service)
+		detectionsBefore := testutil.ToFloat64(refreshReuseDetections)
+
+		const clients = 10
+		var wg sync.WaitGroup
+		responses := make([]*models.RefreshTokenResponse, clients)
+		errs := make([]error, clients)
+		for i := 0; i < clients; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				responses[i], errs[i] = service.RefreshToken(ctx, refreshToken)
+			}(i)
+		}
+		wg.Wait()
+
+		// Exactly one wins; the rest get a 401 without revoking the family
+		var winner *models.RefreshTokenResponse
+		for i, err := range errs {
+			if err == nil {
+				require.Nil(t, winner, "more than one refresh succeeded")
+				winner = responses[i]
+				continue
+			}
+			assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+			assert.Contains(t, err.Error(), "concurrent refresh")
+		}
+		require.NotNil(t, winner)
+		assert.Equal(t, detectionsBefore, testutil.ToFloat64(refreshReuseDetections))
+
+		_, err := service.RefreshToken(ctx, winner.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unstored tokens are rejected", func(t *testing.T) {
+		service := newService(newMemoryRefreshTokenRepository())
+		refreshToken, err := utils.GenerateRefreshToken(userID.String(), "john.doe@example.com", time.Hour, jwtSecret)
+		require.NoError(t, err)
+
+		_, err = service.RefreshToken(ctx, refreshToken)
+		require.Error(t, err)
+		assert.Equal(t, appErrors.CodeUnauthorized, appErrors.GetAppError(err).Code)
+	})
+
+	t.Run("disabled rotation keeps the refresh token", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+		refreshToken, err := utils.GenerateRefreshToken(userID.String(), "john.doe@example.com", time.Hour, jwtSecret)
+		require.NoError(t, err)
+
+		response, err := service.RefreshToken(ctx, refreshToken)
+		require.NoError(t, err)
+		assert.Empty(t, response.RefreshToken)
+
+		// The same token keeps working
+		_, err = service.RefreshToken(ctx, refreshToken)
+		assert.NoError(t, err)
+	})
+}
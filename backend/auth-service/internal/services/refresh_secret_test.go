@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRefreshTokenSecret tests signing refresh tokens with a separate secret
+func TestRefreshTokenSecret(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	refreshSecret := "test-refresh-secret-at-least-32-chars-long"
+	password := "SecurePass123!"
+
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	newUser := func() *models.User {
+		return &models.User{
+			ID:           userID,
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(newUser(), nil)
+	mockRepo.On("GetByID", mock.Anything, userID).Return(newUser(), nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithRefreshTokenSecret(refreshSecret))
+
+	response, err := service.Login(context.Background(), "john.doe@example.com", password)
+	require.NoError(t, err)
+
+	t.Run("tokens are signed with their own secret", func(t *testing.T) {
+		_, err := utils.ValidateToken(response.AccessToken, jwtSecret)
+		assert.NoError(t, err)
+		_, err = utils.ValidateToken(response.AccessToken, refreshSecret)
+		assert.Error(t, err)
+
+		_, err = utils.ValidateToken(response.RefreshToken, refreshSecret)
+		assert.NoError(t, err)
+		_, err = utils.ValidateToken(response.RefreshToken, jwtSecret)
+		assert.Error(t, err)
+	})
+
+	t.Run("refresh accepts only the refresh secret", func(t *testing.T) {
+		refreshed, err := service.RefreshToken(context.Background(), response.RefreshToken)
+		require.NoError(t, err)
+		assert.NotEmpty(t, refreshed.AccessToken)
+
+		forged, err := utils.GenerateRefreshToken(userID.String(), "john.doe@example.com", time.Hour, jwtSecret)
+		require.NoError(t, err)
+		_, err = service.RefreshToken(context.Background(), forged)
+		assert.Error(t, err)
+	})
+
+	t.Run("refresh token is not an access token", func(t *testing.T) {
+		_, err := service.ValidateAccessToken(context.Background(), response.RefreshToken)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRefreshTokensDisabled tests that login and refresh behave correctly without refresh tokens
+func TestRefreshTokensDisabled(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	// Login clears the password hash on the returned user, so each case gets its own
+	newUser := func() *models.User {
+		return &models.User{
+			ID:           uuid.New(),
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+	}
+
+	t.Run("login omits the refresh token", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(newUser(), nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithRefreshTokens(false))
+
+		response, err := service.Login(context.Background(), "john.doe@example.com", password)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+		assert.Empty(t, response.RefreshToken)
+
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "refresh_token")
+	})
+
+	t.Run("refresh is disabled", func(t *testing.T) {
+		user := newUser()
+		refreshToken, err := utils.GenerateRefreshToken(user.ID.String(), user.Email, 7*24*time.Hour, jwtSecret)
+		require.NoError(t, err)
+
+		mockRepo := new(MockUserRepository)
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithRefreshTokens(false))
+
+		response, err := service.RefreshToken(context.Background(), refreshToken)
+		require.Error(t, err)
+		assert.Nil(t, response)
+
+		appErr := appErrors.GetAppError(err)
+		require.NotNil(t, appErr)
+		assert.Equal(t, 404, appErr.StatusCode)
+		assert.Contains(t, appErr.Message, "disabled")
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("login issues a refresh token by default", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(newUser(), nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		response, err := service.Login(context.Background(), "john.doe@example.com", password)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.RefreshToken)
+	})
+}
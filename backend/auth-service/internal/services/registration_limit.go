@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+)
+
+// registrationCounterTTL outlives a calendar day so the key survives until the day rolls over
+const registrationCounterTTL = 25 * time.Hour
+
+// Counter counts events per key within an expiry window (see internal/cache)
+type Counter interface {
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// checkRegistrationLimit enforces the daily cap on registrations per client IP.
+// Counts reset at midnight UTC. Counter failures are logged and allow the
+// registration, so an unavailable store doesn't block sign-ups.
+func (s *AuthService) checkRegistrationLimit(ctx context.Context) error {
+	if s.registrationCounter == nil || s.registrationsPerIPPerDay <= 0 {
+		return nil
+	}
+
+	ip := requestinfo.FromContext(ctx).IP
+	if ip == "" {
+		return nil
+	}
+
+	key := "registrations:" + ip + ":" + s.clock.Now().UTC().Format("2006-01-02")
+	count, err := s.registrationCounter.Increment(ctx, key, registrationCounterTTL)
+	if err != nil {
+		s.logger.WithError(err).WithField("ip", ip).Warn("Registration limit check failed")
+		return nil
+	}
+
+	if count > int64(s.registrationsPerIPPerDay) {
+		return appErrors.NewTooManyRequests("too many registrations from this address today, please try again tomorrow")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/cache"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// failingCounter is a Counter whose store is unavailable
+type failingCounter struct{}
+
+func (failingCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+// newRegistrationLimitService creates a service whose registrations always succeed at the repository
+func newRegistrationLimitService(counter Counter, perDay int, opts ...Option) *AuthService {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, appErrors.NewNotFound("user not found"))
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+
+	opts = append(opts, WithRegistrationLimit(counter, perDay))
+	return NewAuthService(mockRepo, "test-secret-key-at-least-32-chars-long-for-security", 15*time.Minute, 7*24*time.Hour, opts...)
+}
+
+// TestRegistrationLimit tests the daily registrations-per-IP cap
+func TestRegistrationLimit(t *testing.T) {
+	service := newRegistrationLimitService(cache.NewMemoryCounter(), 3)
+	fakeClock := clock.NewFake(time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC))
+	service.clock = fakeClock
+
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "203.0.113.7"})
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Register(ctx, newNameTestRequest("John", "Doe"))
+		require.NoError(t, err, "registration %d should be allowed", i+1)
+	}
+
+	// The next registration from the same IP on the same day is rejected
+	_, err := service.Register(ctx, newNameTestRequest("John", "Doe"))
+	require.Error(t, err)
+	appErr := appErrors.GetAppError(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, 429, appErr.StatusCode)
+	assert.Equal(t, appErrors.CodeRateLimited, appErr.Code)
+
+	// Other addresses have their own allowance
+	otherCtx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "198.51.100.20"})
+	_, err = service.Register(otherCtx, newNameTestRequest("John", "Doe"))
+	require.NoError(t, err)
+
+	// A new day resets the count
+	fakeClock.Advance(2 * time.Hour)
+	_, err = service.Register(ctx, newNameTestRequest("John", "Doe"))
+	require.NoError(t, err)
+}
+
+// TestRegistrationLimitSkipsHashing tests that registrations over the cap are
+// rejected before the password is hashed
+func TestRegistrationLimitSkipsHashing(t *testing.T) {
+	scheme := &countingScheme{BcryptScheme: utils.NewBcryptScheme(bcrypt.MinCost)}
+	service := newRegistrationLimitService(cache.NewMemoryCounter(), 1, WithPasswordHashing(utils.NewHashRegistry(scheme)))
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "203.0.113.7"})
+
+	_, err := service.Register(ctx, newNameTestRequest("John", "Doe"))
+	require.NoError(t, err)
+	require.Equal(t, 1, scheme.hashes)
+
+	_, err = service.Register(ctx, newNameTestRequest("John", "Doe"))
+	require.Error(t, err)
+	assert.Equal(t, appErrors.CodeRateLimited, appErrors.GetAppError(err).Code)
+	assert.Equal(t, 1, scheme.hashes, "rate limited registration should not be hashed")
+}
+
+// TestRegistrationLimitDisabled tests that a zero limit allows unlimited registrations
+func TestRegistrationLimitDisabled(t *testing.T) {
+	service := newRegistrationLimitService(cache.NewMemoryCounter(), 0)
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "203.0.113.7"})
+
+	for i := 0; i < 5; i++ {
+		_, err := service.Register(ctx, newNameTestRequest("John", "Doe"))
+		require.NoError(t, err)
+	}
+}
+
+// TestRegistrationLimitCounterFailure tests that an unavailable store does not block registration
+func TestRegistrationLimitCounterFailure(t *testing.T) {
+	service := newRegistrationLimitService(failingCounter{}, 1)
+	ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "203.0.113.7"})
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Register(ctx, newNameTestRequest("John", "Doe"))
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestConcurrentDuplicateRegistration tests that when two registrations for
+// the same email both pass the existence check, the one losing the insert
+// race gets a 409 conflict rather than an internal error
+func TestConcurrentDuplicateRegistration(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+
+	// Hold both registrations after their existence check until each has made it
+	var checked sync.WaitGroup
+	checked.Add(2)
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "race@example.com").
+		Return(nil, appErrors.NewNotFound("user not found")).
+		Run(func(mock.Arguments) {
+			checked.Done()
+			checked.Wait()
+		})
+
+	// Like the unique email constraint, only the first insert succeeds
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil).Once()
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).
+		Return(appErrors.NewConflict("user with this email already exists"))
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+		WithPasswordHashing(utils.NewHashRegistry(utils.NewBcryptScheme(bcrypt.MinCost))))
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = service.Register(context.Background(), newTimingTestRequest("race@example.com"))
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded, conflicts := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case appErrors.GetStatusCode(err) == http.StatusConflict:
+			conflicts++
+			assert.Equal(t, "user with this email already exists", appErrors.GetAppError(err).Message)
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, conflicts)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// countingScheme is a bcrypt scheme that counts hashes
+type countingScheme struct {
+	*utils.BcryptScheme
+	hashes int
+}
+
+func (s *countingScheme) Hash(password string) (string, error) {
+	s.hashes++
+	return s.BcryptScheme.Hash(password)
+}
+
+// newTimingTestRequest returns a valid registration request for email
+func newTimingTestRequest(email string) *models.RegisterRequest {
+	return &models.RegisterRequest{
+		Email:        email,
+		Phone:        "+447700900123",
+		Password:     "SecurePass123!",
+		FirstName:    "John",
+		LastName:     "Doe",
+		DateOfBirth:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		AddressLine1: "123 Main St",
+		City:         "London",
+		Postcode:     "SW1A 1AA",
+		Country:      "UK",
+	}
+}
+
+// newTimingTestService returns a service where existing@example.com is
+// registered and any other email is new
+func newTimingTestService(scheme utils.HashScheme) *AuthService {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "existing@example.com").Return(&models.User{ID: uuid.New(), Email: "existing@example.com"}, nil)
+	mockRepo.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, appErrors.NewNotFound("user not found"))
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+
+	return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+		WithPasswordHashing(utils.NewHashRegistry(scheme)))
+}
+
+// TestRegisterHashesForExistingEmail tests that the duplicate-email path does
+// the same slow hashing as the new-email path, so timing doesn't enumerate accounts
+func TestRegisterHashesForExistingEmail(t *testing.T) {
+	scheme := &countingScheme{BcryptScheme: utils.NewBcryptScheme(bcrypt.MinCost)}
+	service := newTimingTestService(scheme)
+
+	_, err := service.Register(context.Background(), newTimingTestRequest("new@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, scheme.hashes)
+
+	_, err = service.Register(context.Background(), newTimingTestRequest("existing@example.com"))
+	require.Error(t, err)
+	assert.Equal(t, 2, scheme.hashes, "duplicate email should be hashed too")
+}
+
+// TestRegisterTimingIsComparable tests that duplicate and new emails take a similar time
+func TestRegisterTimingIsComparable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing comparison skipped in short mode")
+	}
+
+	service := newTimingTestService(utils.NewBcryptScheme(bcrypt.DefaultCost))
+
+	timeRegister := func(email string) time.Duration {
+		start := time.Now()
+		_, _ = service.Register(context.Background(), newTimingTestRequest(email))
+		return time.Since(start)
+	}
+
+	newEmail := timeRegister("new@example.com")
+	existingEmail := timeRegister("existing@example.com")
+
+	// bcrypt dominates both paths; without it the duplicate path is orders of magnitude faster
+	assert.Greater(t, existingEmail, newEmail/2)
+	assert.Greater(t, newEmail, existingEmail/2)
+}
+
+// BenchmarkRegisterNewEmail benchmarks registering a new email
+func BenchmarkRegisterNewEmail(b *testing.B) {
+	service := newTimingTestService(utils.NewBcryptScheme(bcrypt.DefaultCost))
+	req := newTimingTestRequest("new@example.com")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = service.Register(context.Background(), req)
+	}
+}
+
+// BenchmarkRegisterExistingEmail benchmarks registering an existing email;
+// it should be comparable to BenchmarkRegisterNewEmail
+func BenchmarkRegisterExistingEmail(b *testing.B) {
+	service := newTimingTestService(utils.NewBcryptScheme(bcrypt.DefaultCost))
+	req := newTimingTestRequest("existing@example.com")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = service.Register(context.Background(), req)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/protobankbankc/auth-service/internal/models"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterReportsAllFieldErrors tests that every invalid field is
+// reported in one validation error instead of just the first
+func TestRegisterReportsAllFieldErrors(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	mockRepo := new(MockUserRepository)
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+	t.Run("invalid fields", func(t *testing.T) {
+		req := newNameTestRequest("J0hn", "Doe")
+		req.Email = "not-an-email"
+		req.Password = "weak"
+		req.DateOfBirth = time.Now().AddDate(-15, 0, 0)
+		req.Country = "Narnia"
+
+		underageBefore := testutil.ToFloat64(registrationOutcomes.WithLabelValues(registrationUnderage))
+
+		user, err := service.Register(context.Background(), req)
+		require.Error(t, err)
+		assert.Nil(t, user)
+
+		validationErr := appErrors.GetValidationError(err)
+		require.NotNil(t, validationErr)
+		assert.Equal(t, http.StatusBadRequest, validationErr.StatusCode)
+		assert.Equal(t, "validation failed", validationErr.Message)
+		assert.Equal(t, map[string]string{
+			"date_of_birth": "you must be at least 18 years old to register",
+			"first_name":    "first name contains invalid characters",
+			"email":         "invalid email format",
+			"country":       "country must be an ISO 3166-1 alpha-2 code, e.g. GB",
+			"password": "password must be at least 8 characters long; " +
+				"password must contain at least one uppercase letter; " +
+				"password must contain at least one number; " +
+				"password must contain at least one special character",
+		}, validationErr.Fields)
+
+		// The errors behind the fields are still in the chain
+		assert.ErrorIs(t, err, appErrors.ErrWeakPassword)
+		assert.Equal(t, appErrors.CodeValidationFailed, appErrors.GetAppError(err).Code)
+
+		// The outcome is the first check that failed
+		assert.Equal(t, underageBefore+1, testutil.ToFloat64(registrationOutcomes.WithLabelValues(registrationUnderage)))
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		_, err := service.Register(context.Background(), &models.RegisterRequest{
+			Email:    "john.doe@example.com",
+			Password: "SecurePass123!",
+		})
+		require.Error(t, err)
+
+		validationErr := appErrors.GetValidationError(err)
+		require.NotNil(t, validationErr)
+		assert.Equal(t, map[string]string{
+			"first_name":    "first name is required",
+			"last_name":     "last name is required",
+			"date_of_birth": "date of birth is required",
+			"address_line1": "address line 1 is required",
+			"city":          "city is required",
+			"postcode":      "postcode is required",
+			"country":       "country is required",
+		}, validationErr.Fields)
+	})
+}
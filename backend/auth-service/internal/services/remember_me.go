@@ -0,0 +1,19 @@
+package services
+
+import (
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// refreshDurationFor returns the refresh token lifetime for a login and
+// whether the session is persistent. Remember me only applies when a
+// remember-me duration is configured; otherwise the default is used.
+// Precedence: a per-device expiry, when added, should cap this value rather
+// than be overridden by it.
+func (s *AuthService) refreshDurationFor(opts models.LoginOptions) (time.Duration, bool) {
+	if opts.RememberMe && s.rememberMeDuration > 0 {
+		return s.rememberMeDuration, true
+	}
+	return s.refreshTokenDuration, false
+}
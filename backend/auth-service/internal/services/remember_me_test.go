@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRememberMe tests that remember me issues a longer-lived refresh token
+func TestRememberMe(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	defaultExpiry := 7 * 24 * time.Hour
+	rememberMeExpiry := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name           string
+		rememberMe     bool
+		serviceOption  Option
+		wantExpiry     time.Duration
+		wantPersistent bool
+	}{
+		{
+			name:           "remember me yields the longer refresh expiry",
+			rememberMe:     true,
+			serviceOption:  WithRememberMe(rememberMeExpiry),
+			wantExpiry:     rememberMeExpiry,
+			wantPersistent: true,
+		},
+		{
+			name:          "omitting remember me yields the default expiry",
+			rememberMe:    false,
+			serviceOption: WithRememberMe(rememberMeExpiry),
+			wantExpiry:    defaultExpiry,
+		},
+		{
+			name:          "remember me is ignored when not configured",
+			rememberMe:    true,
+			serviceOption: WithRememberMe(0),
+			wantExpiry:    defaultExpiry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{
+				ID:           uuid.New(),
+				Email:        "john.doe@example.com",
+				PasswordHash: passwordHash,
+				Status:       models.UserStatusActive,
+			}
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+
+			service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, defaultExpiry, tt.serviceOption)
+
+			before := time.Now()
+			response, err := service.LoginWithOptions(context.Background(), "john.doe@example.com", password,
+				models.LoginOptions{RememberMe: tt.rememberMe})
+			require.NoError(t, err)
+
+			assert.Equal(t, int(tt.wantExpiry.Seconds()), response.RefreshExpiresIn)
+			assert.Equal(t, tt.wantPersistent, response.Persistent)
+
+			expiry, err := utils.GetTokenExpiry(response.RefreshToken, jwtSecret)
+			require.NoError(t, err)
+			assert.WithinDuration(t, before.Add(tt.wantExpiry), *expiry, 5*time.Second)
+		})
+	}
+}
+
+// TestRememberMeAudit tests that persistent sessions are recorded in the login audit
+func TestRememberMeAudit(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john.doe@example.com",
+		PasswordHash: passwordHash,
+		Status:       models.UserStatusActive,
+	}
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+
+	var recorded *models.AuditEvent
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.AuditEvent")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(*models.AuditEvent) }).
+		Return(nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+		WithRememberMe(30*24*time.Hour), WithAuditRepository(auditRepo))
+
+	_, err = service.LoginWithOptions(context.Background(), "john.doe@example.com", password,
+		models.LoginOptions{RememberMe: true})
+	require.NoError(t, err)
+
+	require.NotNil(t, recorded)
+	assert.Equal(t, models.AuditEventLoginSuccess, recorded.EventType)
+	assert.Equal(t, loginSessionPersistent, recorded.Metadata["session"])
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/protobankbankc/auth-service/internal/models"
+)
+
+// SecuritySummary reports the authenticated caller's account security
+// posture: their last login and failed attempts since from the audit trail,
+// and their active session count. Without an audit repository the login
+// fields are empty, and without session tracking the session count is null.
+func (s *AuthService) SecuritySummary(ctx context.Context, user *models.User) (*models.SecuritySummary, error) {
+	summary := &models.SecuritySummary{ClosureRequestedAt: user.ClosureRequestedAt}
+
+	if s.auditRepo != nil {
+		logins, err := s.auditRepo.SummarizeLogins(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize logins: %w", err)
+		}
+		summary.LastLogin = logins.LastLogin
+		summary.FailedLoginsSinceLastLogin = logins.FailedSinceLastLogin
+	}
+
+	if s.sessions != nil {
+		count, err := s.sessions.CountActiveByUser(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count sessions: %w", err)
+		}
+		summary.ActiveSessions = &count
+	}
+
+	return summary, nil
+}
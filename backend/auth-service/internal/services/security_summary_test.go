@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSecuritySummary tests assembling the caller's security posture from the
+// login audit trail
+func TestSecuritySummary(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+
+	t.Run("reports the latest login and failures since", func(t *testing.T) {
+		logins := &models.LoginSummary{
+			LastLogin:            &models.LoginRecord{At: now.Add(-time.Hour), IPAddress: "203.0.113.7", Country: "GB"},
+			FailedSinceLastLogin: 2,
+		}
+
+		mockRepo := new(MockUserRepository)
+		auditRepo := new(MockAuditRepository)
+		auditRepo.On("SummarizeLogins", mock.Anything, user.ID).Return(logins, nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithAuditRepository(auditRepo))
+
+		summary, err := service.SecuritySummary(context.Background(), user)
+		require.NoError(t, err)
+
+		require.NotNil(t, summary.LastLogin)
+		assert.Equal(t, now.Add(-time.Hour), summary.LastLogin.At)
+		assert.Equal(t, "203.0.113.7", summary.LastLogin.IPAddress)
+		assert.Equal(t, "GB", summary.LastLogin.Country)
+		assert.Equal(t, 2, summary.FailedLoginsSinceLastLogin)
+		assert.Nil(t, summary.ActiveSessions)
+		assert.Nil(t, summary.ClosureRequestedAt)
+	})
+
+	t.Run("counts active sessions", func(t *testing.T) {
+		sessions := newMemorySessionRepository()
+		active := func() *models.Session {
+			return &models.Session{ID: uuid.New(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		}
+		revokedAt := time.Now()
+		revoked := active()
+		revoked.RevokedAt = &revokedAt
+		expired := active()
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		other := active()
+		other.UserID = uuid.New()
+		for _, session := range []*models.Session{active(), active(), revoked, expired, other} {
+			require.NoError(t, sessions.Create(context.Background(), session))
+		}
+
+		mockRepo := new(MockUserRepository)
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithSessions(sessions))
+
+		summary, err := service.SecuritySummary(context.Background(), user)
+		require.NoError(t, err)
+
+		require.NotNil(t, summary.ActiveSessions)
+		assert.Equal(t, 2, *summary.ActiveSessions)
+		assert.Nil(t, summary.LastLogin)
+	})
+
+	t.Run("without an audit repository the login fields are empty", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		summary, err := service.SecuritySummary(context.Background(), user)
+		require.NoError(t, err)
+		assert.Nil(t, summary.LastLogin)
+		assert.Zero(t, summary.FailedLoginsSinceLastLogin)
+		assert.Nil(t, summary.ActiveSessions)
+	})
+
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// startSession records a session for a refresh token issued at issuedAt and
+// returns its ID for the sid claim, or "" when sessions aren't tracked
+func (s *AuthService) startSession(ctx context.Context, userID uuid.UUID, opts models.LoginOptions, issuedAt time.Time, lifetime time.Duration) (string, error) {
+	if s.sessions == nil {
+		return "", nil
+	}
+
+	info := requestinfo.FromContext(ctx)
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		DeviceID:   opts.DeviceID,
+		DeviceType: opts.DeviceType,
+		IPAddress:  info.IP,
+		UserAgent:  info.UserAgent,
+		CreatedAt:  issuedAt,
+		LastSeenAt: issuedAt,
+		ExpiresAt:  issuedAt.Add(lifetime),
+	}
+
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return "", err
+	}
+
+	return session.ID.String(), nil
+}
+
+// sessionTouchInterval is how often a session's last-seen time is written.
+// Requests in between don't write, so an idle timeout can be late by up to
+// this much.
+const sessionTouchInterval = time.Minute
+
+// checkSession rejects a token whose session was revoked, has been idle
+// longer than the idle timeout or belongs to another user, and records the
+// session as seen. Tokens issued without a session (before tracking was
+// enabled) are accepted.
+func (s *AuthService) checkSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	if s.sessions == nil || sessionID == "" {
+		return nil
+	}
+
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return appErrors.NewUnauthorized("invalid session in token")
+	}
+
+	session, err := s.sessions.GetByID(ctx, id)
+	if err != nil {
+		if appErrors.IsAppError(err) {
+			return appErrors.NewUnauthorized("session has been revoked")
+		}
+		return err
+	}
+
+	now := s.clock.Now().UTC()
+	if session.UserID != userID || !session.IsActive(now) {
+		return appErrors.NewUnauthorized("session has been revoked")
+	}
+
+	idle := now.Sub(session.LastSeenAt)
+	if s.sessionIdleTimeout > 0 && idle > s.sessionIdleTimeout {
+		return appErrors.NewUnauthorized("session expired after inactivity; please log in again")
+	}
+
+	if idle >= sessionTouchInterval {
+		if err := s.sessions.Touch(ctx, id, now); err != nil {
+			s.logger.WithError(err).WithField("session_id", id).Warn("Failed to record session activity")
+		}
+	}
+
+	return nil
+}
+
+// ListSessions returns the authenticated caller's active sessions, flagging
+// the one their access token (claims) belongs to as current
+func (s *AuthService) ListSessions(ctx context.Context, user *models.User, claims *utils.RegisteredTokenClaims) ([]*models.Session, error) {
+	if err := s.requireSessions(); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessions.ListActiveByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.ID.String() == claims.SessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the authenticated caller's sessions, so its
+// refresh and access tokens can no longer be used
+func (s *AuthService) RevokeSession(ctx context.Context, user *models.User, sessionID uuid.UUID) error {
+	if err := s.requireSessions(); err != nil {
+		return err
+	}
+
+	if err := s.sessions.Revoke(ctx, user.ID, sessionID); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id":    user.ID,
+		"session_id": sessionID,
+	}).Info("Session revoked")
+
+	return nil
+}
+
+// RevokeOtherSessions revokes all of the authenticated caller's sessions
+// except the one their access token (claims) belongs to, and reports how many
+// were revoked
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, user *models.User, claims *utils.RegisteredTokenClaims) (*models.SessionRevocation, error) {
+	if err := s.requireSessions(); err != nil {
+		return nil, err
+	}
+
+	// A token without a session (issued before tracking) keeps none
+	current, _ := uuid.Parse(claims.SessionID)
+
+	revoked, err := s.sessions.RevokeAllExcept(ctx, user.ID, current)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": user.ID,
+		"revoked": revoked,
+	}).Info("Other sessions revoked")
+
+	return &models.SessionRevocation{Revoked: revoked}, nil
+}
+
+// requireSessions rejects session endpoint calls when sessions aren't tracked
+func (s *AuthService) requireSessions() error {
+	if s.sessions == nil {
+		return appErrors.NewNotFound("session tracking is disabled")
+	}
+	return nil
+}
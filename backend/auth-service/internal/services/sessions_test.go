@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/clock"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySessionRepository is an in-memory SessionRepository
+type memorySessionRepository struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*models.Session
+	touches  int
+}
+
+func newMemorySessionRepository() *memorySessionRepository {
+	return &memorySessionRepository{sessions: map[uuid.UUID]*models.Session{}}
+}
+
+func (r *memorySessionRepository) Create(ctx context.Context, session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func (r *memorySessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, appErrors.NewNotFound("session not found")
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *memorySessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions := []*models.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive(time.Now()) {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt) })
+	return sessions, nil
+}
+
+func (r *memorySessionRepository) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	sessions, err := r.ListActiveByUser(ctx, userID)
+	return len(sessions), err
+}
+
+func (r *memorySessionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions := []*models.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.After(sessions[j].CreatedAt) })
+	return sessions, nil
+}
+
+func (r *memorySessionRepository) Touch(ctx context.Context, id uuid.UUID, lastSeen time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.touches++
+	if session, ok := r.sessions[id]; ok {
+		session.LastSeenAt = lastSeen
+	}
+	return nil
+}
+
+func (r *memorySessionRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return appErrors.NewNotFound("session not found")
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return nil
+}
+
+func (r *memorySessionRepository) RevokeAllExcept(ctx context.Context, userID, keepID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revoked := 0
+	now := time.Now()
+	for id, session := range r.sessions {
+		if session.UserID == userID && id != keepID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+// TestSessions tests listing and revoking the sessions created at login
+func TestSessions(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john.doe@example.com",
+		PasswordHash: passwordHash,
+		Status:       models.UserStatusActive,
+	}
+
+	newService := func(sessions *memorySessionRepository, opts ...Option) *AuthService {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+		mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+		return NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, append([]Option{WithSessions(sessions)}, opts...)...)
+	}
+
+	login := func(t *testing.T, service *AuthService, deviceID string) *models.LoginResponse {
+		user.PasswordHash = passwordHash // Login clears it on the returned user
+		ctx := requestinfo.NewContext(context.Background(), requestinfo.Info{IP: "203.0.113.7", UserAgent: "test-agent"})
+		response, err := service.LoginWithOptions(ctx, "john.doe@example.com", password,
+			models.LoginOptions{DeviceID: deviceID, DeviceType: "ios"})
+		require.NoError(t, err)
+		return response
+	}
+
+	// authenticate resolves the caller the way RequireAuth does
+	authenticate := func(t *testing.T, service *AuthService, accessToken string) (*models.User, *utils.RegisteredTokenClaims) {
+		caller, claims, err := service.ValidateAccessTokenWithClaims(context.Background(), accessToken)
+		require.NoError(t, err)
+		return caller, claims
+	}
+
+	t.Run("login creates a session flagged current in the listing", func(t *testing.T) {
+		service := newService(newMemorySessionRepository())
+		phone := login(t, service, "phone-1")
+		login(t, service, "tablet-1")
+
+		caller, claims := authenticate(t, service, phone.AccessToken)
+		sessions, err := service.ListSessions(context.Background(), caller, claims)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		current := 0
+		for _, session := range sessions {
+			assert.Equal(t, "ios", session.DeviceType)
+			assert.Equal(t, "203.0.113.7", session.IPAddress)
+			if session.Current {
+				current++
+				assert.Equal(t, "phone-1", session.DeviceID)
+			}
+		}
+		assert.Equal(t, 1, current, "exactly one session should be current")
+	})
+
+	t.Run("revoking a session invalidates its tokens", func(t *testing.T) {
+		service := newService(newMemorySessionRepository())
+		phone := login(t, service, "phone-1")
+		tablet := login(t, service, "tablet-1")
+
+		tabletClaims, err := utils.ValidateTokenWithClaims(tablet.RefreshToken, jwtSecret)
+		require.NoError(t, err)
+		require.NotEmpty(t, tabletClaims.SessionID)
+
+		// The access token carries the same sid
+		_, tabletAccessClaims := authenticate(t, service, tablet.AccessToken)
+		assert.Equal(t, tabletClaims.SessionID, tabletAccessClaims.SessionID)
+
+		caller, _ := authenticate(t, service, phone.AccessToken)
+		require.NoError(t, service.RevokeSession(context.Background(), caller, uuid.MustParse(tabletClaims.SessionID)))
+
+		_, err = service.RefreshToken(context.Background(), tablet.RefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, appErrors.GetStatusCode(err))
+
+		_, _, err = service.ValidateAccessTokenWithClaims(context.Background(), tablet.AccessToken)
+		assert.Equal(t, http.StatusUnauthorized, appErrors.GetStatusCode(err))
+
+		refreshed, err := service.RefreshToken(context.Background(), phone.RefreshToken)
+		require.NoError(t, err)
+
+		// The refreshed access token stays in the same session
+		caller, claims := authenticate(t, service, refreshed.AccessToken)
+		sessions, err := service.ListSessions(context.Background(), caller, claims)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.True(t, sessions[0].Current)
+	})
+
+	t.Run("revoking other sessions keeps the current one", func(t *testing.T) {
+		service := newService(newMemorySessionRepository())
+		phone := login(t, service, "phone-1")
+		tablet := login(t, service, "tablet-1")
+		laptop := login(t, service, "laptop-1")
+
+		caller, claims := authenticate(t, service, phone.AccessToken)
+		revocation, err := service.RevokeOtherSessions(context.Background(), caller, claims)
+		require.NoError(t, err)
+		assert.Equal(t, 2, revocation.Revoked)
+
+		for _, other := range []*models.LoginResponse{tablet, laptop} {
+			_, err := service.RefreshToken(context.Background(), other.RefreshToken)
+			assert.Error(t, err)
+		}
+		_, err = service.RefreshToken(context.Background(), phone.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a session idle past the timeout is rejected while active ones continue", func(t *testing.T) {
+		fakeClock := clock.NewFake(time.Now())
+		service := newService(newMemorySessionRepository(), WithClock(fakeClock), WithSessionIdleTimeout(30*time.Minute))
+		phone := login(t, service, "phone-1")
+		tablet := login(t, service, "tablet-1")
+
+		// The phone is used every 20 minutes; the tablet isn't
+		fakeClock.Advance(20 * time.Minute)
+		_, err := service.RefreshToken(context.Background(), phone.RefreshToken)
+		require.NoError(t, err)
+		fakeClock.Advance(20 * time.Minute)
+
+		_, err = service.RefreshToken(context.Background(), tablet.RefreshToken)
+		appErr := appErrors.GetAppError(err)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusUnauthorized, appErr.StatusCode)
+		assert.Equal(t, "session expired after inactivity; please log in again", appErr.Message)
+
+		_, err = service.RefreshToken(context.Background(), phone.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("session activity is recorded at most once a minute", func(t *testing.T) {
+		fakeClock := clock.NewFake(time.Now())
+		sessions := newMemorySessionRepository()
+		service := newService(sessions, WithClock(fakeClock))
+		phone := login(t, service, "phone-1")
+
+		authenticate(t, service, phone.AccessToken)
+		fakeClock.Advance(30 * time.Second)
+		authenticate(t, service, phone.AccessToken)
+		assert.Equal(t, 0, sessions.touches)
+
+		fakeClock.Advance(time.Minute)
+		authenticate(t, service, phone.AccessToken)
+		assert.Equal(t, 1, sessions.touches)
+	})
+
+	t.Run("another user's session can't be revoked", func(t *testing.T) {
+		sessions := newMemorySessionRepository()
+		service := newService(sessions)
+		phone := login(t, service, "phone-1")
+
+		otherSession := &models.Session{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, sessions.Create(context.Background(), otherSession))
+
+		caller, _ := authenticate(t, service, phone.AccessToken)
+		err := service.RevokeSession(context.Background(), caller, otherSession.ID)
+		assert.Equal(t, http.StatusNotFound, appErrors.GetStatusCode(err))
+	})
+
+	t.Run("session endpoints are unavailable when tracking is disabled", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+		_, err := service.ListSessions(context.Background(), user, &utils.RegisteredTokenClaims{})
+		assert.Equal(t, http.StatusNotFound, appErrors.GetStatusCode(err))
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/protobankbankc/auth-service/internal/features"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+)
+
+// TokenBindingMode selects what the tokens of a login that opts in to
+// binding are bound to
+type TokenBindingMode string
+
+const (
+	// TokenBindingNone issues unbound tokens
+	TokenBindingNone TokenBindingMode = "none"
+	// TokenBindingIP binds tokens to the client IP. This breaks sessions
+	// that change IP mid-session (e.g. mobile networks).
+	TokenBindingIP TokenBindingMode = "ip"
+	// TokenBindingUserAgent binds tokens to the client's User-Agent header.
+	// This only ties a token to a client type, not a specific device.
+	TokenBindingUserAgent TokenBindingMode = "user_agent"
+)
+
+// ParseTokenBindingMode parses a token binding mode from configuration
+func ParseTokenBindingMode(value string) (TokenBindingMode, error) {
+	switch mode := TokenBindingMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", TokenBindingNone:
+		return TokenBindingNone, nil
+	case TokenBindingIP, TokenBindingUserAgent:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid token binding mode: %q", value)
+	}
+}
+
+// issueAccessToken generates an unbound access token, with minimal claims
+// when configured. It returns the token's lifetime, which differs from the
+// configured expiry when jitter is enabled.
+func (s *AuthService) issueAccessToken(ctx context.Context, userID, email string) (string, time.Duration, error) {
+	return s.issueAccessTokenAt(ctx, userID, email, s.clock.Now(), "", "")
+}
+
+// issueAccessTokenAt is issueAccessToken with the token's iat set to issuedAt,
+// its sid to sessionID and its binding claim to binding, if any
+func (s *AuthService) issueAccessTokenAt(ctx context.Context, userID, email string, issuedAt time.Time, sessionID, binding string) (string, time.Duration, error) {
+	expiry := s.accessTokenExpiry()
+
+	opts := utils.AccessTokenOptions{
+		Binding:   binding,
+		Minimal:   features.Enabled(ctx, features.MinimalClaims, s.minimalClaims),
+		IssuedAt:  issuedAt,
+		SessionID: sessionID,
+	}
+
+	token, err := utils.GenerateAccessTokenWithKey(userID, email, opts, expiry, s.signingKeys.KeyFor("access"))
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, expiry, nil
+}
+
+// tokenIssueTime returns the current time at the one-second precision of
+// the iat and exp claims, so times reported alongside a token match it
+func (s *AuthService) tokenIssueTime() time.Time {
+	return s.clock.Now().UTC().Truncate(time.Second)
+}
+
+// tokenBindingFor returns the binding claim for the client in ctx, or ""
+// when binding is disabled or the client attribute is unknown.
+// The claim is "<mode>:<hmac>" so verification doesn't depend on the
+// current configuration.
+func (s *AuthService) tokenBindingFor(ctx context.Context) string {
+	if s.tokenBinding == "" || s.tokenBinding == TokenBindingNone {
+		return ""
+	}
+
+	value := bindingValue(s.tokenBinding, requestinfo.FromContext(ctx))
+	if value == "" {
+		return ""
+	}
+
+	return string(s.tokenBinding) + ":" + s.bindingHash(s.tokenBinding, value)
+}
+
+// verifyTokenBinding reports whether a token's binding claim matches the
+// client in ctx. Unbound tokens always match.
+func (s *AuthService) verifyTokenBinding(ctx context.Context, binding string) bool {
+	if binding == "" {
+		return true
+	}
+
+	mode, hash, ok := strings.Cut(binding, ":")
+	if !ok {
+		return false
+	}
+
+	value := bindingValue(TokenBindingMode(mode), requestinfo.FromContext(ctx))
+	if value == "" {
+		return false
+	}
+
+	expected := s.bindingHash(TokenBindingMode(mode), value)
+	return hmac.Equal([]byte(hash), []byte(expected))
+}
+
+// bindingValue returns the client attribute a token is bound to
+func bindingValue(mode TokenBindingMode, info requestinfo.Info) string {
+	switch mode {
+	case TokenBindingIP:
+		return info.IP
+	case TokenBindingUserAgent:
+		return info.UserAgent
+	default:
+		return ""
+	}
+}
+
+// bindingHash keys the hash with the JWT secret so the bound IP or user
+// agent can't be recovered from the token by brute force
+func (s *AuthService) bindingHash(mode TokenBindingMode, value string) string {
+	mac := hmac.New(sha256.New, []byte(s.jwtSecret))
+	mac.Write([]byte(string(mode) + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenBinding tests that bound access tokens only validate for the same client
+func TestTokenBinding(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	clientContext := func(ip, userAgent string) context.Context {
+		return requestinfo.NewContext(context.Background(), requestinfo.Info{IP: ip, UserAgent: userAgent})
+	}
+
+	login := func(t *testing.T, mode TokenBindingMode, ctx context.Context, bindToken bool) (*AuthService, *models.LoginResponse) {
+		user := &models.User{
+			ID:           uuid.New(),
+			Email:        "john.doe@example.com",
+			PasswordHash: passwordHash,
+			Status:       models.UserStatusActive,
+		}
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+		mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour, WithTokenBinding(mode))
+		response, err := service.LoginWithOptions(ctx, "john.doe@example.com", password, models.LoginOptions{BindToken: bindToken})
+		require.NoError(t, err)
+		return service, response
+	}
+
+	tests := []struct {
+		name      string
+		mode      TokenBindingMode
+		bindToken bool
+		issuedTo  context.Context
+		usedFrom  context.Context
+		wantErr   bool
+	}{
+		{
+			name:      "ip bound token from same ip",
+			mode:      TokenBindingIP,
+			bindToken: true,
+			issuedTo:  clientContext("203.0.113.7", "app/1.0"),
+			usedFrom:  clientContext("203.0.113.7", "app/2.0"),
+		},
+		{
+			name:      "ip bound token from different ip",
+			mode:      TokenBindingIP,
+			bindToken: true,
+			issuedTo:  clientContext("203.0.113.7", "app/1.0"),
+			usedFrom:  clientContext("198.51.100.9", "app/1.0"),
+			wantErr:   true,
+		},
+		{
+			name:      "user agent bound token from same user agent",
+			mode:      TokenBindingUserAgent,
+			bindToken: true,
+			issuedTo:  clientContext("203.0.113.7", "app/1.0"),
+			usedFrom:  clientContext("198.51.100.9", "app/1.0"),
+		},
+		{
+			name:      "user agent bound token from different user agent",
+			mode:      TokenBindingUserAgent,
+			bindToken: true,
+			issuedTo:  clientContext("203.0.113.7", "app/1.0"),
+			usedFrom:  clientContext("203.0.113.7", "curl/8.0"),
+			wantErr:   true,
+		},
+		{
+			name:     "login without opt in from different ip",
+			mode:     TokenBindingIP,
+			issuedTo: clientContext("203.0.113.7", "app/1.0"),
+			usedFrom: clientContext("198.51.100.9", "curl/8.0"),
+		},
+		{
+			name:      "opt in with binding disabled from different ip",
+			mode:      TokenBindingNone,
+			bindToken: true,
+			issuedTo:  clientContext("203.0.113.7", "app/1.0"),
+			usedFrom:  clientContext("198.51.100.9", "curl/8.0"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, response := login(t, tt.mode, tt.issuedTo, tt.bindToken)
+
+			user, err := service.ValidateAccessToken(tt.usedFrom, response.AccessToken)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				appErr := appErrors.GetAppError(err)
+				require.NotNil(t, appErr)
+				assert.Equal(t, 401, appErr.StatusCode)
+				assert.Nil(t, user)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, user)
+			}
+		})
+	}
+
+	t.Run("refresh keeps the binding and rejects other clients", func(t *testing.T) {
+		issuedTo := clientContext("203.0.113.7", "app/1.0")
+		elsewhere := clientContext("198.51.100.9", "app/1.0")
+		service, response := login(t, TokenBindingIP, issuedTo, true)
+
+		_, err := service.RefreshToken(elsewhere, response.RefreshToken)
+		require.Error(t, err)
+		assert.Equal(t, 401, appErrors.GetAppError(err).StatusCode)
+
+		refreshed, err := service.RefreshToken(issuedTo, response.RefreshToken)
+		require.NoError(t, err)
+
+		_, err = service.ValidateAccessToken(issuedTo, refreshed.AccessToken)
+		require.NoError(t, err)
+		_, err = service.ValidateAccessToken(elsewhere, refreshed.AccessToken)
+		require.Error(t, err)
+	})
+}
+
+// TestParseTokenBindingMode tests parsing binding modes from configuration
+func TestParseTokenBindingMode(t *testing.T) {
+	for value, expected := range map[string]TokenBindingMode{
+		"":           TokenBindingNone,
+		"none":       TokenBindingNone,
+		"IP":         TokenBindingIP,
+		"user_agent": TokenBindingUserAgent,
+	} {
+		mode, err := ParseTokenBindingMode(value)
+		require.NoError(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	_, err := ParseTokenBindingMode("cookie")
+	assert.Error(t, err)
+}
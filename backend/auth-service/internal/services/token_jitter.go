@@ -0,0 +1,35 @@
+package services
+
+import "time"
+
+// MaxAccessTokenJitter is the largest allowed jitter fraction. Anything
+// higher would let some tokens live only half as long as configured.
+const MaxAccessTokenJitter = 0.5
+
+// accessTokenJitter spreads access token expiries so clients that logged in
+// together don't all refresh at the same moment
+type accessTokenJitter struct {
+	fraction float64       // Jitter as a fraction of the access token expiry, e.g. 0.1 for ±10%
+	max      time.Duration // Upper bound on the jitter in either direction (0 = no bound)
+}
+
+// accessTokenExpiry returns the access token lifetime for a new token: the
+// configured expiry shifted by a random offset within ±jitter
+func (s *AuthService) accessTokenExpiry() time.Duration {
+	spread := s.jitterSpread()
+	if spread <= 0 {
+		return s.accessTokenDuration
+	}
+
+	offset := time.Duration(s.randInt63n(int64(2*spread)+1)) - spread
+	return s.accessTokenDuration + offset
+}
+
+// jitterSpread returns the largest offset jitter may apply in either direction
+func (s *AuthService) jitterSpread() time.Duration {
+	spread := time.Duration(float64(s.accessTokenDuration) * s.jitter.fraction)
+	if s.jitter.max > 0 && spread > s.jitter.max {
+		spread = s.jitter.max
+	}
+	return spread
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccessTokenJitter tests that tokens issued together get different expiries within the jitter bound
+func TestAccessTokenJitter(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	expiry := 15 * time.Minute
+
+	issueLifetimes := func(t *testing.T, service *AuthService, n int) map[time.Duration]bool {
+		lifetimes := map[time.Duration]bool{}
+		for i := 0; i < n; i++ {
+			token, lifetime, err := service.issueAccessToken(context.Background(), uuid.New().String(), "john.doe@example.com")
+			require.NoError(t, err)
+
+			claims, err := utils.ValidateTokenWithClaims(token, jwtSecret)
+			require.NoError(t, err)
+			tokenLifetime := time.Duration(claims.ExpiresAt-claims.IssuedAt) * time.Second
+			assert.InDelta(t, lifetime.Seconds(), tokenLifetime.Seconds(), 1, "exp should match the returned lifetime")
+
+			lifetimes[lifetime] = true
+		}
+		return lifetimes
+	}
+
+	t.Run("expiries differ within ±fraction", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), jwtSecret, expiry, 7*24*time.Hour, WithAccessTokenJitter(0.1, 0))
+
+		lifetimes := issueLifetimes(t, service, 20)
+		assert.Greater(t, len(lifetimes), 1, "tokens issued together should not all expire together")
+		for lifetime := range lifetimes {
+			assert.GreaterOrEqual(t, lifetime, expiry-90*time.Second)
+			assert.LessOrEqual(t, lifetime, expiry+90*time.Second)
+		}
+	})
+
+	t.Run("jitter is capped at max", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), jwtSecret, expiry, 7*24*time.Hour, WithAccessTokenJitter(0.5, 30*time.Second))
+
+		for lifetime := range issueLifetimes(t, service, 20) {
+			assert.GreaterOrEqual(t, lifetime, expiry-30*time.Second)
+			assert.LessOrEqual(t, lifetime, expiry+30*time.Second)
+		}
+	})
+
+	t.Run("bounds are reached at the ends of the random range", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), jwtSecret, expiry, 7*24*time.Hour, WithAccessTokenJitter(0.1, 0))
+
+		service.randInt63n = func(n int64) int64 { return 0 }
+		assert.Equal(t, expiry-90*time.Second, service.accessTokenExpiry())
+
+		service.randInt63n = func(n int64) int64 { return n - 1 }
+		assert.Equal(t, expiry+90*time.Second, service.accessTokenExpiry())
+	})
+
+	t.Run("disabled or out of range jitter keeps the configured expiry", func(t *testing.T) {
+		for _, fraction := range []float64{0, -0.1, 0.9} {
+			service := NewAuthService(new(MockUserRepository), jwtSecret, expiry, 7*24*time.Hour, WithAccessTokenJitter(fraction, 0))
+			assert.Equal(t, map[time.Duration]bool{expiry: true}, issueLifetimes(t, service, 5))
+		}
+	})
+}
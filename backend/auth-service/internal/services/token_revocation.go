@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/requestinfo"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// RevocationStore tracks revoked token IDs until they expire (see internal/cache)
+type RevocationStore interface {
+	Counter
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// RevokeToken revokes the access token with the given jti so it fails
+// validation immediately. The entry is kept for the longest lifetime an access
+// token can have, after which the token has expired anyway. The revocation is
+// recorded as an audit event.
+func (s *AuthService) RevokeToken(ctx context.Context, jti string) error {
+	jti = strings.TrimSpace(jti)
+	if jti == "" {
+		return appErrors.NewBadRequest("jti is required")
+	}
+
+	if s.revokedTokens == nil {
+		return appErrors.NewNotFound("token revocation is disabled")
+	}
+
+	if _, err := s.revokedTokens.Increment(ctx, revokedTokenKey(jti), s.maxAccessTokenLifetime()); err != nil {
+		return appErrors.NewInternalError(err, "failed to revoke token")
+	}
+
+	s.recordTokenRevoked(ctx, jti)
+	return nil
+}
+
+// isTokenRevoked reports whether the token with the given jti was revoked.
+// Store failures are logged and treat the token as not revoked, so an
+// unavailable store doesn't reject every request.
+func (s *AuthService) isTokenRevoked(ctx context.Context, jti string) bool {
+	if s.revokedTokens == nil || jti == "" {
+		return false
+	}
+
+	revoked, err := s.revokedTokens.Exists(ctx, revokedTokenKey(jti))
+	if err != nil {
+		s.logger.WithError(err).WithField("jti", jti).Warn("Token revocation check failed")
+		return false
+	}
+
+	return revoked
+}
+
+// maxAccessTokenLifetime returns how long an access token can remain
+// acceptable: the configured expiry plus any jitter and expiry grace
+func (s *AuthService) maxAccessTokenLifetime() time.Duration {
+	return s.accessTokenDuration + s.jitterSpread() + s.expiryGrace
+}
+
+// recordTokenRevoked writes a token revocation audit event; failures are logged
+func (s *AuthService) recordTokenRevoked(ctx context.Context, jti string) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	info := requestinfo.FromContext(ctx)
+
+	event := &models.AuditEvent{
+		ID:        uuid.New(),
+		EventType: models.AuditEventTokenRevoked,
+		IPAddress: info.IP,
+		UserAgent: info.UserAgent,
+		Metadata:  map[string]string{"jti": jti},
+		CreatedAt: s.clock.Now().UTC(),
+	}
+	if info.RequestID != "" {
+		event.Metadata["request_id"] = info.RequestID
+	}
+
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"event_type": event.EventType,
+			"jti":        jti,
+		}).Error("Failed to record token revocation audit event")
+	}
+}
+
+// revokedTokenKey returns the store key for a revoked jti
+func revokedTokenKey(jti string) string {
+	return "revoked_tokens:" + jti
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/cache"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRevokeToken tests that a revoked jti fails validation immediately while
+// other tokens for the same user are unaffected
+func TestRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	user := &models.User{
+		ID:     uuid.New(),
+		Email:  "john.doe@example.com",
+		Status: models.UserStatusActive,
+	}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	mockAudit := new(MockAuditRepository)
+	mockAudit.On("Create", mock.Anything, mock.MatchedBy(func(event *models.AuditEvent) bool {
+		return event.EventType == models.AuditEventTokenRevoked
+	})).Return(nil).Once()
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour,
+		WithTokenRevocation(cache.NewMemoryCounter()), WithAuditRepository(mockAudit))
+
+	revokedToken, _, err := service.issueAccessToken(ctx, user.ID.String(), user.Email)
+	require.NoError(t, err)
+	otherToken, _, err := service.issueAccessToken(ctx, user.ID.String(), user.Email)
+	require.NoError(t, err)
+
+	claims, err := utils.ValidateTokenWithClaims(revokedToken, jwtSecret)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RevokeToken(ctx, claims.ID))
+
+	_, err = service.ValidateAccessToken(ctx, revokedToken)
+	require.Error(t, err)
+	appErr := appErrors.GetAppError(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, appErrors.CodeUnauthorized, appErr.Code)
+
+	_, err = service.ValidateAccessToken(ctx, otherToken)
+	require.NoError(t, err)
+
+	mockAudit.AssertExpectations(t)
+
+	t.Run("jti is required", func(t *testing.T) {
+		err := service.RevokeToken(ctx, " ")
+		require.Error(t, err)
+	})
+
+	t.Run("revocation disabled", func(t *testing.T) {
+		service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+		err := service.RevokeToken(ctx, claims.ID)
+		require.Error(t, err)
+	})
+}
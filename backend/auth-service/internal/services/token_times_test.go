@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenIssueTimes tests that login and refresh report when the access
+// token was issued and expires, matching its iat and exp claims
+func TestTokenIssueTimes(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	password := "SecurePass123!"
+	passwordHash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        "john.doe@example.com",
+		PasswordHash: passwordHash,
+		Status:       models.UserStatusActive,
+	}
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "john.doe@example.com").Return(user, nil)
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+	assertMatchesToken := func(t *testing.T, token string, issuedAt, expiresAt time.Time) {
+		assert.WithinDuration(t, time.Now(), issuedAt, 5*time.Second)
+		assert.True(t, issuedAt.Before(expiresAt), "issued_at should precede expires_at")
+		assert.Equal(t, time.UTC, issuedAt.Location())
+		assert.Equal(t, time.UTC, expiresAt.Location())
+
+		claims, err := utils.ValidateTokenWithClaims(token, jwtSecret)
+		require.NoError(t, err)
+		assert.Equal(t, claims.IssuedAt, issuedAt.Unix())
+		assert.Equal(t, claims.ExpiresAt, expiresAt.Unix())
+	}
+
+	login, err := service.Login(context.Background(), "john.doe@example.com", password)
+	require.NoError(t, err)
+	assertMatchesToken(t, login.AccessToken, login.IssuedAt, login.ExpiresAt)
+	assert.Equal(t, login.ExpiresIn, int(login.ExpiresAt.Sub(login.IssuedAt).Seconds()))
+
+	refreshed, err := service.RefreshToken(context.Background(), login.RefreshToken)
+	require.NoError(t, err)
+	assertMatchesToken(t, refreshed.AccessToken, refreshed.IssuedAt, refreshed.ExpiresAt)
+}
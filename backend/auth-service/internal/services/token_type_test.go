@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/protobankbankc/auth-service/internal/utils"
+	appErrors "github.com/protobankbankc/auth-service/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenTypeEnforced tests that a refresh token isn't accepted where an
+// access token is expected, and vice versa
+func TestTokenTypeEnforced(t *testing.T) {
+	jwtSecret := "test-secret-key-at-least-32-chars-long-for-security"
+	userID := uuid.New().String()
+
+	mockRepo := new(MockUserRepository)
+	service := NewAuthService(mockRepo, jwtSecret, 15*time.Minute, 7*24*time.Hour)
+
+	accessToken, err := utils.GenerateAccessToken(userID, "john.doe@example.com", 15*time.Minute, jwtSecret)
+	require.NoError(t, err)
+	refreshToken, err := utils.GenerateRefreshToken(userID, "john.doe@example.com", 7*24*time.Hour, jwtSecret)
+	require.NoError(t, err)
+
+	assertInvalidType := func(t *testing.T, err error) {
+		appErr := appErrors.GetAppError(err)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusUnauthorized, appErr.StatusCode)
+		assert.Equal(t, "invalid token type", appErr.Message)
+	}
+
+	t.Run("refresh token as access token", func(t *testing.T) {
+		user, err := service.ValidateAccessToken(context.Background(), refreshToken)
+		assert.Nil(t, user)
+		assertInvalidType(t, err)
+	})
+
+	t.Run("access token as refresh token", func(t *testing.T) {
+		resp, err := service.RefreshToken(context.Background(), accessToken)
+		assert.Nil(t, resp)
+		assertInvalidType(t, err)
+	})
+
+	// No user lookups happen for tokens of the wrong type
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,80 @@
+package startup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetryPolicy bounds how long startup waits for a dependency
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts before giving up (at least 1)
+	MaxAttempts int
+
+	// InitialBackoff is the delay after the first failed attempt
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay, which doubles after each failed attempt
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the default startup retry policy
+// (about a minute in total before giving up)
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+// WaitFor calls connect until it succeeds, the policy's attempts are used up
+// or ctx is cancelled. Orchestrators often start the service before its
+// database or cache accepts connections, so failing on the first attempt
+// would crash-loop the container.
+func WaitFor(ctx context.Context, logger *logrus.Logger, name string, policy RetryPolicy, connect func(ctx context.Context) error) error {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	backoff := policy.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = connect(ctx); err == nil {
+			if attempt > 1 {
+				logger.WithFields(logrus.Fields{
+					"dependency": name,
+					"attempt":    attempt,
+				}).Info("Dependency is reachable")
+			}
+			return nil
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		logger.WithFields(logrus.Fields{
+			"dependency":   name,
+			"attempt":      attempt,
+			"max_attempts": maxAttempts,
+			"retry_in":     backoff.String(),
+		}).WithError(err).Warn("Dependency not reachable, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting for %s: %w", name, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	return fmt.Errorf("%s not reachable after %d attempts: %w", name, maxAttempts, err)
+}
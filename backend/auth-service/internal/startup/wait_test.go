@@ -0,0 +1,88 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyDependency fails a fixed number of times before becoming healthy
+type flakyDependency struct {
+	failures int
+	calls    int
+}
+
+func (d *flakyDependency) connect(ctx context.Context) error {
+	d.calls++
+	if d.calls <= d.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// testPolicy retries quickly so tests don't wait on real backoff
+func testPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+}
+
+// TestWaitForSucceedsAfterFailures tests waiting for a dependency that becomes healthy
+func TestWaitForSucceedsAfterFailures(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	dependency := &flakyDependency{failures: 3}
+
+	err := WaitFor(context.Background(), logger, "postgres", testPolicy(5), dependency.connect)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, dependency.calls)
+	assert.Len(t, hook.AllEntries(), 4, "three retry warnings and one recovery message")
+	assert.Equal(t, "postgres", hook.LastEntry().Data["dependency"])
+}
+
+// TestWaitForGivesUp tests that a dependency that stays down eventually fails startup
+func TestWaitForGivesUp(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	dependency := &flakyDependency{failures: 100}
+
+	err := WaitFor(context.Background(), logger, "redis", testPolicy(3), dependency.connect)
+	require.Error(t, err)
+
+	assert.Equal(t, 3, dependency.calls)
+	assert.Contains(t, err.Error(), "redis not reachable after 3 attempts")
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+// TestWaitForFirstAttempt tests that a healthy dependency is connected to without retries
+func TestWaitForFirstAttempt(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	dependency := &flakyDependency{}
+
+	err := WaitFor(context.Background(), logger, "postgres", RetryPolicy{}, dependency.connect)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, dependency.calls)
+	assert.Empty(t, hook.AllEntries())
+}
+
+// TestWaitForCancelled tests that cancelling the context stops waiting
+func TestWaitForCancelled(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dependency := &flakyDependency{failures: 100}
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}
+
+	err := WaitFor(ctx, logger, "postgres", policy, dependency.connect)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, dependency.calls)
+}
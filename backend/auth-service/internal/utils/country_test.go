@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeCountry tests countries are reduced to their ISO 3166-1 alpha-2 code
+func TestNormalizeCountry(t *testing.T) {
+	valid := map[string]string{
+		"GB":   "GB",
+		"gb":   "GB",
+		" US ": "US",
+		"UK":   "GB",
+		"uk":   "GB",
+		"EL":   "GR",
+	}
+	for input, expected := range valid {
+		code, ok := NormalizeCountry(input)
+		assert.True(t, ok, "input %q", input)
+		assert.Equal(t, expected, code, "input %q", input)
+	}
+
+	for _, input := range []string{"", "XX", "GBR", "England", "U"} {
+		_, ok := NormalizeCountry(input)
+		assert.False(t, ok, "input %q", input)
+	}
+}
@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password hash scheme identifiers
+const (
+	HashSchemeBcrypt   = "bcrypt"
+	HashSchemeArgon2id = "argon2id"
+)
+
+// HashScheme is a password hashing algorithm. Hashes carry their own
+// algorithm tag as a "$<id>$" prefix (modular crypt format), so stored
+// hashes can be dispatched to the scheme that produced them.
+type HashScheme interface {
+	// ID returns the scheme identifier used in configuration
+	ID() string
+
+	// Identifies reports whether the hash was produced by this scheme
+	Identifies(hash string) bool
+
+	// Hash hashes a password
+	Hash(password string) (string, error)
+
+	// Verify checks a password against a hash produced by this scheme
+	Verify(hash, password string) error
+
+	// NeedsRehash reports whether the hash uses weaker parameters than the scheme's current ones
+	NeedsRehash(hash string) bool
+}
+
+// HashRegistry hashes new passwords with a default scheme and verifies
+// existing hashes with whichever registered scheme produced them
+type HashRegistry struct {
+	defaultScheme HashScheme
+	schemes       []HashScheme
+}
+
+// NewHashRegistry creates a registry that hashes with defaultScheme and also verifies others
+func NewHashRegistry(defaultScheme HashScheme, others ...HashScheme) *HashRegistry {
+	registry := &HashRegistry{defaultScheme: defaultScheme}
+	registry.Register(defaultScheme)
+	for _, scheme := range others {
+		registry.Register(scheme)
+	}
+	return registry
+}
+
+// DefaultHashRegistry creates a registry that hashes with bcrypt and also verifies argon2id
+func DefaultHashRegistry() *HashRegistry {
+	return NewHashRegistry(NewBcryptScheme(bcrypt.DefaultCost), NewArgon2idScheme())
+}
+
+// NewPasswordHashRegistry creates a registry with the built-in schemes, hashing new passwords with the named one
+func NewPasswordHashRegistry(defaultID string) (*HashRegistry, error) {
+	switch strings.ToLower(strings.TrimSpace(defaultID)) {
+	case "", HashSchemeBcrypt:
+		return DefaultHashRegistry(), nil
+	case HashSchemeArgon2id:
+		return NewHashRegistry(NewArgon2idScheme(), NewBcryptScheme(bcrypt.DefaultCost)), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash scheme %q", defaultID)
+	}
+}
+
+// Register adds a scheme that existing hashes may be verified with.
+// Registering a scheme with an existing ID replaces it.
+func (r *HashRegistry) Register(scheme HashScheme) {
+	for i, existing := range r.schemes {
+		if existing.ID() == scheme.ID() {
+			r.schemes[i] = scheme
+			return
+		}
+	}
+	r.schemes = append(r.schemes, scheme)
+}
+
+// Hash hashes a password with the default scheme
+func (r *HashRegistry) Hash(password string) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("password cannot be empty")
+	}
+	return r.defaultScheme.Hash(password)
+}
+
+// Verify checks a password against a hash from any registered scheme
+func (r *HashRegistry) Verify(hash, password string) error {
+	scheme := r.schemeFor(hash)
+	if scheme == nil {
+		return fmt.Errorf("invalid password")
+	}
+	return scheme.Verify(hash, password)
+}
+
+// NeedsRehash reports whether the hash should be replaced with one from the
+// default scheme, either because another scheme produced it or because its
+// parameters are out of date
+func (r *HashRegistry) NeedsRehash(hash string) bool {
+	scheme := r.schemeFor(hash)
+	if scheme == nil || scheme.ID() != r.defaultScheme.ID() {
+		return true
+	}
+	return scheme.NeedsRehash(hash)
+}
+
+// schemeFor returns the registered scheme that produced the hash
+func (r *HashRegistry) schemeFor(hash string) HashScheme {
+	for _, scheme := range r.schemes {
+		if scheme.Identifies(hash) {
+			return scheme
+		}
+	}
+	return nil
+}
+
+// BcryptScheme hashes passwords with bcrypt
+type BcryptScheme struct {
+	cost int
+}
+
+// NewBcryptScheme creates a bcrypt scheme with the given cost
+func NewBcryptScheme(cost int) *BcryptScheme {
+	return &BcryptScheme{cost: cost}
+}
+
+// ID returns the scheme identifier
+func (s *BcryptScheme) ID() string {
+	return HashSchemeBcrypt
+}
+
+// Identifies reports whether the hash is a bcrypt hash
+func (s *BcryptScheme) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Hash hashes a password with bcrypt
+func (s *BcryptScheme) Hash(password string) (string, error) {
+	if PasswordTooLong(password, MaxPasswordBytes) {
+		return "", fmt.Errorf("password too long: maximum %d bytes", MaxPasswordBytes)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// Verify checks a password against a bcrypt hash
+func (s *BcryptScheme) Verify(hash, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return fmt.Errorf("invalid password")
+	}
+	return nil
+}
+
+// NeedsRehash reports whether the hash was generated with a lower cost
+func (s *BcryptScheme) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < s.cost
+}
+
+// Argon2idScheme hashes passwords with argon2id, encoded as
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+type Argon2idScheme struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	keyLen  uint32
+	saltLen int
+}
+
+// NewArgon2idScheme creates an argon2id scheme with the RFC 9106 second recommended parameters
+func NewArgon2idScheme() *Argon2idScheme {
+	return &Argon2idScheme{
+		memory:  64 * 1024,
+		time:    3,
+		threads: 4,
+		keyLen:  32,
+		saltLen: 16,
+	}
+}
+
+// ID returns the scheme identifier
+func (s *Argon2idScheme) ID() string {
+	return HashSchemeArgon2id
+}
+
+// Identifies reports whether the hash is an argon2id hash
+func (s *Argon2idScheme) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// Hash hashes a password with argon2id and a random salt
+func (s *Argon2idScheme) Hash(password string) (string, error) {
+	salt := make([]byte, s.saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, s.time, s.memory, s.threads, s.keyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, s.memory, s.time, s.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify checks a password against an argon2id hash using the hash's own parameters
+func (s *Argon2idScheme) Verify(hash, password string) error {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return fmt.Errorf("invalid password")
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return fmt.Errorf("invalid password")
+	}
+	return nil
+}
+
+// NeedsRehash reports whether the hash was generated with different parameters
+func (s *Argon2idScheme) NeedsRehash(hash string) bool {
+	params, _, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.memory != s.memory || params.time != s.time || params.threads != s.threads || uint32(len(key)) != s.keyLen
+}
+
+// decodeArgon2id parses an encoded argon2id hash
+func decodeArgon2id(hash string) (*Argon2idScheme, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != HashSchemeArgon2id {
+		return nil, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+
+	params := &Argon2idScheme{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, nil, nil, fmt.Errorf("malformed argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("malformed argon2id salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, fmt.Errorf("malformed argon2id key")
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestHashRegistryVerify tests verifying hashes from different schemes
+func TestHashRegistryVerify(t *testing.T) {
+	password := "SecurePass123!"
+	registry := DefaultHashRegistry()
+
+	bcryptHash, err := NewBcryptScheme(bcrypt.MinCost).Hash(password)
+	require.NoError(t, err)
+	argon2idHash, err := NewArgon2idScheme().Hash(password)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "bcrypt", hash: bcryptHash},
+		{name: "argon2id", hash: argon2idHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, registry.Verify(tt.hash, password))
+			assert.Error(t, registry.Verify(tt.hash, "WrongPassword123!"))
+		})
+	}
+
+	t.Run("unknown scheme", func(t *testing.T) {
+		assert.Error(t, registry.Verify("$md5$not-supported", password))
+	})
+
+	t.Run("malformed argon2id hash", func(t *testing.T) {
+		assert.Error(t, registry.Verify("$argon2id$v=19$m=65536$bad", password))
+	})
+}
+
+// TestHashRegistryNeedsRehash tests detecting hashes that should be replaced
+func TestHashRegistryNeedsRehash(t *testing.T) {
+	password := "SecurePass123!"
+
+	bcryptHash, err := NewBcryptScheme(bcrypt.DefaultCost).Hash(password)
+	require.NoError(t, err)
+	weakBcryptHash, err := NewBcryptScheme(bcrypt.MinCost).Hash(password)
+	require.NoError(t, err)
+	argon2idHash, err := NewArgon2idScheme().Hash(password)
+	require.NoError(t, err)
+
+	weakArgon2id := NewArgon2idScheme()
+	weakArgon2id.time = 1
+	weakArgon2idHash, err := weakArgon2id.Hash(password)
+	require.NoError(t, err)
+
+	bcryptDefault := DefaultHashRegistry()
+	argon2idDefault, err := NewPasswordHashRegistry(HashSchemeArgon2id)
+	require.NoError(t, err)
+
+	assert.False(t, bcryptDefault.NeedsRehash(bcryptHash), "current bcrypt hash")
+	assert.True(t, bcryptDefault.NeedsRehash(weakBcryptHash), "bcrypt hash with lower cost")
+	assert.True(t, bcryptDefault.NeedsRehash(argon2idHash), "hash from a non-default scheme")
+
+	assert.False(t, argon2idDefault.NeedsRehash(argon2idHash), "current argon2id hash")
+	assert.True(t, argon2idDefault.NeedsRehash(weakArgon2idHash), "argon2id hash with old parameters")
+	assert.True(t, argon2idDefault.NeedsRehash(bcryptHash), "bcrypt hash after migrating to argon2id")
+
+	// Old-parameter hashes still verify until they are replaced
+	assert.NoError(t, argon2idDefault.Verify(weakArgon2idHash, password))
+}
+
+// TestNewPasswordHashRegistry tests selecting the default scheme from configuration
+func TestNewPasswordHashRegistry(t *testing.T) {
+	registry, err := NewPasswordHashRegistry("ARGON2ID")
+	require.NoError(t, err)
+
+	hash, err := registry.Hash("SecurePass123!")
+	require.NoError(t, err)
+	assert.True(t, NewArgon2idScheme().Identifies(hash))
+
+	registry, err = NewPasswordHashRegistry("")
+	require.NoError(t, err)
+
+	hash, err = registry.Hash("SecurePass123!")
+	require.NoError(t, err)
+	assert.True(t, NewBcryptScheme(bcrypt.DefaultCost).Identifies(hash))
+
+	_, err = NewPasswordHashRegistry("md5")
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK is an RSA public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKSet is a JSON Web Key Set, as served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWKS returns the public halves of keys as a JWK set. Keys without a
+// public key are skipped.
+func PublicJWKS(keys []*RSAKeyPair) JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range keys {
+		if key == nil || key.Public == nil {
+			continue
+		}
+		set.Keys = append(set.Keys, JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: AlgorithmRS256,
+			KeyID:     key.KeyID(),
+			Modulus:   base64.RawURLEncoding.EncodeToString(key.Public.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.Public.E)).Bytes()),
+		})
+	}
+	return set
+}
+
+// jwkThumbprint returns the RFC 7638 SHA-256 thumbprint of an RSA public key:
+// the hash of its required JWK members in lexicographic order, base64url
+// encoded
+func jwkThumbprint(publicKey *rsa.PublicKey) string {
+	if publicKey == nil {
+		return ""
+	}
+
+	n := base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -1,40 +1,145 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// ErrMalformedClaims is returned for correctly signed tokens missing the user
+// ID or token type, e.g. tokens issued by another system sharing the secret
+var ErrMalformedClaims = errors.New("malformed token claims")
+
+// Token types, stored in the token_type claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// TokenTypeError is returned for valid tokens of the wrong type, e.g. a
+// refresh token presented as an access token
+type TokenTypeError struct {
+	Expected string
+	Actual   string
+}
+
+// Error implements the error interface
+func (e *TokenTypeError) Error() string {
+	return fmt.Sprintf("invalid token type: expected %s token, got %s", e.Expected, e.Actual)
+}
+
 // TokenClaims represents the claims stored in JWT tokens
 type TokenClaims struct {
 	UserID    string `json:"user_id"`
 	Email     string `json:"email"`
-	TokenType string `json:"token_type"` // "access" or "refresh"
+	TokenType string `json:"token_type"`        // "access" or "refresh"
+	Binding   string `json:"binding,omitempty"` // client binding hash, if bound
+	SessionID string `json:"sid,omitempty"`     // session the token belongs to, if tracked
+}
+
+// RegisteredTokenClaims represents the custom claims together with the
+// standard registered claims (RFC 7519) for OIDC-style clients
+type RegisteredTokenClaims struct {
+	TokenClaims
+	Subject   string   `json:"sub"`
+	ID        string   `json:"jti,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
 }
 
 // customClaims extends jwt.RegisteredClaims with our custom fields
 type customClaims struct {
-	UserID    string `json:"user_id"`
-	Email     string `json:"email"`
+	UserID    string `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
 	TokenType string `json:"token_type"`
+	Binding   string `json:"bnd,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// AccessTokenOptions controls optional access token claims
+type AccessTokenOptions struct {
+	// Binding binds the token to a client. The value is opaque to this
+	// package; callers verify it on use.
+	Binding string
+
+	// Minimal omits user_id, email and nbf, leaving only sub, exp, iat, jti
+	// and token_type (plus the binding, if set) to keep the token small.
+	// Consumers look up the rest rather than reading it from the token.
+	Minimal bool
+
+	// IssuedAt is the token's issue time, from which expiry is counted.
+	// Zero means now.
+	IssuedAt time.Time
+
+	// SessionID ties the token to a server-side session (the sid claim)
+	SessionID string
+}
+
+// SigningKeys holds the HMAC secrets for each token type, so a leaked access
+// secret doesn't also let refresh tokens be forged. Without a refresh secret,
+// refresh tokens are signed with the access secret.
+type SigningKeys struct {
+	Access  string
+	Refresh string
+
+	// AccessRSA, when set, signs access tokens with RS256 instead of the
+	// access secret, so other services can verify them with the public key
+	// alone. Refresh tokens are only verified here and stay HS256.
+	AccessRSA *RSAKeyPair
+
+	// RetiredAccessRSA are previous access token keys; tokens they signed
+	// still verify until they expire, but new tokens use AccessRSA
+	RetiredAccessRSA []*RSAKeyPair
+}
+
+// ForType returns the HMAC secret for tokens of the given type
+func (k SigningKeys) ForType(tokenType string) string {
+	if tokenType == "refresh" && k.Refresh != "" {
+		return k.Refresh
+	}
+	return k.Access
+}
+
+// KeyFor returns the key that signs and verifies tokens of the given type
+func (k SigningKeys) KeyFor(tokenType string) SigningKey {
+	if tokenType == "access" && k.AccessRSA != nil {
+		return SigningKey{RSA: k.AccessRSA, RetiredRSA: k.RetiredAccessRSA}
+	}
+	return HMACKey(k.ForType(tokenType))
+}
+
 // GenerateAccessToken generates a new JWT access token
 func GenerateAccessToken(userID, email string, expiry time.Duration, secret string) (string, error) {
-	return generateToken(userID, email, "access", expiry, secret)
+	return generateToken(userID, email, TokenTypeAccess, AccessTokenOptions{}, expiry, HMACKey(secret))
+}
+
+// GenerateAccessTokenWithKey generates a JWT access token with optional
+// claims (binding, session, issue time), signed with the key's algorithm
+func GenerateAccessTokenWithKey(userID, email string, opts AccessTokenOptions, expiry time.Duration, key SigningKey) (string, error) {
+	return generateToken(userID, email, TokenTypeAccess, opts, expiry, key)
 }
 
 // GenerateRefreshToken generates a new JWT refresh token
 func GenerateRefreshToken(userID, email string, expiry time.Duration, secret string) (string, error) {
-	return generateToken(userID, email, "refresh", expiry, secret)
+	return generateToken(userID, email, TokenTypeRefresh, AccessTokenOptions{}, expiry, HMACKey(secret))
+}
+
+// GenerateRefreshTokenWithOptions generates a JWT refresh token with optional
+// claims. Only IssuedAt, SessionID and Binding apply to refresh tokens.
+func GenerateRefreshTokenWithOptions(userID, email string, opts AccessTokenOptions, expiry time.Duration, secret string) (string, error) {
+	refreshOpts := AccessTokenOptions{IssuedAt: opts.IssuedAt, SessionID: opts.SessionID, Binding: opts.Binding}
+	return generateToken(userID, email, TokenTypeRefresh, refreshOpts, expiry, HMACKey(secret))
 }
 
 // generateToken creates a JWT token with the specified parameters
-func generateToken(userID, email, tokenType string, expiry time.Duration, secret string) (string, error) {
+func generateToken(userID, email, tokenType string, opts AccessTokenOptions, expiry time.Duration, key SigningKey) (string, error) {
 	// Validate inputs
 	if userID == "" {
 		return "", fmt.Errorf("user ID cannot be empty")
@@ -44,8 +149,9 @@ func generateToken(userID, email, tokenType string, expiry time.Duration, secret
 		return "", fmt.Errorf("email cannot be empty")
 	}
 
-	if secret == "" {
-		return "", fmt.Errorf("secret cannot be empty")
+	method, signingKey, err := key.signingMethod()
+	if err != nil {
+		return "", err
 	}
 
 	if expiry <= 0 {
@@ -53,23 +159,40 @@ func generateToken(userID, email, tokenType string, expiry time.Duration, secret
 	}
 
 	// Create claims
-	now := time.Now()
+	now := opts.IssuedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
 	claims := customClaims{
 		UserID:    userID,
 		Email:     email,
 		TokenType: tokenType,
+		Binding:   opts.Binding,
+		SessionID: opts.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
+	// Minimal tokens identify the user by sub only
+	if opts.Minimal {
+		claims.UserID = ""
+		claims.Email = ""
+		claims.NotBefore = nil
+	}
+
 	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(method, claims)
+	if kid := key.keyID(); kid != "" {
+		token.Header["kid"] = kid
+	}
 
 	// Sign token
-	signedToken, err := token.SignedString([]byte(secret))
+	signedToken, err := token.SignedString(signingKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -79,28 +202,50 @@ func generateToken(userID, email, tokenType string, expiry time.Duration, secret
 
 // ValidateToken validates a JWT token and returns the claims
 func ValidateToken(tokenString, secret string) (*TokenClaims, error) {
+	claims, err := ValidateTokenWithClaims(tokenString, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &claims.TokenClaims, nil
+}
+
+// ValidateTokenWithClaims validates a JWT token and returns the custom claims
+// along with the registered claims (sub, iat, exp, iss, aud)
+func ValidateTokenWithClaims(tokenString, secret string) (*RegisteredTokenClaims, error) {
+	return ValidateTokenWithKey(tokenString, HMACKey(secret), 0, time.Now())
+}
+
+// ValidateTokenWithKey validates a JWT token signed with key, which may be
+// RS256 as well as HS256. Tokens that expired less than leeway before now
+// are still accepted.
+func ValidateTokenWithKey(tokenString string, key SigningKey, leeway time.Duration, now time.Time) (*RegisteredTokenClaims, error) {
 	// Validate inputs
 	if tokenString == "" {
 		return nil, fmt.Errorf("token cannot be empty")
 	}
 
-	if secret == "" {
+	if !key.IsSet() {
 		return nil, fmt.Errorf("secret cannot be empty")
 	}
 
-	// Parse token
+	// Parse token. Only the key's own algorithm is accepted, which rules out
+	// "none" and algorithm confusion, e.g. an HS256 token signed with the
+	// RS256 public key, or other HMAC sizes.
+	algorithm := key.Algorithm()
 	token, err := jwt.ParseWithClaims(tokenString, &customClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
-	})
+		// RS256 keys are selected by kid; HS256 tokens must not name one
+		return key.verificationKey(token.Header["kid"])
+	}, jwt.WithValidMethods([]string{algorithm}), jwt.WithLeeway(leeway), jwt.WithTimeFunc(func() time.Time { return now }))
 
 	if err != nil {
 		// Check for specific error types
 		if strings.Contains(err.Error(), "token has expired") ||
-		   strings.Contains(err.Error(), "token is expired") {
+			strings.Contains(err.Error(), "token is expired") {
 			return nil, fmt.Errorf("token has expired")
 		}
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -112,12 +257,80 @@ func ValidateToken(tokenString, secret string) (*TokenClaims, error) {
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	// Return simplified claims
-	return &TokenClaims{
-		UserID:    claims.UserID,
-		Email:     claims.Email,
-		TokenType: claims.TokenType,
-	}, nil
+	result := &RegisteredTokenClaims{
+		TokenClaims: TokenClaims{
+			UserID:    claims.UserID,
+			Email:     claims.Email,
+			TokenType: claims.TokenType,
+			Binding:   claims.Binding,
+			SessionID: claims.SessionID,
+		},
+		Subject:  claims.Subject,
+		ID:       claims.ID,
+		Issuer:   claims.Issuer,
+		Audience: claims.Audience,
+	}
+
+	// Minimal tokens carry the user ID only as sub
+	if result.UserID == "" {
+		result.UserID = claims.Subject
+	}
+
+	if result.UserID == "" || result.TokenType == "" {
+		return nil, ErrMalformedClaims
+	}
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+
+	return result, nil
+}
+
+// ValidateAccessToken is ValidateTokenWithKey, also checking the token is an
+// access token and returning a *TokenTypeError for any other type. Prefer it
+// to checking claims.TokenType by hand.
+func ValidateAccessToken(tokenString string, key SigningKey, leeway time.Duration, now time.Time) (*RegisteredTokenClaims, error) {
+	return validateTokenOfType(tokenString, TokenTypeAccess, key, leeway, now)
+}
+
+// ValidateRefreshToken is ValidateAccessToken for refresh tokens. Expired
+// refresh tokens get no leeway.
+func ValidateRefreshToken(tokenString string, key SigningKey, now time.Time) (*RegisteredTokenClaims, error) {
+	return validateTokenOfType(tokenString, TokenTypeRefresh, key, 0, now)
+}
+
+// validateTokenOfType is ValidateTokenWithKey, also checking the token_type
+// claim is tokenType
+func validateTokenOfType(tokenString, tokenType string, key SigningKey, leeway time.Duration, now time.Time) (*RegisteredTokenClaims, error) {
+	claims, err := ValidateTokenWithKey(tokenString, key, leeway, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, &TokenTypeError{Expected: tokenType, Actual: claims.TokenType}
+	}
+
+	return claims, nil
+}
+
+// DecodeTokenUnverified returns a token's header and claims WITHOUT verifying
+// its signature or expiry. Only for developer tooling; never trust the result.
+func DecodeTokenUnverified(tokenString string) (map[string]interface{}, map[string]interface{}, error) {
+	if tokenString == "" {
+		return nil, nil, fmt.Errorf("token cannot be empty")
+	}
+
+	claims := jwt.MapClaims{}
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return token.Header, claims, nil
 }
 
 // ExtractTokenFromHeader extracts the JWT token from the Authorization header
@@ -133,8 +346,8 @@ func ExtractTokenFromHeader(authHeader string) (string, error) {
 		return "", fmt.Errorf("invalid authorization header format")
 	}
 
-	// Verify Bearer scheme
-	if parts[0] != "Bearer" {
+	// Verify Bearer scheme (case-insensitive per RFC 7235)
+	if !strings.EqualFold(parts[0], "Bearer") {
 		return "", fmt.Errorf("invalid authorization header format: expected Bearer scheme")
 	}
 
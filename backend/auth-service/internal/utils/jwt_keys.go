@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported token signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// RSAKeyPair holds the keys for RS256 signing. Holders of only the public key
+// can verify tokens but not issue them.
+type RSAKeyPair struct {
+	Private *rsa.PrivateKey
+	Public  *rsa.PublicKey
+}
+
+// KeyID returns the key's ID: its RFC 7638 JWK thumbprint. RS256 tokens carry
+// it in the kid header so verifiers can pick the key from the JWKS.
+func (k *RSAKeyPair) KeyID() string {
+	return jwkThumbprint(k.Public)
+}
+
+// SigningKey signs and verifies tokens with a single algorithm: RS256 when
+// RSA is set, HS256 with Secret otherwise. Tokens are only accepted with the
+// key's own algorithm, so a token can't pick how it is verified.
+type SigningKey struct {
+	Secret string
+	RSA    *RSAKeyPair
+
+	// RetiredRSA are previous RS256 keys, kept only to verify tokens issued
+	// before a key rotation until they expire
+	RetiredRSA []*RSAKeyPair
+}
+
+// HMACKey returns the key for HS256 tokens signed with secret
+func HMACKey(secret string) SigningKey {
+	return SigningKey{Secret: secret}
+}
+
+// Algorithm returns the JWT alg the key signs and verifies with
+func (k SigningKey) Algorithm() string {
+	if k.RSA != nil {
+		return AlgorithmRS256
+	}
+	return AlgorithmHS256
+}
+
+// IsSet reports whether the key has a secret or RSA keys to work with
+func (k SigningKey) IsSet() bool {
+	return k.Secret != "" || k.RSA != nil
+}
+
+// signingMethod returns the jwt signing method and key used to sign tokens
+func (k SigningKey) signingMethod() (jwt.SigningMethod, interface{}, error) {
+	if k.RSA != nil {
+		if k.RSA.Private == nil {
+			return nil, nil, fmt.Errorf("private key is required to sign tokens")
+		}
+		return jwt.SigningMethodRS256, k.RSA.Private, nil
+	}
+
+	if k.Secret == "" {
+		return nil, nil, fmt.Errorf("secret cannot be empty")
+	}
+	return jwt.SigningMethodHS256, []byte(k.Secret), nil
+}
+
+// keyID returns the kid header for tokens signed with the key, or "" for HS256
+func (k SigningKey) keyID() string {
+	if k.RSA == nil {
+		return ""
+	}
+	return k.RSA.KeyID()
+}
+
+// VerificationKeys returns the RSA keys RS256 tokens are verified with: the
+// current key first, then any retired ones
+func (k SigningKey) VerificationKeys() []*RSAKeyPair {
+	if k.RSA == nil {
+		return nil
+	}
+	return append([]*RSAKeyPair{k.RSA}, k.RetiredRSA...)
+}
+
+// verificationKey returns the key that verifies a token with the given kid
+// header. RS256 tokens must name one of the key's RSA keys; HS256 keys aren't
+// selected by kid, so HS256 tokens must not carry one.
+func (k SigningKey) verificationKey(kid interface{}) (interface{}, error) {
+	if k.RSA != nil {
+		for _, keys := range k.VerificationKeys() {
+			if keys.Public != nil && kid == keys.KeyID() {
+				return keys.Public, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key id: %v", kid)
+	}
+
+	if kid != nil {
+		return nil, fmt.Errorf("unknown key id: %v", kid)
+	}
+	return []byte(k.Secret), nil
+}
+
+// LoadRSAKeyPair reads a PEM-encoded RSA private key and, optionally, its
+// public key. Without a public key path the public key is taken from the
+// private key; with one, it must belong to the private key.
+func LoadRSAKeyPair(privateKeyPath, publicKeyPath string) (*RSAKeyPair, error) {
+	privatePEM, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	keys := &RSAKeyPair{Private: privateKey, Public: &privateKey.PublicKey}
+	if publicKeyPath == "" {
+		return keys, nil
+	}
+
+	publicPEM, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	if !publicKey.Equal(&privateKey.PublicKey) {
+		return nil, fmt.Errorf("public key does not match private key")
+	}
+
+	keys.Public = publicKey
+	return keys, nil
+}
+
+// LoadRSAPublicKey reads a PEM-encoded RSA public key, e.g. a retired signing
+// key whose tokens should still verify. The key pair has no private key.
+func LoadRSAPublicKey(publicKeyPath string) (*RSAKeyPair, error) {
+	publicPEM, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return &RSAKeyPair{Public: publicKey}, nil
+}
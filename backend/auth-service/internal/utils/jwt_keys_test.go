@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRSAKeys generates an RSA key pair for tests
+func newTestRSAKeys(t *testing.T) *RSAKeyPair {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return &RSAKeyPair{Private: privateKey, Public: &privateKey.PublicKey}
+}
+
+// TestRS256Tokens tests signing and verifying access tokens with an RSA key pair
+func TestRS256Tokens(t *testing.T) {
+	keys := newTestRSAKeys(t)
+	key := SigningKey{RSA: keys}
+	userID := uuid.New().String()
+	now := time.Now()
+
+	token, err := GenerateAccessTokenWithKey(userID, "test@example.com", AccessTokenOptions{IssuedAt: now}, 15*time.Minute, key)
+	require.NoError(t, err)
+
+	header, _, err := DecodeTokenUnverified(token)
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", header["alg"])
+
+	t.Run("verifies with the public key alone", func(t *testing.T) {
+		claims, err := ValidateTokenWithKey(token, SigningKey{RSA: &RSAKeyPair{Public: keys.Public}}, 0, now)
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, "access", claims.TokenType)
+	})
+
+	t.Run("public key alone can't sign", func(t *testing.T) {
+		_, err := GenerateAccessTokenWithKey(userID, "test@example.com", AccessTokenOptions{}, time.Minute, SigningKey{RSA: &RSAKeyPair{Public: keys.Public}})
+		assert.Error(t, err)
+	})
+
+	t.Run("another key pair is rejected", func(t *testing.T) {
+		_, err := ValidateTokenWithKey(token, SigningKey{RSA: newTestRSAKeys(t)}, 0, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("RS256 tokens are rejected by an HS256 key", func(t *testing.T) {
+		_, err := ValidateTokenWithKey(token, HMACKey(testSecret), 0, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256 tokens are rejected by an RS256 key", func(t *testing.T) {
+		hmacToken, err := GenerateAccessToken(userID, "test@example.com", 15*time.Minute, testSecret)
+		require.NoError(t, err)
+
+		_, err = ValidateTokenWithKey(hmacToken, key, 0, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256 signed with the public key is rejected", func(t *testing.T) {
+		// The classic algorithm confusion attack: the public key is known,
+		// so an attacker uses it as an HMAC secret
+		publicDER, err := x509.MarshalPKIXPublicKey(keys.Public)
+		require.NoError(t, err)
+		publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user_id":    userID,
+			"token_type": "access",
+			"exp":        now.Add(15 * time.Minute).Unix(),
+		}).SignedString(publicPEM)
+		require.NoError(t, err)
+
+		_, err = ValidateTokenWithKey(forged, key, 0, now)
+		assert.Error(t, err)
+	})
+}
+
+// TestLoadRSAKeyPair tests loading PEM keys and checking they belong together
+func TestLoadRSAKeyPair(t *testing.T) {
+	dir := t.TempDir()
+	keys := newTestRSAKeys(t)
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+		return path
+	}
+
+	privatePath := writePEM("private.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(keys.Private))
+	publicDER, err := x509.MarshalPKIXPublicKey(keys.Public)
+	require.NoError(t, err)
+	publicPath := writePEM("public.pem", "PUBLIC KEY", publicDER)
+
+	otherDER, err := x509.MarshalPKIXPublicKey(newTestRSAKeys(t).Public)
+	require.NoError(t, err)
+	otherPublicPath := writePEM("other.pem", "PUBLIC KEY", otherDER)
+
+	t.Run("public key from the private key", func(t *testing.T) {
+		loaded, err := LoadRSAKeyPair(privatePath, "")
+		require.NoError(t, err)
+		assert.True(t, loaded.Public.Equal(keys.Public))
+	})
+
+	t.Run("matching public key", func(t *testing.T) {
+		loaded, err := LoadRSAKeyPair(privatePath, publicPath)
+		require.NoError(t, err)
+		assert.True(t, loaded.Public.Equal(keys.Public))
+	})
+
+	t.Run("mismatched public key", func(t *testing.T) {
+		_, err := LoadRSAKeyPair(privatePath, otherPublicPath)
+		assert.EqualError(t, err, "public key does not match private key")
+	})
+
+	t.Run("missing private key", func(t *testing.T) {
+		_, err := LoadRSAKeyPair(filepath.Join(dir, "missing.pem"), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("not a private key", func(t *testing.T) {
+		_, err := LoadRSAKeyPair(publicPath, "")
+		assert.Error(t, err)
+	})
+}
+
+// TestRS256KeyIDs tests that RS256 tokens name their key and verify against
+// the current or a retired key during rotation
+func TestRS256KeyIDs(t *testing.T) {
+	oldKeys, newKeys := newTestRSAKeys(t), newTestRSAKeys(t)
+	userID := uuid.New().String()
+	now := time.Now()
+
+	oldToken, err := GenerateAccessTokenWithKey(userID, "test@example.com", AccessTokenOptions{IssuedAt: now}, 15*time.Minute, SigningKey{RSA: oldKeys})
+	require.NoError(t, err)
+
+	header, _, err := DecodeTokenUnverified(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, oldKeys.KeyID(), header["kid"])
+	assert.NotEqual(t, oldKeys.KeyID(), newKeys.KeyID())
+
+	rotated := SigningKey{RSA: newKeys, RetiredRSA: []*RSAKeyPair{{Public: oldKeys.Public}}}
+
+	t.Run("retired key still verifies its tokens", func(t *testing.T) {
+		_, err := ValidateTokenWithKey(oldToken, rotated, 0, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("new tokens use the current key", func(t *testing.T) {
+		token, err := GenerateAccessTokenWithKey(userID, "test@example.com", AccessTokenOptions{IssuedAt: now}, 15*time.Minute, rotated)
+		require.NoError(t, err)
+
+		header, _, err := DecodeTokenUnverified(token)
+		require.NoError(t, err)
+		assert.Equal(t, newKeys.KeyID(), header["kid"])
+
+		_, err = ValidateTokenWithKey(token, rotated, 0, now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("dropped key is rejected", func(t *testing.T) {
+		_, err := ValidateTokenWithKey(oldToken, SigningKey{RSA: newKeys}, 0, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("token without kid is rejected", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"user_id":    userID,
+			"token_type": "access",
+			"exp":        now.Add(15 * time.Minute).Unix(),
+		}).SignedString(newKeys.Private)
+		require.NoError(t, err)
+
+		_, err = ValidateTokenWithKey(token, rotated, 0, now)
+		assert.Error(t, err)
+	})
+}
+
+// TestRSAKeyID tests key IDs are RFC 7638 JWK thumbprints, using the RFC's example key
+func TestRSAKeyID(t *testing.T) {
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+
+	keys := &RSAKeyPair{Public: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}}
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", keys.KeyID())
+
+	jwks := PublicJWKS([]*RSAKeyPair{keys, {}})
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "AQAB", jwks.Keys[0].Exponent)
+	assert.Equal(t, keys.KeyID(), jwks.Keys[0].KeyID)
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -357,6 +360,62 @@ func TestTokenSecurity(t *testing.T) {
 	})
 }
 
+// TestAlgorithmConfusion tests that tokens signed with anything but HS256,
+// including alg "none", or naming a key ID are rejected
+func TestAlgorithmConfusion(t *testing.T) {
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"user_id":    uuid.New().String(),
+			"token_type": "access",
+			"exp":        time.Now().Add(15 * time.Minute).Unix(),
+		}
+	}
+
+	t.Run("alg none", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = ValidateToken(token, testSecret)
+		assert.Error(t, err)
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims()).SignedString(key)
+		require.NoError(t, err)
+
+		_, err = ValidateToken(token, testSecret)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS512 with the right secret", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims()).SignedString([]byte(testSecret))
+		require.NoError(t, err)
+
+		_, err = ValidateToken(token, testSecret)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown kid", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+		token.Header["kid"] = "other-key"
+		signed, err := token.SignedString([]byte(testSecret))
+		require.NoError(t, err)
+
+		_, err = ValidateToken(signed, testSecret)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256 without kid", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).SignedString([]byte(testSecret))
+		require.NoError(t, err)
+
+		_, err = ValidateToken(token, testSecret)
+		assert.NoError(t, err)
+	})
+}
+
 // TestExtractTokenFromHeader tests extracting token from Authorization header
 func TestExtractTokenFromHeader(t *testing.T) {
 	validToken := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIn0.abc123"
@@ -408,6 +467,25 @@ func TestExtractTokenFromHeader(t *testing.T) {
 			want:    validToken,
 			wantErr: false,
 		},
+		{
+			name:    "lowercase bearer scheme",
+			header:  "bearer " + validToken,
+			want:    validToken,
+			wantErr: false,
+		},
+		{
+			name:    "mixed case bearer scheme",
+			header:  "BeArEr " + validToken,
+			want:    validToken,
+			wantErr: false,
+		},
+		{
+			name:    "other scheme with bearer-like name",
+			header:  "Bearer2 " + validToken,
+			want:    "",
+			wantErr: true,
+			errMsg:  "expected Bearer scheme",
+		},
 	}
 
 	for _, tt := range tests {
@@ -459,6 +537,85 @@ func TestTokenClaims(t *testing.T) {
 	assert.Equal(t, "access", claims["token_type"])
 }
 
+// TestValidateTokenWithClaims tests that registered claims are surfaced
+func TestValidateTokenWithClaims(t *testing.T) {
+	userID := uuid.New().String()
+	email := "test@example.com"
+
+	before := time.Now().Add(-time.Second).Unix()
+	token, err := GenerateAccessToken(userID, email, 15*time.Minute, testSecret)
+	require.NoError(t, err)
+
+	claims, err := ValidateTokenWithClaims(token, testSecret)
+	require.NoError(t, err)
+
+	assert.Equal(t, userID, claims.Subject)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, email, claims.Email)
+	assert.Equal(t, "access", claims.TokenType)
+	assert.GreaterOrEqual(t, claims.IssuedAt, before)
+	assert.NotZero(t, claims.ExpiresAt)
+	assert.Greater(t, claims.ExpiresAt, claims.IssuedAt)
+
+	_, err = ValidateTokenWithClaims(token, "wrong-secret-key-that-is-long-enough")
+	assert.Error(t, err)
+}
+
+// TestMinimalAccessToken tests that minimal tokens omit the extra claims but still validate
+func TestMinimalAccessToken(t *testing.T) {
+	userID := uuid.New().String()
+	email := "test@example.com"
+
+	minimal, err := GenerateAccessTokenWithKey(userID, email, AccessTokenOptions{Minimal: true}, 15*time.Minute, HMACKey(testSecret))
+	require.NoError(t, err)
+	full, err := GenerateAccessToken(userID, email, 15*time.Minute, testSecret)
+	require.NoError(t, err)
+
+	payload := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(minimal, payload)
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(payload))
+	for key := range payload {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"sub", "exp", "iat", "jti", "token_type"}, keys)
+	assert.Less(t, len(minimal), len(full))
+
+	claims, err := ValidateTokenWithClaims(minimal, testSecret)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, userID, claims.Subject)
+	assert.Equal(t, "access", claims.TokenType)
+	assert.Empty(t, claims.Email)
+	assert.NotEmpty(t, claims.ID)
+
+	// A binding is kept since verifying the token depends on it
+	bound, err := GenerateAccessTokenWithKey(userID, email, AccessTokenOptions{Minimal: true, Binding: "ip:abc"}, 15*time.Minute, HMACKey(testSecret))
+	require.NoError(t, err)
+	claims, err = ValidateTokenWithClaims(bound, testSecret)
+	require.NoError(t, err)
+	assert.Equal(t, "ip:abc", claims.Binding)
+}
+
+// TestTokenID tests that every token gets a unique jti
+func TestTokenID(t *testing.T) {
+	userID := uuid.New().String()
+
+	first, err := GenerateAccessToken(userID, "test@example.com", 15*time.Minute, testSecret)
+	require.NoError(t, err)
+	second, err := GenerateAccessToken(userID, "test@example.com", 15*time.Minute, testSecret)
+	require.NoError(t, err)
+
+	firstClaims, err := ValidateTokenWithClaims(first, testSecret)
+	require.NoError(t, err)
+	secondClaims, err := ValidateTokenWithClaims(second, testSecret)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, firstClaims.ID)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+}
+
 // BenchmarkGenerateAccessToken benchmarks token generation
 func BenchmarkGenerateAccessToken(b *testing.B) {
 	userID := uuid.New().String()
@@ -513,3 +670,135 @@ func TestTokenTypeValidation(t *testing.T) {
 		assert.NotEqual(t, "access", claims.TokenType)
 	})
 }
+
+// TestValidateTokenType tests the typed validators reject valid tokens of the
+// other type with a *TokenTypeError
+func TestValidateTokenType(t *testing.T) {
+	userID := uuid.New().String()
+	email := "test@example.com"
+
+	accessToken, err := GenerateAccessToken(userID, email, 15*time.Minute, testSecret)
+	require.NoError(t, err)
+
+	refreshToken, err := GenerateRefreshToken(userID, email, 7*24*time.Hour, testSecret)
+	require.NoError(t, err)
+
+	t.Run("access token accepted by access validator", func(t *testing.T) {
+		claims, err := ValidateAccessToken(accessToken, HMACKey(testSecret), 0, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, TokenTypeAccess, claims.TokenType)
+	})
+
+	t.Run("refresh token rejected by access validator", func(t *testing.T) {
+		claims, err := ValidateAccessToken(refreshToken, HMACKey(testSecret), 0, time.Now())
+		assert.Nil(t, claims)
+
+		var typeErr *TokenTypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.Equal(t, TokenTypeAccess, typeErr.Expected)
+		assert.Equal(t, TokenTypeRefresh, typeErr.Actual)
+	})
+
+	t.Run("access token rejected by refresh validator", func(t *testing.T) {
+		_, err := ValidateRefreshToken(accessToken, HMACKey(testSecret), time.Now())
+
+		var typeErr *TokenTypeError
+		require.ErrorAs(t, err, &typeErr)
+		assert.Equal(t, TokenTypeRefresh, typeErr.Expected)
+	})
+
+	t.Run("refresh token accepted by refresh validator", func(t *testing.T) {
+		claims, err := ValidateRefreshToken(refreshToken, HMACKey(testSecret), time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, TokenTypeRefresh, claims.TokenType)
+	})
+
+	t.Run("only access tokens get expiry leeway", func(t *testing.T) {
+		_, err := ValidateAccessToken(accessToken, HMACKey(testSecret), time.Minute, time.Now().Add(15*time.Minute+30*time.Second))
+		assert.NoError(t, err)
+
+		_, err = ValidateRefreshToken(refreshToken, HMACKey(testSecret), time.Now().Add(7*24*time.Hour+30*time.Second))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid signature is not a type error", func(t *testing.T) {
+		_, err := ValidateAccessToken(refreshToken, HMACKey("different-secret-key-at-least-32-chars"), 0, time.Now())
+		require.Error(t, err)
+
+		var typeErr *TokenTypeError
+		assert.False(t, errors.As(err, &typeErr))
+	})
+}
+
+// TestMalformedTokenClaims tests that signed tokens missing required claims are rejected
+func TestMalformedTokenClaims(t *testing.T) {
+	sign := func(claims jwt.MapClaims) string {
+		claims["exp"] = time.Now().Add(15 * time.Minute).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("missing user_id and sub", func(t *testing.T) {
+		token := sign(jwt.MapClaims{"token_type": "access", "email": "test@example.com"})
+
+		_, err := ValidateToken(token, testSecret)
+		assert.ErrorIs(t, err, ErrMalformedClaims)
+	})
+
+	t.Run("missing token_type", func(t *testing.T) {
+		token := sign(jwt.MapClaims{"user_id": uuid.New().String(), "email": "test@example.com"})
+
+		_, err := ValidateToken(token, testSecret)
+		assert.ErrorIs(t, err, ErrMalformedClaims)
+	})
+
+	t.Run("empty token_type", func(t *testing.T) {
+		token := sign(jwt.MapClaims{"user_id": uuid.New().String(), "token_type": ""})
+
+		_, err := ValidateTokenWithClaims(token, testSecret)
+		assert.ErrorIs(t, err, ErrMalformedClaims)
+	})
+
+	t.Run("sub stands in for user_id", func(t *testing.T) {
+		userID := uuid.New().String()
+		token := sign(jwt.MapClaims{"sub": userID, "token_type": "access"})
+
+		claims, err := ValidateToken(token, testSecret)
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+	})
+}
+
+// TestSigningKeys tests selecting the signing secret by token type
+func TestSigningKeys(t *testing.T) {
+	userID := uuid.New().String()
+	email := "test@example.com"
+	refreshSecret := "refresh-secret-key-at-least-32-characters-long"
+
+	t.Run("refresh falls back to the access secret", func(t *testing.T) {
+		keys := SigningKeys{Access: testSecret}
+		assert.Equal(t, testSecret, keys.ForType("access"))
+		assert.Equal(t, testSecret, keys.ForType("refresh"))
+	})
+
+	t.Run("separate secrets do not validate each other's tokens", func(t *testing.T) {
+		keys := SigningKeys{Access: testSecret, Refresh: refreshSecret}
+
+		accessToken, err := GenerateAccessToken(userID, email, 15*time.Minute, keys.ForType("access"))
+		require.NoError(t, err)
+		refreshToken, err := GenerateRefreshToken(userID, email, 7*24*time.Hour, keys.ForType("refresh"))
+		require.NoError(t, err)
+
+		_, err = ValidateToken(accessToken, keys.ForType("access"))
+		assert.NoError(t, err)
+		_, err = ValidateToken(refreshToken, keys.ForType("refresh"))
+		assert.NoError(t, err)
+
+		_, err = ValidateToken(accessToken, keys.ForType("refresh"))
+		assert.Error(t, err)
+		_, err = ValidateToken(refreshToken, keys.ForType("access"))
+		assert.Error(t, err)
+	})
+}
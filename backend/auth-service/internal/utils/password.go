@@ -7,6 +7,17 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// MaxPasswordBytes is bcrypt's input limit. bcrypt silently ignores anything
+// beyond it, so longer passwords are rejected rather than truncated. The
+// limit is in bytes, not characters: multi-byte UTF-8 characters count more
+// than once.
+const MaxPasswordBytes = 72
+
+// PasswordTooLong reports whether a password exceeds maxBytes bytes of UTF-8
+func PasswordTooLong(password string, maxBytes int) bool {
+	return len([]byte(password)) > maxBytes
+}
+
 // Common weak passwords to reject
 var commonPasswords = map[string]bool{
 	"password":    true,
@@ -30,8 +41,8 @@ func HashPassword(password string) (string, error) {
 		return "", fmt.Errorf("password cannot be empty")
 	}
 
-	if len(password) > 72 {
-		return "", fmt.Errorf("password too long: maximum 72 bytes")
+	if PasswordTooLong(password, MaxPasswordBytes) {
+		return "", fmt.Errorf("password too long: maximum %d bytes", MaxPasswordBytes)
 	}
 
 	// Generate hash with default cost (12)
@@ -43,13 +54,13 @@ func HashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
-// ComparePasswords compares a hashed password with a plain text password
+// defaultHashRegistry verifies hashes from any built-in scheme
+var defaultHashRegistry = DefaultHashRegistry()
+
+// ComparePasswords compares a hashed password with a plain text password,
+// using whichever hash scheme produced the hash
 func ComparePasswords(hashedPassword, password string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	if err != nil {
-		return fmt.Errorf("invalid password")
-	}
-	return nil
+	return defaultHashRegistry.Verify(hashedPassword, password)
 }
 
 // ValidatePasswordStrength validates password meets strength requirements
@@ -58,8 +69,8 @@ func ValidatePasswordStrength(password string) error {
 		return fmt.Errorf("password must be at least 8 characters long")
 	}
 
-	if len(password) > 72 {
-		return fmt.Errorf("password too long: maximum 72 bytes")
+	if PasswordTooLong(password, MaxPasswordBytes) {
+		return fmt.Errorf("password too long: maximum %d bytes", MaxPasswordBytes)
 	}
 
 	// Check for uppercase
@@ -34,6 +34,12 @@ func TestHashPassword(t *testing.T) {
 			wantErr:   true,
 			errString: "password too long",
 		},
+		{
+			name:      "multi-byte password over 72 bytes",
+			password:  strings.Repeat("é", 37), // 37 characters, 74 bytes
+			wantErr:   true,
+			errString: "password too long",
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,6 +172,12 @@ func TestValidatePasswordStrength(t *testing.T) {
 			wantErr:   true,
 			errString: "at least 8 characters",
 		},
+		{
+			name:      "multi-byte password over 72 bytes",
+			password:  "Secure1!" + strings.Repeat("€", 22), // 30 characters, 74 bytes
+			wantErr:   true,
+			errString: "password too long",
+		},
 		{
 			name:      "common password",
 			password:  "Password123!",
@@ -194,6 +206,27 @@ func TestValidatePasswordStrength(t *testing.T) {
 	}
 }
 
+// TestPasswordTooLong tests that password length is measured in bytes
+func TestPasswordTooLong(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		want     bool
+	}{
+		{"72 ASCII bytes", strings.Repeat("a", 72), false},
+		{"73 ASCII bytes", strings.Repeat("a", 73), true},
+		{"36 two-byte characters", strings.Repeat("é", 36), false},
+		{"37 two-byte characters", strings.Repeat("é", 37), true},
+		{"19 four-byte characters", strings.Repeat("🔒", 19), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PasswordTooLong(tt.password, MaxPasswordBytes))
+		})
+	}
+}
+
 // BenchmarkHashPassword benchmarks password hashing
 func BenchmarkHashPassword(b *testing.B) {
 	password := "SecurePass123!"
@@ -0,0 +1,39 @@
+package utils
+
+import "strings"
+
+// NormalizePhone puts a phone number in the form it is stored and compared
+// in: separators (spaces, dashes, dots, parentheses) are removed and a
+// leading international "00" becomes "+". An empty or separator-only number
+// normalizes to "", meaning no phone.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(phone) {
+		switch r {
+		case ' ', '-', '.', '(', ')', '\t':
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	normalized := b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	return normalized
+}
+
+// IsValidPhone reports whether a normalized phone number is 7 to 15 digits,
+// optionally with a leading "+" (E.164 allows at most 15 digits)
+func IsValidPhone(phone string) bool {
+	digits := strings.TrimPrefix(phone, "+")
+	if len(digits) < 7 || len(digits) > 15 {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizePhone tests phone numbers are reduced to one stored form
+func TestNormalizePhone(t *testing.T) {
+	tests := map[string]string{
+		"+447700900123":       "+447700900123",
+		"+44 7700 900123":     "+447700900123",
+		" +44 (20) 7946-0958": "+442079460958",
+		"00447700900123":      "+447700900123",
+		"07700 900123":        "07700900123",
+		"":                    "",
+		"  ":                  "",
+		" - ":                 "",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, NormalizePhone(input), "input %q", input)
+	}
+}
+
+// TestIsValidPhone tests the phone format check on normalized numbers
+func TestIsValidPhone(t *testing.T) {
+	tests := map[string]bool{
+		"+447700900123":     true,
+		"07700900123":       true,
+		"+1234567":          true,
+		"+123456":           false,
+		"+1234567890123456": false,
+		"+44770090012x":     false,
+		"++447700900123":    false,
+		"":                  false,
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, IsValidPhone(input), "input %q", input)
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// postcodeFormats holds the postcode format of each country with a known
+// format, keyed by ISO 3166-1 alpha-2 code. Postcodes are matched upper-cased
+// with surrounding whitespace trimmed.
+var postcodeFormats = map[string]*regexp.Regexp{
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}$`),
+	"IE": regexp.MustCompile(`^[A-Z][0-9][0-9W] ?[A-Z0-9]{4}$`),
+	"US": regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z][0-9][A-Z] ?[0-9][A-Z][0-9]$`),
+	"AU": regexp.MustCompile(`^[0-9]{4}$`),
+	"DE": regexp.MustCompile(`^[0-9]{5}$`),
+	"FR": regexp.MustCompile(`^[0-9]{5}$`),
+	"ES": regexp.MustCompile(`^[0-9]{5}$`),
+	"IT": regexp.MustCompile(`^[0-9]{5}$`),
+	"NL": regexp.MustCompile(`^[0-9]{4} ?[A-Z]{2}$`),
+}
+
+// postcodeCountryAliases maps country codes in common use that aren't ISO
+// 3166-1 alpha-2 to the ISO code
+var postcodeCountryAliases = map[string]string{
+	"UK": "GB",
+}
+
+// PostcodeMatchesCountry reports whether postcode has the format used in
+// country. known is false for countries without a known format, whose
+// postcodes always match.
+func PostcodeMatchesCountry(postcode, country string) (matches, known bool) {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	if alias, ok := postcodeCountryAliases[code]; ok {
+		code = alias
+	}
+
+	format, ok := postcodeFormats[code]
+	if !ok {
+		return true, false
+	}
+
+	return format.MatchString(strings.ToUpper(strings.TrimSpace(postcode))), true
+}
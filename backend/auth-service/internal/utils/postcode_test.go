@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostcodeMatchesCountry tests postcodes are checked against the declared country's format
+func TestPostcodeMatchesCountry(t *testing.T) {
+	tests := []struct {
+		name     string
+		postcode string
+		country  string
+		matches  bool
+		known    bool
+	}{
+		{"UK postcode in UK", "SW1A 1AA", "UK", true, true},
+		{"UK postcode in GB", "sw1a1aa", "gb", true, true},
+		{"short UK postcode", "M1 1AE", "GB", true, true},
+		{"US ZIP code in UK", "90210", "UK", false, true},
+		{"US ZIP+4 in US", "90210-1234", "US", true, true},
+		{"UK postcode in US", "SW1A 1AA", "US", false, true},
+		{"Canadian postcode", "K1A 0B1", "CA", true, true},
+		{"Dutch postcode", "1012 AB", "NL", true, true},
+		{"Irish Eircode", "D02 AF30", "IE", true, true},
+		{"unknown country", "anything", "ZZ", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, known := PostcodeMatchesCountry(tt.postcode, tt.country)
+			assert.Equal(t, tt.matches, matches)
+			assert.Equal(t, tt.known, known)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 // Common error types
@@ -17,26 +19,57 @@ var (
 	// User errors
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrPhoneInUse        = errors.New("phone number already in use")
 	ErrUserInactive      = errors.New("user account is inactive")
+	ErrDeviceInUse       = errors.New("device is registered to another user")
+	ErrEmailNotVerified  = errors.New("email address is not verified")
 
 	// Validation errors
-	ErrInvalidInput      = errors.New("invalid input")
-	ErrInvalidEmail      = errors.New("invalid email format")
-	ErrWeakPassword      = errors.New("password does not meet strength requirements")
-	ErrPasswordMismatch  = errors.New("passwords do not match")
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrInvalidEmail     = errors.New("invalid email format")
+	ErrWeakPassword     = errors.New("password does not meet strength requirements")
+	ErrPasswordMismatch = errors.New("passwords do not match")
 
 	// Rate limiting
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 
 	// Internal errors
-	ErrInternal         = errors.New("internal server error")
-	ErrDatabaseError    = errors.New("database error")
-	ErrCacheError       = errors.New("cache error")
+	ErrInternal      = errors.New("internal server error")
+	ErrDatabaseError = errors.New("database error")
+	ErrCacheError    = errors.New("cache error")
+
+	// Availability errors
+	ErrServiceUnavailable = errors.New("service temporarily unavailable")
+)
+
+// ErrorCode is a stable, machine-readable error identifier exposed to clients.
+// Clients should switch on codes rather than match messages.
+type ErrorCode string
+
+// Error codes
+const (
+	CodeInvalidInput       ErrorCode = "INVALID_INPUT"
+	CodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	CodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
+	CodeForbidden          ErrorCode = "FORBIDDEN"
+	CodeAccountPending     ErrorCode = "ACCOUNT_PENDING"
+	CodeAccountSuspended   ErrorCode = "ACCOUNT_SUSPENDED"
+	CodeAccountClosed      ErrorCode = "ACCOUNT_CLOSED"
+	CodeNotFound           ErrorCode = "NOT_FOUND"
+	CodeUserExists         ErrorCode = "USER_EXISTS"
+	CodeDeviceInUse        ErrorCode = "DEVICE_IN_USE"
+	CodeEmailNotVerified   ErrorCode = "EMAIL_NOT_VERIFIED"
+	CodeRateLimited        ErrorCode = "RATE_LIMITED"
+	CodeInternal           ErrorCode = "INTERNAL_ERROR"
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeHTTPSRequired      ErrorCode = "HTTPS_REQUIRED"
 )
 
 // AppError represents an application error with HTTP status code
 type AppError struct {
 	Err        error
+	Code       ErrorCode
 	Message    string
 	StatusCode int
 	Internal   error // Internal error for logging (not exposed to client)
@@ -58,19 +91,43 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
-// NewAppError creates a new application error
+// NewAppError creates a new application error, deriving the code from the status
 func NewAppError(err error, message string, statusCode int) *AppError {
 	return &AppError{
 		Err:        err,
+		Code:       codeForStatus(statusCode),
 		Message:    message,
 		StatusCode: statusCode,
 	}
 }
 
+// codeForStatus maps an HTTP status to the generic error code for it
+func codeForStatus(statusCode int) ErrorCode {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodeInvalidInput
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeUserExists
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternal
+	}
+}
+
 // NewBadRequest creates a 400 Bad Request error
 func NewBadRequest(message string) *AppError {
 	return &AppError{
 		Err:        ErrInvalidInput,
+		Code:       CodeInvalidInput,
 		Message:    message,
 		StatusCode: http.StatusBadRequest,
 	}
@@ -80,6 +137,17 @@ func NewBadRequest(message string) *AppError {
 func NewUnauthorized(message string) *AppError {
 	return &AppError{
 		Err:        ErrUnauthorized,
+		Code:       CodeUnauthorized,
+		Message:    message,
+		StatusCode: http.StatusUnauthorized,
+	}
+}
+
+// NewInvalidCredentials creates a 401 error for a failed login
+func NewInvalidCredentials(message string) *AppError {
+	return &AppError{
+		Err:        ErrInvalidCredentials,
+		Code:       CodeInvalidCredentials,
 		Message:    message,
 		StatusCode: http.StatusUnauthorized,
 	}
@@ -89,6 +157,19 @@ func NewUnauthorized(message string) *AppError {
 func NewNotFound(message string) *AppError {
 	return &AppError{
 		Err:        ErrUserNotFound,
+		Code:       CodeNotFound,
+		Message:    message,
+		StatusCode: http.StatusNotFound,
+	}
+}
+
+// NewInactiveNotFound creates a 404 Not Found error for a user lookup that
+// only matches active accounts. It wraps ErrUserInactive so callers can tell
+// it apart from a user that does not exist at all.
+func NewInactiveNotFound(message string) *AppError {
+	return &AppError{
+		Err:        ErrUserInactive,
+		Code:       CodeNotFound,
 		Message:    message,
 		StatusCode: http.StatusNotFound,
 	}
@@ -98,6 +179,29 @@ func NewNotFound(message string) *AppError {
 func NewForbidden(message string) *AppError {
 	return &AppError{
 		Err:        ErrUserInactive,
+		Code:       CodeForbidden,
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
+// NewAccountStatusError creates a 403 Forbidden error for an account that is
+// not active, with a code identifying the account's status
+func NewAccountStatusError(code ErrorCode, message string) *AppError {
+	return &AppError{
+		Err:        ErrUserInactive,
+		Code:       code,
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
+// NewEmailNotVerified creates a 403 Forbidden error for a user who hasn't
+// verified their email address
+func NewEmailNotVerified(message string) *AppError {
+	return &AppError{
+		Err:        ErrEmailNotVerified,
+		Code:       CodeEmailNotVerified,
 		Message:    message,
 		StatusCode: http.StatusForbidden,
 	}
@@ -107,6 +211,28 @@ func NewForbidden(message string) *AppError {
 func NewConflict(message string) *AppError {
 	return &AppError{
 		Err:        ErrUserAlreadyExists,
+		Code:       CodeUserExists,
+		Message:    message,
+		StatusCode: http.StatusConflict,
+	}
+}
+
+// NewPhoneConflict creates a 409 Conflict error for a phone number that
+// belongs to another user. It carries the same code as NewConflict.
+func NewPhoneConflict(message string) *AppError {
+	return &AppError{
+		Err:        ErrPhoneInUse,
+		Code:       CodeUserExists,
+		Message:    message,
+		StatusCode: http.StatusConflict,
+	}
+}
+
+// NewDeviceConflict creates a 409 Conflict error for a device ID bound to another user
+func NewDeviceConflict(message string) *AppError {
+	return &AppError{
+		Err:        ErrDeviceInUse,
+		Code:       CodeDeviceInUse,
 		Message:    message,
 		StatusCode: http.StatusConflict,
 	}
@@ -116,21 +242,158 @@ func NewConflict(message string) *AppError {
 func NewTooManyRequests(message string) *AppError {
 	return &AppError{
 		Err:        ErrRateLimitExceeded,
+		Code:       CodeRateLimited,
 		Message:    message,
 		StatusCode: http.StatusTooManyRequests,
 	}
 }
 
+// NewServiceUnavailable creates a 503 Service Unavailable error
+func NewServiceUnavailable(message string) *AppError {
+	return &AppError{
+		Err:        ErrServiceUnavailable,
+		Code:       CodeServiceUnavailable,
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+	}
+}
+
 // NewInternalError creates a 500 Internal Server Error
 func NewInternalError(err error, message string) *AppError {
 	return &AppError{
 		Err:        ErrInternal,
+		Code:       CodeInternal,
 		Message:    message,
 		StatusCode: http.StatusInternalServerError,
 		Internal:   err,
 	}
 }
 
+// ValidationError represents a 400 error carrying per-field validation messages
+type ValidationError struct {
+	*AppError
+	Fields map[string]string
+
+	causes []error
+}
+
+// NewValidationError creates a validation error with no field messages yet
+func NewValidationError() *ValidationError {
+	return &ValidationError{
+		AppError: &AppError{
+			Err:        ErrInvalidInput,
+			Code:       CodeValidationFailed,
+			Message:    "validation failed",
+			StatusCode: http.StatusBadRequest,
+		},
+		Fields: make(map[string]string),
+	}
+}
+
+// Add records a validation message for a field, keeping the first one reported
+func (e *ValidationError) Add(field, message string) {
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+}
+
+// AddCause records the error behind a field message, so errors.Is and
+// errors.As still find it through the ValidationError
+func (e *ValidationError) AddCause(err error) {
+	e.causes = append(e.causes, err)
+}
+
+// HasErrors reports whether any field failed validation
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+// Error implements the error interface, listing each failed field
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, e.Fields[field]))
+	}
+
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the underlying AppError, so GetAppError keeps working,
+// followed by any recorded causes
+func (e *ValidationError) Unwrap() []error {
+	return append([]error{e.AppError}, e.causes...)
+}
+
+// GetValidationError extracts ValidationError from an error chain
+func GetValidationError(err error) *ValidationError {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr
+	}
+	return nil
+}
+
+// PasswordPolicyError represents a 400 error listing every password rule a
+// new password failed, so users can fix them all at once
+type PasswordPolicyError struct {
+	*AppError
+	Violations []string
+}
+
+// NewPasswordPolicyError creates a password policy error with no violations yet
+func NewPasswordPolicyError() *PasswordPolicyError {
+	return &PasswordPolicyError{
+		AppError: &AppError{
+			Err:        ErrWeakPassword,
+			Code:       CodeValidationFailed,
+			Message:    "password does not meet the password policy",
+			StatusCode: http.StatusBadRequest,
+		},
+	}
+}
+
+// Add records a failed rule
+func (e *PasswordPolicyError) Add(message string) {
+	e.Violations = append(e.Violations, message)
+}
+
+// HasErrors reports whether any rule failed
+func (e *PasswordPolicyError) HasErrors() bool {
+	return len(e.Violations) > 0
+}
+
+// Error implements the error interface, listing each failed rule
+func (e *PasswordPolicyError) Error() string {
+	if len(e.Violations) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(e.Violations, "; "))
+}
+
+// Unwrap returns the underlying AppError so GetAppError keeps working
+func (e *PasswordPolicyError) Unwrap() error {
+	return e.AppError
+}
+
+// GetPasswordPolicyError extracts PasswordPolicyError from an error chain
+func GetPasswordPolicyError(err error) *PasswordPolicyError {
+	var policyErr *PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr
+	}
+	return nil
+}
+
 // WrapError wraps an error with additional context
 func WrapError(err error, message string) error {
 	return fmt.Errorf("%s: %w", message, err)
@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorCodes tests that each constructor sets a stable error code
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *AppError
+		code       ErrorCode
+		statusCode int
+	}{
+		{"bad request", NewBadRequest("bad"), CodeInvalidInput, http.StatusBadRequest},
+		{"unauthorized", NewUnauthorized("no"), CodeUnauthorized, http.StatusUnauthorized},
+		{"invalid credentials", NewInvalidCredentials("invalid email or password"), CodeInvalidCredentials, http.StatusUnauthorized},
+		{"forbidden", NewForbidden("no"), CodeForbidden, http.StatusForbidden},
+		{"account status", NewAccountStatusError(CodeAccountSuspended, "account is suspended"), CodeAccountSuspended, http.StatusForbidden},
+		{"not found", NewNotFound("missing"), CodeNotFound, http.StatusNotFound},
+		{"inactive not found", NewInactiveNotFound("user not found"), CodeNotFound, http.StatusNotFound},
+		{"email not verified", NewEmailNotVerified("verify your email"), CodeEmailNotVerified, http.StatusForbidden},
+		{"conflict", NewConflict("exists"), CodeUserExists, http.StatusConflict},
+		{"device conflict", NewDeviceConflict("device is registered to another account"), CodeDeviceInUse, http.StatusConflict},
+		{"too many requests", NewTooManyRequests("slow down"), CodeRateLimited, http.StatusTooManyRequests},
+		{"service unavailable", NewServiceUnavailable("busy"), CodeServiceUnavailable, http.StatusServiceUnavailable},
+		{"internal", NewInternalError(errors.New("boom"), "oops"), CodeInternal, http.StatusInternalServerError},
+		{"app error from status", NewAppError(ErrTokenInvalid, "bad token", http.StatusUnauthorized), CodeUnauthorized, http.StatusUnauthorized},
+		{"validation", NewValidationError().AppError, CodeValidationFailed, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, tt.err.Code)
+			assert.Equal(t, tt.statusCode, tt.err.StatusCode)
+		})
+	}
+}
+
+// TestGetAppErrorPreservesCode tests that codes survive error wrapping
+func TestGetAppErrorPreservesCode(t *testing.T) {
+	wrapped := fmt.Errorf("login: %w", NewInvalidCredentials("invalid email or password"))
+
+	appErr := GetAppError(wrapped)
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, CodeInvalidCredentials, appErr.Code)
+	}
+
+	validationErr := NewValidationError()
+	validationErr.Add("first_name", "is required")
+	appErr = GetAppError(validationErr)
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, CodeValidationFailed, appErr.Code)
+	}
+}
+
+// TestValidationErrorCauses tests that errors recorded as causes are found
+// through a ValidationError without changing its code
+func TestValidationErrorCauses(t *testing.T) {
+	passwordErr := NewPasswordPolicyError()
+	passwordErr.Add("password must contain at least one number")
+
+	validationErr := NewValidationError()
+	validationErr.Add("password", "password must contain at least one number")
+	validationErr.AddCause(passwordErr)
+
+	assert.ErrorIs(t, validationErr, ErrWeakPassword)
+	assert.ErrorIs(t, validationErr, ErrInvalidInput)
+	assert.Same(t, validationErr, GetValidationError(validationErr))
+	if appErr := GetAppError(validationErr); assert.NotNil(t, appErr) {
+		assert.Equal(t, CodeValidationFailed, appErr.Code)
+	}
+}
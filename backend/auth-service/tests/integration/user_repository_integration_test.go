@@ -0,0 +1,141 @@
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/protobankbankc/auth-service/internal/models"
+	"github.com/protobankbankc/auth-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCountByKYCStatus tests grouped KYC status counts against a real database.
+// It seeds users and compares counts before and after, so existing rows don't matter.
+func TestCountByKYCStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	repo := repository.NewUserRepository(pool)
+
+	before, err := repo.CountByKYCStatus(ctx)
+	require.NoError(t, err)
+
+	// Mixed account and KYC statuses
+	seeded := []struct {
+		status    models.UserStatus
+		kycStatus string
+	}{
+		{models.UserStatusActive, "verified"},
+		{models.UserStatusActive, "verified"},
+		{models.UserStatusActive, "pending"},
+		{models.UserStatusSuspended, "review"},
+		{models.UserStatusClosed, "failed"},
+		{models.UserStatusPending, "pending"},
+	}
+
+	suffix := time.Now().Format("20060102150405.000000")
+	for i, seed := range seeded {
+		user := &models.User{
+			ID:           uuid.New(),
+			Email:        fmt.Sprintf("kyc-count-%d-%s@example.com", i, suffix),
+			Phone:        fmt.Sprintf("+4477%09d", time.Now().UnixNano()%1000000000+int64(i)),
+			PasswordHash: "not-a-real-hash",
+			FirstName:    "Kyc",
+			LastName:     "Count",
+			DateOfBirth:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			Country:      "GB",
+			Status:       seed.status,
+		}
+		require.NoError(t, repo.Create(ctx, user))
+		defer repo.Delete(ctx, user.ID)
+
+		if seed.kycStatus != "pending" {
+			require.NoError(t, repo.UpdateKYCStatus(ctx, user.ID, seed.kycStatus, nil))
+		}
+	}
+
+	after, err := repo.CountByKYCStatus(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, after["verified"]-before["verified"])
+	assert.Equal(t, 2, after["pending"]-before["pending"])
+	assert.Equal(t, 1, after["review"]-before["review"])
+	assert.Equal(t, 1, after["failed"]-before["failed"])
+}
+
+// TestPhoneUniqueness tests the partial unique index on non-empty phones
+func TestPhoneUniqueness(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	repo := repository.NewUserRepository(pool)
+	suffix := time.Now().Format("20060102150405.000000")
+
+	newUser := func(name, phone string) *models.User {
+		return &models.User{
+			Email:        fmt.Sprintf("phone-%s-%s@example.com", name, suffix),
+			Phone:        phone,
+			PasswordHash: "not-a-real-hash",
+			FirstName:    "Phone",
+			LastName:     "Unique",
+			DateOfBirth:  time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			Country:      "GB",
+		}
+	}
+
+	t.Run("same phone is rejected", func(t *testing.T) {
+		phone := fmt.Sprintf("+4477%09d", time.Now().UnixNano()%1000000000)
+
+		first := newUser("first", phone)
+		require.NoError(t, repo.Create(ctx, first))
+		defer repo.Delete(ctx, first.ID)
+
+		// Formatting differences don't get around the index
+		second := newUser("second", phone[:3]+" "+phone[3:7]+" "+phone[7:])
+		err := repo.Create(ctx, second)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "phone number already exists")
+	})
+
+	t.Run("empty phones are allowed", func(t *testing.T) {
+		first := newUser("empty-1", "")
+		require.NoError(t, repo.Create(ctx, first))
+		defer repo.Delete(ctx, first.ID)
+
+		second := newUser("empty-2", " ")
+		require.NoError(t, repo.Create(ctx, second))
+		defer repo.Delete(ctx, second.ID)
+
+		assert.Empty(t, second.Phone)
+	})
+}
@@ -23,7 +23,7 @@ CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 CREATE TABLE users (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     email VARCHAR(255) UNIQUE NOT NULL,
-    phone VARCHAR(20) UNIQUE NOT NULL,
+    phone VARCHAR(20) NOT NULL DEFAULT '',
     password_hash VARCHAR(255) NOT NULL,
     first_name VARCHAR(100) NOT NULL,
     last_name VARCHAR(100) NOT NULL,
@@ -35,19 +35,108 @@ CREATE TABLE users (
     country VARCHAR(2) DEFAULT 'GB',
     kyc_status VARCHAR(20) DEFAULT 'pending',
     kyc_verified_at TIMESTAMP,
-    is_active BOOLEAN DEFAULT true,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    is_active BOOLEAN GENERATED ALWAYS AS (status = 'active') STORED,
+    email_verified BOOLEAN NOT NULL DEFAULT false,
+    closure_requested_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 
-    CONSTRAINT chk_kyc_status CHECK (kyc_status IN ('pending', 'verified', 'failed', 'review'))
+    CONSTRAINT chk_kyc_status CHECK (kyc_status IN ('pending', 'verified', 'failed', 'review')),
+    CONSTRAINT chk_user_status CHECK (status IN ('active', 'pending', 'suspended', 'closed'))
 );
 
 CREATE INDEX idx_users_email ON users(email);
-CREATE INDEX idx_users_phone ON users(phone);
+-- Phones are stored normalized (see utils.NormalizePhone) and unique only when
+-- set: '' means no phone and may repeat. The repository maps violations of
+-- this index to a phone-specific conflict by name.
+CREATE UNIQUE INDEX idx_users_phone_unique ON users(phone) WHERE phone <> '';
 CREATE INDEX idx_users_kyc_status ON users(kyc_status);
+CREATE INDEX idx_users_identity ON users(lower(trim(last_name)), date_of_birth, upper(replace(postcode, ' ', '')));
+CREATE INDEX idx_users_closure_requested_at ON users(closure_requested_at)
+    WHERE closure_requested_at IS NOT NULL AND status <> 'closed';
 
 COMMENT ON TABLE users IS 'Core user accounts with KYC verification';
 COMMENT ON COLUMN users.kyc_status IS 'Know Your Customer verification status';
+COMMENT ON COLUMN users.status IS 'Account status: active, pending (verification), suspended or closed';
+COMMENT ON COLUMN users.is_active IS 'Derived from status; kept for compatibility';
+COMMENT ON COLUMN users.closure_requested_at IS 'When the user asked to close the account; closed and anonymized after the cooling-off period';
+COMMENT ON COLUMN users.email_verified IS 'Whether the user proved they own the email by following a verification link'
+
+-- AUDIT EVENTS TABLE
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    country VARCHAR(2),
+    asn BIGINT,
+    as_organization VARCHAR(255),
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_user_id ON audit_events(user_id, created_at DESC);
+CREATE INDEX idx_audit_events_event_type ON audit_events(event_type, created_at DESC);
+
+COMMENT ON TABLE audit_events IS 'Security audit trail (login attempts etc.) with optional GeoIP enrichment';
+
+-- REFRESH TOKENS TABLE
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
+
+COMMENT ON TABLE refresh_tokens IS 'Issued refresh tokens (SHA-256 hashed) for rotation; tokens rotated from one login share a family_id';
+
+-- SESSIONS TABLE
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(255),
+    device_type VARCHAR(50),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_active ON sessions(user_id, last_seen_at DESC) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE sessions IS 'Signed-in devices, one per refresh token; revoking a session invalidates its refresh token';
+
+-- DEVICE BINDINGS TABLE
+CREATE TABLE device_bindings (
+    device_id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_device_bindings_user ON device_bindings(user_id);
+
+COMMENT ON TABLE device_bindings IS 'Device IDs bound to the first user to sign in or register with them, when unique device binding is enforced';
+
+-- EMAIL VERIFICATIONS TABLE
+CREATE TABLE email_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE email_verifications IS 'Outstanding email verification token (SHA-256 hashed) per user; resending replaces it and verifying deletes it';
 
 -- ACCOUNTS TABLE
 CREATE TABLE accounts (
@@ -0,0 +1,24 @@
+-- ============================================================================
+-- Replace users.is_active with a typed account status
+-- ============================================================================
+-- For databases created before users.status existed; fresh databases get it
+-- from database_schema.sql. Inactive users were deactivated through
+-- SetInactive, so they become 'closed'. is_active is kept as a column derived
+-- from status for existing readers.
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
+
+UPDATE users SET status = 'closed' WHERE is_active = false;
+
+ALTER TABLE users ADD CONSTRAINT chk_user_status
+    CHECK (status IN ('active', 'pending', 'suspended', 'closed'));
+
+ALTER TABLE users DROP COLUMN is_active;
+ALTER TABLE users ADD COLUMN is_active BOOLEAN GENERATED ALWAYS AS (status = 'active') STORED;
+
+COMMENT ON COLUMN users.status IS 'Account status: active, pending (verification), suspended or closed';
+COMMENT ON COLUMN users.is_active IS 'Derived from status; kept for compatibility';
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- ============================================================================
+-- Enforce unique phone numbers only when a phone is set
+-- ============================================================================
+-- For databases created with phone UNIQUE NOT NULL; fresh databases get the
+-- partial index from database_schema.sql. Phones are normalized the way
+-- utils.NormalizePhone does it (separators removed, leading 00 becomes +).
+-- If two stored numbers only differed in formatting, creating the index
+-- fails; resolve those rows and re-run.
+
+BEGIN;
+
+UPDATE users
+SET phone = regexp_replace(
+    regexp_replace(phone, '[\s().-]', '', 'g'),
+    '^00', '+'
+);
+
+ALTER TABLE users ALTER COLUMN phone SET DEFAULT '';
+ALTER TABLE users DROP CONSTRAINT users_phone_key;
+DROP INDEX IF EXISTS idx_users_phone;
+
+CREATE UNIQUE INDEX idx_users_phone_unique ON users(phone) WHERE phone <> '';
+
+COMMIT;
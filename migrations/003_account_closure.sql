@@ -0,0 +1,16 @@
+-- ============================================================================
+-- Track account closure requests awaiting their cooling-off period
+-- ============================================================================
+-- For databases created before closure requests existed; fresh databases get
+-- the column from database_schema.sql.
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN closure_requested_at TIMESTAMP;
+
+CREATE INDEX idx_users_closure_requested_at ON users(closure_requested_at)
+    WHERE closure_requested_at IS NOT NULL AND status <> 'closed';
+
+COMMENT ON COLUMN users.closure_requested_at IS 'When the user asked to close the account; closed and anonymized after the cooling-off period';
+
+COMMIT;
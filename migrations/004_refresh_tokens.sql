@@ -0,0 +1,25 @@
+-- ============================================================================
+-- Store issued refresh tokens for rotation and reuse detection
+-- ============================================================================
+-- For databases created before refresh token rotation existed; fresh databases
+-- get the table from database_schema.sql.
+
+BEGIN;
+
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
+
+COMMENT ON TABLE refresh_tokens IS 'Issued refresh tokens (SHA-256 hashed) for rotation; tokens rotated from one login share a family_id';
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- ============================================================================
+-- Track signed-in devices, one session per refresh token
+-- ============================================================================
+-- For databases created before sessions existed; fresh databases get the
+-- table from database_schema.sql.
+
+BEGIN;
+
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(255),
+    device_type VARCHAR(50),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_active ON sessions(user_id, last_seen_at DESC) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE sessions IS 'Signed-in devices, one per refresh token; revoking a session invalidates its refresh token';
+
+COMMIT;
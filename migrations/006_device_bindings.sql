@@ -0,0 +1,19 @@
+-- ============================================================================
+-- Bind device IDs to a single user
+-- ============================================================================
+-- For databases created before device bindings existed; fresh databases get
+-- the table from database_schema.sql.
+
+BEGIN;
+
+CREATE TABLE device_bindings (
+    device_id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_device_bindings_user ON device_bindings(user_id);
+
+COMMENT ON TABLE device_bindings IS 'Device IDs bound to the first user to sign in or register with them, when unique device binding is enforced';
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- ============================================================================
+-- Record login attempts and other security events in an audit trail
+-- ============================================================================
+-- For databases created before the audit trail existed; fresh databases get
+-- the table from database_schema.sql. The country and ASN columns are filled
+-- from GeoIP lookups when they're enabled.
+
+BEGIN;
+
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    country VARCHAR(2),
+    asn BIGINT,
+    as_organization VARCHAR(255),
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_user_id ON audit_events(user_id, created_at DESC);
+CREATE INDEX idx_audit_events_event_type ON audit_events(event_type, created_at DESC);
+
+COMMENT ON TABLE audit_events IS 'Security audit trail (login attempts etc.) with optional GeoIP enrichment';
+
+COMMIT;
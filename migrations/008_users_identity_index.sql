@@ -0,0 +1,13 @@
+-- ============================================================================
+-- Index users by identity for duplicate registration detection
+-- ============================================================================
+-- For databases created before duplicate identity detection existed; fresh
+-- databases get the index from database_schema.sql. The expressions match
+-- the repository's lookup (last name, date of birth and postcode, compared
+-- ignoring case and spacing), so it can use the index.
+
+BEGIN;
+
+CREATE INDEX idx_users_identity ON users(lower(trim(last_name)), date_of_birth, upper(replace(postcode, ' ', '')));
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- ============================================================================
+-- Verify that users own the email address they registered with
+-- ============================================================================
+-- For databases created before email verification existed; fresh databases
+-- get the column and table from database_schema.sql. Accounts that already
+-- exist are marked verified, so turning on REQUIRE_EMAIL_VERIFICATION
+-- doesn't lock out users who were never sent a verification link.
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE users SET email_verified = true;
+
+COMMENT ON COLUMN users.email_verified IS 'Whether the user proved they own the email by following a verification link';
+
+CREATE TABLE email_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE email_verifications IS 'Outstanding email verification token (SHA-256 hashed) per user; resending replaces it and verifying deletes it';
+
+COMMIT;